@@ -103,9 +103,9 @@ func NewIchainApp(logger log.Logger, db dbm.DB, traceStore io.Writer, baseAppOpt
 
 	// add handlers
 	app.bankKeeper = bank.NewKeeper(app.accountMapper)
-	app.assetKeeper = asset.NewKeeper(app.keyAsset, cdc)
 	app.paramsKeeper = params.NewKeeper(app.cdc, app.keyParams)
 	app.identityKeeper = identity.NewKeeper(app.keyIdentity, cdc)
+	app.assetKeeper = asset.NewKeeper(app.keyAsset, cdc, app.paramsKeeper.Setter(), app.identityKeeper)
 	app.ibcMapper = ibc.NewMapper(cdc, app.keyIBC, ibc.DefaultCodespace)
 	app.stakeKeeper = stake.NewKeeper(app.cdc, app.keyStake, app.bankKeeper, app.RegisterCodespace(stake.DefaultCodespace))
 	app.slashingKeeper = slashing.NewKeeper(app.cdc, app.keySlashing, app.stakeKeeper, app.paramsKeeper.Getter(), app.RegisterCodespace(slashing.DefaultCodespace))
@@ -209,6 +209,11 @@ func (app *IchainApp) initChainer(ctx sdk.Context, req abci.RequestInitChain) ab
 
 	gov.InitGenesis(ctx, app.govKeeper, gov.DefaultGenesisState())
 
+	// load the asset policies
+	if err := asset.InitGenesis(ctx, app.assetKeeper, genesisState.AssetData); err != nil {
+		panic(err)
+	}
+
 	return abci.ResponseInitChain{
 		Validators: validators,
 	}
@@ -230,6 +235,7 @@ func (app *IchainApp) ExportAppStateAndValidators() (appState json.RawMessage, v
 	genState := types.GenesisState{
 		Accounts:  accounts,
 		StakeData: stake.WriteGenesis(ctx, app.stakeKeeper),
+		AssetData: asset.WriteGenesis(ctx, app.assetKeeper),
 	}
 	appState, err = wire.MarshalJSONIndent(app.cdc, genState)
 	if err != nil {
@@ -7,6 +7,7 @@ import (
 	"github.com/cosmos/cosmos-sdk/wire"
 	"github.com/cosmos/cosmos-sdk/x/stake"
 	"github.com/icheckteam/ichain/types"
+	"github.com/icheckteam/ichain/x/asset"
 	"github.com/stretchr/testify/require"
 	"github.com/tendermint/tendermint/libs/db"
 	"github.com/tendermint/tendermint/libs/log"
@@ -23,6 +24,7 @@ func setGenesis(gapp *IchainApp, accs ...*types.AppAccount) error {
 	genesisState := types.GenesisState{
 		Accounts:  genaccs,
 		StakeData: stake.DefaultGenesisState(),
+		AssetData: asset.DefaultGenesisState(),
 	}
 
 	stateBytes, err := wire.MarshalJSONIndent(gapp.cdc, genesisState)
@@ -13,6 +13,7 @@ import (
 	"github.com/cosmos/cosmos-sdk/x/auth"
 	"github.com/cosmos/cosmos-sdk/x/stake"
 	"github.com/icheckteam/ichain/types"
+	"github.com/icheckteam/ichain/x/asset"
 
 	"github.com/spf13/pflag"
 
@@ -185,6 +186,7 @@ func GaiaAppGenState(cdc *wire.Codec, appGenTxs []json.RawMessage) (genesisState
 	genesisState = types.GenesisState{
 		Accounts:  genaccs,
 		StakeData: stakeData,
+		AssetData: asset.DefaultGenesisState(),
 	}
 	return
 }
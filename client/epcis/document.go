@@ -0,0 +1,131 @@
+package epcis
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+// Event types defined by the EPCIS 1.2 standard
+const (
+	ObjectEvent         = "ObjectEvent"
+	AggregationEvent    = "AggregationEvent"
+	TransactionEvent    = "TransactionEvent"
+	TransformationEvent = "TransformationEvent"
+)
+
+// Event actions defined by the EPCIS 1.2 standard
+const (
+	ActionAdd     = "ADD"
+	ActionObserve = "OBSERVE"
+	ActionDelete  = "DELETE"
+)
+
+// Document is an EPCIS 1.2 XML document
+type Document struct {
+	XMLName       xml.Name  `xml:"EPCISDocument"`
+	SchemaVersion string    `xml:"schemaVersion,attr"`
+	CreationDate  string    `xml:"creationDate,attr"`
+	Events        EventList `xml:"EPCISBody>EventList"`
+}
+
+// QuantityElement is an entry of a quantity list
+type QuantityElement struct {
+	EPCClass string `xml:"epcClass"`
+	Quantity string `xml:"quantity"`
+	UOM      string `xml:"uom"`
+}
+
+// BizTransaction is a business transaction the event is part of
+type BizTransaction struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+// ILMDField is a single instance/lot master data field
+type ILMDField struct {
+	XMLName xml.Name
+	Value   string `xml:",chardata"`
+}
+
+// Name returns the local name of the field
+func (f ILMDField) Name() string {
+	return f.XMLName.Local
+}
+
+type ilmd struct {
+	Fields []ILMDField `xml:",any"`
+}
+
+// Event is the flattened form of any EPCIS event
+type Event struct {
+	Type                string            `xml:"-"`
+	EventTime           string            `xml:"eventTime"`
+	EventTimeZoneOffset string            `xml:"eventTimeZoneOffset"`
+	Action              string            `xml:"action"`
+	ParentID            string            `xml:"parentID"`
+	EPCList             []string          `xml:"epcList>epc"`
+	ChildEPCs           []string          `xml:"childEPCs>epc"`
+	QuantityList        []QuantityElement `xml:"extension>quantityList>quantityElement"`
+	ChildQuantityList   []QuantityElement `xml:"extension>childQuantityList>quantityElement"`
+	InputEPCList        []string          `xml:"inputEPCList>epc"`
+	InputQuantityList   []QuantityElement `xml:"inputQuantityList>quantityElement"`
+	OutputEPCList       []string          `xml:"outputEPCList>epc"`
+	OutputQuantityList  []QuantityElement `xml:"outputQuantityList>quantityElement"`
+	TransformationID    string            `xml:"transformationID"`
+	BizStep             string            `xml:"bizStep"`
+	Disposition         string            `xml:"disposition"`
+	ReadPoint           string            `xml:"readPoint>id"`
+	BizLocation         string            `xml:"bizLocation>id"`
+	BizTransactions     []BizTransaction  `xml:"bizTransactionList>bizTransaction"`
+	ExtILMD             ilmd              `xml:"extension>ilmd"`
+	TransformationILMD  ilmd              `xml:"ilmd"`
+}
+
+// ILMD returns the instance/lot master data of the event
+func (e Event) ILMD() []ILMDField {
+	return append(e.ExtILMD.Fields, e.TransformationILMD.Fields...)
+}
+
+// EventList keeps the events in document order, whatever their type
+type EventList []Event
+
+// UnmarshalXML implements xml.Unmarshaler
+func (l *EventList) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case ObjectEvent, AggregationEvent, TransactionEvent, TransformationEvent:
+				var ev Event
+				if err := d.DecodeElement(&ev, &t); err != nil {
+					return err
+				}
+				ev.Type = t.Name.Local
+				ev.Action = strings.ToUpper(strings.TrimSpace(ev.Action))
+				*l = append(*l, ev)
+			case "extension":
+				// EPCIS 1.1+ wraps newer event types in an extension element
+				if err := l.UnmarshalXML(d, t); err != nil {
+					return err
+				}
+			default:
+				if err := d.Skip(); err != nil {
+					return err
+				}
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// ParseDocument decodes an EPCIS 1.2 XML document
+func ParseDocument(r io.Reader) (doc Document, err error) {
+	err = xml.NewDecoder(r).Decode(&doc)
+	return
+}
@@ -0,0 +1,153 @@
+package epcis
+
+import (
+	"strings"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/icheckteam/ichain/x/asset"
+)
+
+var sender = sdk.AccAddress([]byte("addr1"))
+
+const testDocument = `<?xml version="1.0" encoding="UTF-8"?>
+<epcis:EPCISDocument xmlns:epcis="urn:epcglobal:epcis:xsd:1" xmlns:example="http://ns.example.com/epcis" schemaVersion="1.2" creationDate="2018-06-12T06:31:32Z">
+  <EPCISBody>
+    <EventList>
+      <ObjectEvent>
+        <eventTime>2018-06-12T06:31:32Z</eventTime>
+        <eventTimeZoneOffset>+07:00</eventTimeZoneOffset>
+        <epcList/>
+        <action>ADD</action>
+        <bizStep>urn:epcglobal:cbv:bizstep:commissioning</bizStep>
+        <readPoint><id>urn:epc:id:sgln:0614141.07346.1234</id></readPoint>
+        <extension>
+          <quantityList>
+            <quantityElement>
+              <epcClass>urn:epc:class:lgtin:4012345.012345.998877</epcClass>
+              <quantity>200</quantity>
+              <uom>KGM</uom>
+            </quantityElement>
+          </quantityList>
+          <ilmd>
+            <example:bestBeforeDate>2018-09-12</example:bestBeforeDate>
+          </ilmd>
+        </extension>
+      </ObjectEvent>
+      <AggregationEvent>
+        <eventTime>2018-06-12T07:00:00Z</eventTime>
+        <parentID>urn:epc:id:sscc:0614141.1234567890</parentID>
+        <childEPCs>
+          <epc>urn:epc:id:sgtin:0614141.107346.2017</epc>
+          <epc>urn:epc:id:sgtin:0614141.107346.2018</epc>
+        </childEPCs>
+        <action>ADD</action>
+        <bizStep>urn:epcglobal:cbv:bizstep:packing</bizStep>
+      </AggregationEvent>
+      <extension>
+        <TransformationEvent>
+          <eventTime>2018-06-12T08:00:00Z</eventTime>
+          <inputQuantityList>
+            <quantityElement>
+              <epcClass>urn:epc:class:lgtin:4012345.012345.998877</epcClass>
+              <quantity>50</quantity>
+            </quantityElement>
+          </inputQuantityList>
+          <outputEPCList>
+            <epc>urn:epc:id:sgtin:4012345.077889.25</epc>
+          </outputEPCList>
+          <transformationID>urn:epc:id:gdti:0614141.12345.400</transformationID>
+        </TransformationEvent>
+      </extension>
+      <ObjectEvent>
+        <eventTime>2018-06-12T09:00:00Z</eventTime>
+        <epcList><epc>urn:epc:id:sgtin:4012345.077889.25</epc></epcList>
+        <action>OBSERVE</action>
+        <bizStep>urn:epcglobal:cbv:bizstep:shipping</bizStep>
+        <bizTransactionList>
+          <bizTransaction type="urn:epcglobal:cbv:btt:po">urn:epcglobal:cbv:bt:0614141073467:1152</bizTransaction>
+        </bizTransactionList>
+      </ObjectEvent>
+    </EventList>
+  </EPCISBody>
+</epcis:EPCISDocument>`
+
+func TestParseDocument(t *testing.T) {
+	doc, err := ParseDocument(strings.NewReader(testDocument))
+	require.Nil(t, err)
+	assert.Equal(t, "1.2", doc.SchemaVersion)
+	require.Equal(t, 4, len(doc.Events))
+
+	// document order is kept across event types
+	assert.Equal(t, ObjectEvent, doc.Events[0].Type)
+	assert.Equal(t, AggregationEvent, doc.Events[1].Type)
+	assert.Equal(t, TransformationEvent, doc.Events[2].Type)
+	assert.Equal(t, ObjectEvent, doc.Events[3].Type)
+
+	ev := doc.Events[0]
+	assert.Equal(t, ActionAdd, ev.Action)
+	assert.Equal(t, "urn:epc:id:sgln:0614141.07346.1234", ev.ReadPoint)
+	require.Equal(t, 1, len(ev.QuantityList))
+	assert.Equal(t, "200", ev.QuantityList[0].Quantity)
+	require.Equal(t, 1, len(ev.ILMD()))
+	assert.Equal(t, "bestBeforeDate", ev.ILMD()[0].Name())
+
+	assert.Equal(t, 2, len(doc.Events[1].ChildEPCs))
+	assert.Equal(t, 1, len(doc.Events[2].InputQuantityList))
+	assert.Equal(t, 1, len(doc.Events[3].BizTransactions))
+}
+
+func TestMapEvents(t *testing.T) {
+	doc, err := ParseDocument(strings.NewReader(testDocument))
+	require.Nil(t, err)
+	m := NewMapper(sender)
+
+	msgs, err := m.Map(doc.Events[0])
+	require.Nil(t, err)
+	require.Equal(t, 1, len(msgs))
+	create := msgs[0].(asset.MsgCreateAsset)
+	assert.Equal(t, "urn:epc:class:lgtin:4012345.012345.998877", create.AssetID)
+	assert.Equal(t, sdk.NewInt(200), create.Quantity)
+
+	msgs, err = m.Map(doc.Events[1])
+	require.Nil(t, err)
+	require.Equal(t, 3, len(msgs))
+	assert.Equal(t, "urn:epc:id:sscc:0614141.1234567890", msgs[0].(asset.MsgCreateAsset).AssetID)
+
+	msgs, err = m.Map(doc.Events[2])
+	require.Nil(t, err)
+	require.Equal(t, 2, len(msgs))
+	materials := msgs[1].(asset.MsgAddMaterials)
+	assert.Equal(t, "urn:epc:id:sgtin:4012345.077889.25", materials.AssetID)
+	assert.Equal(t, sdk.NewInt(50), materials.Amount[0].Amount)
+
+	msgs, err = m.Map(doc.Events[3])
+	require.Nil(t, err)
+	require.Equal(t, 1, len(msgs))
+	_, ok := msgs[0].(asset.MsgUpdateProperties)
+	assert.True(t, ok)
+
+	// fractional quantities can not be represented by an asset
+	_, err = m.Map(Event{Type: ObjectEvent, Action: ActionAdd, QuantityList: []QuantityElement{{EPCClass: "a", Quantity: "1.5"}}})
+	assert.NotNil(t, err)
+}
+
+func TestMakeBatches(t *testing.T) {
+	doc, err := ParseDocument(strings.NewReader(testDocument))
+	require.Nil(t, err)
+
+	batches, err := MakeBatches(NewMapper(sender), doc.Events, 3)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(batches))
+	assert.Equal(t, 0, batches[0].From)
+	assert.Equal(t, 3, batches[0].To)
+	assert.Equal(t, 6, len(batches[0].Msgs))
+	assert.Equal(t, 3, batches[1].From)
+	assert.Equal(t, 4, batches[1].To)
+
+	assert.Equal(t, 1, len(pendingBatches(batches, 3)))
+	assert.Equal(t, 0, len(pendingBatches(batches, 4)))
+}
@@ -0,0 +1,292 @@
+package epcis
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+
+	"github.com/icheckteam/ichain/client/tx"
+	"github.com/icheckteam/ichain/x/asset"
+)
+
+// EPCIS 2.0 JSON-LD vocabulary
+const (
+	ContextEPCIS  = "https://ref.gs1.org/standards/epcis/2.0.0/epcis-context.jsonld"
+	ContextIchain = "https://ichain.io/epcis/"
+
+	BizStepCommissioning   = "commissioning"
+	BizStepDecommissioning = "decommissioning"
+	BizStepTransforming    = "transforming"
+	BizStepInspecting      = "inspecting"
+	BizStepAccepting       = "accepting"
+
+	// PropEPC is the property holding the registered EPC alias of an asset
+	PropEPC = "epc"
+
+	assetURNPrefix = "urn:ichain:asset:"
+)
+
+// JSONDocument is an EPCIS 2.0 JSON-LD document
+type JSONDocument struct {
+	Context       []interface{} `json:"@context"`
+	Type          string        `json:"type"`
+	SchemaVersion string        `json:"schemaVersion"`
+	CreationDate  string        `json:"creationDate"`
+	EPCISBody     JSONBody      `json:"epcisBody"`
+}
+
+// JSONBody ...
+type JSONBody struct {
+	EventList []JSONEvent `json:"eventList"`
+}
+
+// JSONQuantity ...
+type JSONQuantity struct {
+	EPCClass string      `json:"epcClass"`
+	Quantity json.Number `json:"quantity"` // an sdk.Int may not fit in an int64
+	UOM      string      `json:"uom,omitempty"`
+}
+
+// JSONParty is a source or destination of an event
+type JSONParty struct {
+	Type        string `json:"type"`
+	Source      string `json:"source,omitempty"`
+	Destination string `json:"destination,omitempty"`
+}
+
+// JSONProperty is an ichain property carried as a user extension
+type JSONProperty struct {
+	Name  string      `json:"name"`
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+// JSONEvent is an EPCIS 2.0 event
+type JSONEvent struct {
+	Type                string         `json:"type"`
+	EventID             string         `json:"eventID"`
+	EventTime           string         `json:"eventTime"`
+	EventTimeZoneOffset string         `json:"eventTimeZoneOffset"`
+	Action              string         `json:"action,omitempty"`
+	BizStep             string         `json:"bizStep,omitempty"`
+	EPCList             []string       `json:"epcList,omitempty"`
+	QuantityList        []JSONQuantity `json:"quantityList,omitempty"`
+	InputQuantityList   []JSONQuantity `json:"inputQuantityList,omitempty"`
+	OutputQuantityList  []JSONQuantity `json:"outputQuantityList,omitempty"`
+	SourceList          []JSONParty    `json:"sourceList,omitempty"`
+	DestinationList     []JSONParty    `json:"destinationList,omitempty"`
+	Properties          []JSONProperty `json:"ichain:properties,omitempty"`
+	Actor               string         `json:"ichain:actor,omitempty"`
+	Memo                string         `json:"ichain:memo,omitempty"`
+}
+
+// AliasResolver returns the registered EPC alias of an asset, if any
+type AliasResolver func(assetID string) (string, error)
+
+// Exporter renders the history of an asset as EPCIS events
+type Exporter struct {
+	resolve AliasResolver
+	epcs    map[string]string
+	err     error
+}
+
+// NewExporter ...
+func NewExporter(resolve AliasResolver) *Exporter {
+	return &Exporter{
+		resolve: resolve,
+		epcs:    map[string]string{},
+	}
+}
+
+// EPC returns the EPC of the asset, its registered alias if it has one. An
+// asset id that already is a URI is used as is, other ids are prefixed.
+func (e *Exporter) EPC(assetID string) string {
+	if epc, ok := e.epcs[assetID]; ok {
+		return epc
+	}
+	epc := deriveEPC(assetID)
+	if e.resolve != nil {
+		alias, err := e.resolve(assetID)
+		if err != nil && e.err == nil {
+			e.err = err
+		}
+		if alias != "" {
+			epc = alias
+		}
+	}
+	e.epcs[assetID] = epc
+	return epc
+}
+
+func deriveEPC(assetID string) string {
+	if strings.HasPrefix(assetID, "urn:") || strings.HasPrefix(assetID, "http://") || strings.HasPrefix(assetID, "https://") {
+		return assetID
+	}
+	return assetURNPrefix + url.PathEscape(assetID)
+}
+
+// AliasOf returns the registered EPC alias found in the properties
+func AliasOf(props asset.Properties) string {
+	for _, p := range props {
+		if p.Name == PropEPC && p.Type == asset.PropertyTypeString {
+			return p.StringValue
+		}
+	}
+	return ""
+}
+
+// Export renders the transactions touching an asset as an EPCIS 2.0 document,
+// failed and duplicated transactions are left out.
+func (e *Exporter) Export(infos []tx.TxInfo, now time.Time) (JSONDocument, error) {
+	events := []JSONEvent{}
+	sorted := make([]tx.TxInfo, len(infos))
+	copy(sorted, infos)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Height < sorted[j].Height })
+
+	var owner sdk.AccAddress
+	seen := map[string]bool{}
+	for _, info := range sorted {
+		if !info.Result.IsOK() || seen[info.Hash.String()] {
+			continue
+		}
+		seen[info.Hash.String()] = true
+		stdTx, _ := info.Tx.(auth.StdTx)
+		for i, msg := range info.Tx.GetMsgs() {
+			ev, ok := e.event(msg, &owner)
+			if !ok {
+				continue
+			}
+			ev.EventID = fmt.Sprintf("urn:ichain:tx:%s:%d", info.Hash.String(), i)
+			ev.EventTime = time.Unix(info.Time, 0).UTC().Format(time.RFC3339)
+			ev.EventTimeZoneOffset = "+00:00"
+			ev.Memo = stdTx.Memo
+			events = append(events, ev)
+		}
+	}
+
+	if e.err != nil {
+		return JSONDocument{}, e.err
+	}
+	return JSONDocument{
+		Context:       []interface{}{ContextEPCIS, map[string]string{"ichain": ContextIchain}},
+		Type:          "EPCISDocument",
+		SchemaVersion: "2.0",
+		CreationDate:  now.UTC().Format(time.RFC3339),
+		EPCISBody:     JSONBody{EventList: events},
+	}, nil
+}
+
+func (e *Exporter) event(msg sdk.Msg, owner *sdk.AccAddress) (ev JSONEvent, ok bool) {
+	switch msg := msg.(type) {
+	case asset.MsgCreateAsset:
+		*owner = msg.Sender
+		if len(msg.Owner) > 0 {
+			*owner = msg.Owner
+		}
+		if msg.Parent != "" {
+			// a split moves quantity from the parent into a new asset
+			return JSONEvent{
+				Type:               "TransformationEvent",
+				BizStep:            BizStepTransforming,
+				InputQuantityList:  []JSONQuantity{e.quantity(msg.Parent, msg.Quantity)},
+				OutputQuantityList: []JSONQuantity{e.quantity(msg.AssetID, msg.Quantity)},
+				Properties:         toJSONProperties(msg.Properties),
+				Actor:              msg.Sender.String(),
+			}, true
+		}
+		return JSONEvent{
+			Type:         "ObjectEvent",
+			Action:       ActionAdd,
+			BizStep:      BizStepCommissioning,
+			QuantityList: []JSONQuantity{e.quantity(msg.AssetID, msg.Quantity)},
+			Properties:   toJSONProperties(msg.Properties),
+			Actor:        msg.Sender.String(),
+		}, true
+	case asset.MsgAddQuantity:
+		return JSONEvent{
+			Type:         "ObjectEvent",
+			Action:       ActionAdd,
+			BizStep:      BizStepCommissioning,
+			QuantityList: []JSONQuantity{e.quantity(msg.AssetID, msg.Quantity)},
+			Actor:        msg.Sender.String(),
+		}, true
+	case asset.MsgSubtractQuantity:
+		return JSONEvent{
+			Type:         "ObjectEvent",
+			Action:       ActionDelete,
+			BizStep:      BizStepDecommissioning,
+			QuantityList: []JSONQuantity{e.quantity(msg.AssetID, msg.Quantity)},
+			Actor:        msg.Sender.String(),
+		}, true
+	case asset.MsgAddMaterials:
+		inputs := make([]JSONQuantity, len(msg.Amount))
+		for i, m := range msg.Amount {
+			inputs[i] = e.quantity(m.RecordID, m.Amount)
+		}
+		return JSONEvent{
+			Type:               "TransformationEvent",
+			BizStep:            BizStepTransforming,
+			InputQuantityList:  inputs,
+			OutputQuantityList: []JSONQuantity{{EPCClass: e.EPC(msg.AssetID)}},
+			Actor:              msg.Sender.String(),
+		}, true
+	case asset.MsgUpdateProperties:
+		return JSONEvent{
+			Type:       "ObjectEvent",
+			Action:     ActionObserve,
+			BizStep:    BizStepInspecting,
+			EPCList:    []string{e.EPC(msg.AssetID)},
+			Properties: toJSONProperties(msg.Properties),
+			Actor:      msg.Sender.String(),
+		}, true
+	case asset.MsgAnswerProposal:
+		if msg.Role != asset.RoleOwner || msg.Response != asset.StatusAccepted {
+			return
+		}
+		ev = JSONEvent{
+			Type:            "ObjectEvent",
+			Action:          ActionObserve,
+			BizStep:         BizStepAccepting,
+			EPCList:         []string{e.EPC(msg.AssetID)},
+			DestinationList: []JSONParty{{Type: "owning_party", Destination: msg.Recipient.String()}},
+			Actor:           msg.Recipient.String(),
+		}
+		if len(*owner) > 0 {
+			ev.SourceList = []JSONParty{{Type: "owning_party", Source: owner.String()}}
+		}
+		*owner = msg.Recipient
+		return ev, true
+	case asset.MsgFinalize:
+		return JSONEvent{
+			Type:    "ObjectEvent",
+			Action:  ActionDelete,
+			BizStep: BizStepDecommissioning,
+			EPCList: []string{e.EPC(msg.AssetID)},
+			Actor:   msg.Sender.String(),
+		}, true
+	default:
+		return
+	}
+}
+
+func (e *Exporter) quantity(assetID string, quantity sdk.Int) JSONQuantity {
+	return JSONQuantity{EPCClass: e.EPC(assetID), Quantity: json.Number(quantity.String())}
+}
+
+func toJSONProperties(props asset.Properties) []JSONProperty {
+	out := make([]JSONProperty, len(props))
+	for i, p := range props {
+		out[i] = JSONProperty{
+			Name:  p.Name,
+			Type:  asset.PropertyTypeToString(p.Type),
+			Value: p.GetValue(),
+		}
+	}
+	return out
+}
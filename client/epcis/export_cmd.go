@@ -0,0 +1,85 @@
+package epcis
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/spf13/cobra"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/cosmos/cosmos-sdk/wire"
+
+	assetclient "github.com/icheckteam/ichain/x/asset/client"
+)
+
+// ExportCmd prints the history of an asset as an EPCIS 2.0 JSON-LD document
+func ExportCmd(cdc *wire.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "export [asset-id]",
+		Short: "Export the history of an asset as an EPCIS 2.0 JSON-LD document",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCLIContext().WithCodec(cdc)
+			doc, err := exportAsset(ctx, cdc, args[0])
+			if err != nil {
+				return err
+			}
+			output, err := json.MarshalIndent(doc, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(output))
+			return nil
+		},
+	}
+}
+
+// RegisterRoutes registers the EPCIS REST routes
+func RegisterRoutes(ctx context.CLIContext, r *mux.Router, cdc *wire.Codec) {
+	r.HandleFunc("/assets/{id}/epcis", exportAssetHandlerFn(ctx, cdc)).Methods("GET")
+}
+
+func exportAssetHandlerFn(ctx context.CLIContext, cdc *wire.Codec) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		if _, err := assetclient.GetRecord(ctx, vars["id"], cdc); err != nil {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(fmt.Sprintf("Couldn't decode asset. Error: %s", err.Error())))
+			return
+		}
+		doc, err := exportAsset(ctx, cdc, vars["id"])
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		output, err := json.Marshal(doc)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(fmt.Sprintf("Couldn't encode document. Error: %s", err.Error())))
+			return
+		}
+		w.Header().Set("Content-Type", "application/ld+json")
+		w.Write(output)
+	}
+}
+
+// exportAsset renders the asset history, the EPC of every referenced asset is
+// its registered alias when it has one
+func exportAsset(ctx context.CLIContext, cdc *wire.Codec, assetID string) (JSONDocument, error) {
+	infos, err := assetclient.QueryAssetTxs(ctx, assetID, cdc, 0)
+	if err != nil {
+		return JSONDocument{}, err
+	}
+	e := NewExporter(func(id string) (string, error) {
+		props, err := assetclient.GetProperties(ctx, id, cdc)
+		if err != nil {
+			return "", err
+		}
+		return AliasOf(props), nil
+	})
+	return e.Export(infos, time.Now())
+}
@@ -0,0 +1,97 @@
+package epcis
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/icheckteam/ichain/client/tx"
+	"github.com/icheckteam/ichain/x/asset"
+)
+
+func testTxInfo(hash string, height int64, msgs ...sdk.Msg) tx.TxInfo {
+	return tx.TxInfo{
+		Hash:   []byte(hash),
+		Height: height,
+		Tx:     auth.NewStdTx(msgs, auth.StdFee{}, nil, "memo"),
+		Time:   height * 10,
+	}
+}
+
+func TestExporterEPC(t *testing.T) {
+	e := NewExporter(func(id string) (string, error) {
+		if id == "aliased" {
+			return "urn:epc:id:sgtin:0614141.107346.2017", nil
+		}
+		return "", nil
+	})
+	assert.Equal(t, "urn:epc:id:sgtin:0614141.107346.2017", e.EPC("aliased"))
+	assert.Equal(t, "urn:epc:class:lgtin:4012345.012345.998877", e.EPC("urn:epc:class:lgtin:4012345.012345.998877"))
+	assert.Equal(t, "urn:ichain:asset:asset%201", e.EPC("asset 1"))
+
+	e = NewExporter(func(id string) (string, error) {
+		return "", fmt.Errorf("not found")
+	})
+	_, err := e.Export([]tx.TxInfo{testTxInfo("a", 1, asset.MsgFinalize{Sender: sender, AssetID: "asset1"})}, time.Now())
+	assert.NotNil(t, err)
+}
+
+func TestExport(t *testing.T) {
+	recipient := sdk.AccAddress([]byte("addr2"))
+	infos := []tx.TxInfo{
+		testTxInfo("c", 3, asset.MsgFinalize{Sender: recipient, AssetID: "asset1"}),
+		testTxInfo("a", 1, asset.NewMsgCreateAsset(sender, "asset1", "asset1", sdk.NewInt(100), "")),
+		testTxInfo("b", 2, asset.MsgAnswerProposal{
+			AssetID:   "asset1",
+			Recipient: recipient,
+			Role:      asset.RoleOwner,
+			Response:  asset.StatusAccepted,
+		}),
+		// the same transaction is found through the asset and its parent
+		testTxInfo("b", 2, asset.MsgAnswerProposal{
+			AssetID:   "asset1",
+			Recipient: recipient,
+			Role:      asset.RoleOwner,
+			Response:  asset.StatusAccepted,
+		}),
+	}
+	failed := testTxInfo("d", 2, asset.MsgFinalize{Sender: sender, AssetID: "asset1"})
+	failed.Result.Code = 1
+	infos = append(infos, failed)
+
+	doc, err := NewExporter(nil).Export(infos, time.Now())
+	require.Nil(t, err)
+	assert.Equal(t, "2.0", doc.SchemaVersion)
+	events := doc.EPCISBody.EventList
+	require.Equal(t, 3, len(events))
+
+	assert.Equal(t, "ObjectEvent", events[0].Type)
+	assert.Equal(t, ActionAdd, events[0].Action)
+	assert.Equal(t, json.Number("100"), events[0].QuantityList[0].Quantity)
+	assert.Equal(t, "memo", events[0].Memo)
+
+	assert.Equal(t, BizStepAccepting, events[1].BizStep)
+	assert.Equal(t, sender.String(), events[1].SourceList[0].Source)
+	assert.Equal(t, recipient.String(), events[1].DestinationList[0].Destination)
+
+	assert.Equal(t, ActionDelete, events[2].Action)
+	assert.Equal(t, time.Unix(30, 0).UTC().Format(time.RFC3339), events[2].EventTime)
+}
+
+func TestExportLargeQuantity(t *testing.T) {
+	quantity, ok := sdk.NewIntFromString("100000000000000000000000")
+	require.True(t, ok)
+	infos := []tx.TxInfo{testTxInfo("a", 1, asset.NewMsgCreateAsset(sender, "asset1", "asset1", quantity, ""))}
+
+	doc, err := NewExporter(nil).Export(infos, time.Now())
+	require.Nil(t, err)
+	bz, err := json.Marshal(doc.EPCISBody.EventList[0].QuantityList[0])
+	require.Nil(t, err)
+	assert.Contains(t, string(bz), `"quantity":100000000000000000000000`)
+}
@@ -0,0 +1,220 @@
+package epcis
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/cosmos/cosmos-sdk/client/keys"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+	authcmd "github.com/cosmos/cosmos-sdk/x/auth/client/cli"
+	authctx "github.com/cosmos/cosmos-sdk/x/auth/client/context"
+)
+
+const (
+	flagBatchSize    = "batch-size"
+	flagDryRun       = "dry-run"
+	flagResume       = "resume"
+	flagProgressFile = "progress-file"
+)
+
+// Batch is a group of events submitted in a single transaction
+type Batch struct {
+	From int       `json:"from"` // index of the first event in the batch
+	To   int       `json:"to"`   // index after the last event in the batch
+	Msgs []sdk.Msg `json:"msgs"`
+}
+
+// Progress records how many events of a document have been committed
+type Progress struct {
+	Document string `json:"document"` // sha256 of the imported document
+	Events   int    `json:"events"`
+}
+
+// ImportCmd imports an EPCIS 1.2 XML document
+func ImportCmd(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import [file.xml]",
+		Short: "Import the events of an EPCIS 1.2 XML document as asset transactions",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			bz, err := ioutil.ReadFile(args[0])
+			if err != nil {
+				return err
+			}
+			doc, err := ParseDocument(bytes.NewReader(bz))
+			if err != nil {
+				return errors.Wrap(err, "couldn't parse EPCIS document")
+			}
+
+			txCtx := authctx.NewTxContextFromCLI().WithCodec(cdc)
+			cliCtx := context.NewCLIContext().
+				WithCodec(cdc).
+				WithAccountDecoder(authcmd.GetAccountDecoder(cdc))
+
+			from, err := cliCtx.GetFromAddress()
+			if err != nil {
+				return err
+			}
+
+			batchSize := viper.GetInt(flagBatchSize)
+			if batchSize <= 0 {
+				return fmt.Errorf("--%s must be positive", flagBatchSize)
+			}
+			batches, err := MakeBatches(NewMapper(from), doc.Events, batchSize)
+			if err != nil {
+				return err
+			}
+
+			progressFile := viper.GetString(flagProgressFile)
+			if progressFile == "" {
+				progressFile = args[0] + ".progress"
+			}
+			progress := Progress{Document: documentHash(bz)}
+			if viper.GetBool(flagResume) {
+				progress, err = loadProgress(progressFile, progress.Document)
+				if err != nil {
+					return err
+				}
+			}
+
+			if viper.GetBool(flagDryRun) {
+				output, err := wire.MarshalJSONIndent(cdc, pendingBatches(batches, progress.Events))
+				if err != nil {
+					return err
+				}
+				fmt.Println(string(output))
+				return nil
+			}
+
+			return submitBatches(txCtx, cliCtx, batches, progress, progressFile)
+		},
+	}
+
+	cmd.Flags().Int(flagBatchSize, 20, "Number of events submitted per transaction")
+	cmd.Flags().Bool(flagDryRun, false, "Print the mapped messages without submitting them")
+	cmd.Flags().Bool(flagResume, false, "Skip the events committed by a previous import of the same document")
+	cmd.Flags().String(flagProgressFile, "", "File tracking the committed events (defaults to <file>.progress)")
+	return cmd
+}
+
+// MakeBatches maps the events and groups them in batches of at most size
+// events, the messages of an event are never split across batches.
+func MakeBatches(m Mapper, events EventList, size int) ([]Batch, error) {
+	batches := []Batch{}
+	var current *Batch
+	for i, ev := range events {
+		msgs, err := m.Map(ev)
+		if err != nil {
+			return nil, errors.Wrapf(err, "event %d", i)
+		}
+		if current == nil || current.To-current.From >= size {
+			batches = append(batches, Batch{From: i, To: i})
+			current = &batches[len(batches)-1]
+		}
+		current.Msgs = append(current.Msgs, msgs...)
+		current.To = i + 1
+	}
+	return batches, nil
+}
+
+func pendingBatches(batches []Batch, done int) []Batch {
+	pending := []Batch{}
+	for _, b := range batches {
+		if b.To > done {
+			pending = append(pending, b)
+		}
+	}
+	return pending
+}
+
+func submitBatches(txCtx authctx.TxContext, cliCtx context.CLIContext, batches []Batch, progress Progress, progressFile string) error {
+	if err := cliCtx.EnsureAccountExists(); err != nil {
+		return err
+	}
+	from, err := cliCtx.GetFromAddress()
+	if err != nil {
+		return err
+	}
+	if txCtx.AccountNumber == 0 {
+		accnum, err := cliCtx.GetAccountNumber(from)
+		if err != nil {
+			return err
+		}
+		txCtx = txCtx.WithAccountNumber(accnum)
+	}
+	if txCtx.Sequence == 0 {
+		sequence, err := cliCtx.GetAccountSequence(from)
+		if err != nil {
+			return err
+		}
+		txCtx = txCtx.WithSequence(sequence)
+	}
+
+	passphrase, err := keys.GetPassphrase(cliCtx.FromAddressName)
+	if err != nil {
+		return err
+	}
+
+	for _, batch := range pendingBatches(batches, progress.Events) {
+		if batch.From < progress.Events {
+			return fmt.Errorf("progress stopped inside batch [%d, %d), use the same --%s as the failed import", batch.From, batch.To, flagBatchSize)
+		}
+		txBytes, err := txCtx.BuildAndSign(cliCtx.FromAddressName, passphrase, batch.Msgs)
+		if err != nil {
+			return err
+		}
+		res, err := cliCtx.BroadcastTx(txBytes)
+		if err != nil {
+			return errors.Wrapf(err, "events [%d, %d) failed, %d events committed, rerun with --%s", batch.From, batch.To, progress.Events, flagResume)
+		}
+		fmt.Printf("Committed events [%d, %d) at block %d (hash: %s)\n", batch.From, batch.To, res.Height, res.Hash.String())
+
+		progress.Events = batch.To
+		if err := saveProgress(progressFile, progress); err != nil {
+			return err
+		}
+		txCtx = txCtx.WithSequence(txCtx.Sequence + 1)
+	}
+	return nil
+}
+
+func documentHash(bz []byte) string {
+	hash := sha256.Sum256(bz)
+	return hex.EncodeToString(hash[:])
+}
+
+func loadProgress(file string, document string) (progress Progress, err error) {
+	bz, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return Progress{Document: document}, nil
+	}
+	if err != nil {
+		return
+	}
+	if err = json.Unmarshal(bz, &progress); err != nil {
+		return
+	}
+	if progress.Document != document {
+		err = fmt.Errorf("progress file %s belongs to another document", file)
+	}
+	return
+}
+
+func saveProgress(file string, progress Progress) error {
+	bz, err := json.Marshal(progress)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, bz, 0644)
+}
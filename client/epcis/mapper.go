@@ -0,0 +1,286 @@
+package epcis
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/icheckteam/ichain/x/asset"
+)
+
+// Property names written by the importer
+const (
+	PropEventTime        = "epcis_event_time"
+	PropBizStep          = "biz_step"
+	PropDisposition      = "disposition"
+	PropReadPoint        = "read_point"
+	PropBizLocation      = "biz_location"
+	PropBizTransactions  = "biz_transactions"
+	PropParentID         = "parent_id"
+	PropChildEPCs        = "child_epcs"
+	PropTransformationID = "transformation_id"
+	PropUnit             = "unit"
+)
+
+// Mapper maps EPCIS events onto asset messages sent by a single account
+type Mapper struct {
+	Sender sdk.AccAddress
+}
+
+// NewMapper ...
+func NewMapper(sender sdk.AccAddress) Mapper {
+	return Mapper{Sender: sender}
+}
+
+// object is an epc or an epc class with its quantity
+type object struct {
+	ID       string
+	Quantity sdk.Int
+	Unit     string
+}
+
+// Map returns the messages that replay the event on chain
+func (m Mapper) Map(ev Event) ([]sdk.Msg, error) {
+	var msgs []sdk.Msg
+	var err error
+	switch ev.Type {
+	case ObjectEvent:
+		msgs, err = m.mapObjectEvent(ev)
+	case AggregationEvent:
+		msgs, err = m.mapAggregationEvent(ev)
+	case TransactionEvent:
+		msgs, err = m.mapTransactionEvent(ev)
+	case TransformationEvent:
+		msgs, err = m.mapTransformationEvent(ev)
+	default:
+		return nil, fmt.Errorf("unsupported event type %s", ev.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, msg := range msgs {
+		if err := msg.ValidateBasic(); err != nil {
+			return nil, fmt.Errorf("%s: %s", ev.Type, err.Error())
+		}
+	}
+	return msgs, nil
+}
+
+func (m Mapper) mapObjectEvent(ev Event) ([]sdk.Msg, error) {
+	objects, err := toObjects(ev.EPCList, ev.QuantityList)
+	if err != nil {
+		return nil, err
+	}
+	msgs := make([]sdk.Msg, 0, len(objects))
+	switch ev.Action {
+	case ActionAdd:
+		props := append(contextProperties(ev), ilmdProperties(ev)...)
+		for _, o := range objects {
+			msgs = append(msgs, m.createAsset(o, props))
+		}
+	case ActionObserve:
+		for _, o := range objects {
+			msgs = append(msgs, m.updateProperties(o.ID, contextProperties(ev)))
+		}
+	case ActionDelete:
+		for _, o := range objects {
+			msgs = append(msgs, asset.MsgFinalize{Sender: m.Sender, AssetID: o.ID})
+		}
+	default:
+		return nil, fmt.Errorf("invalid action %s", ev.Action)
+	}
+	return msgs, nil
+}
+
+// mapAggregationEvent creates the parent on ADD and links the children to it
+// through the parent_id property, an aggregation does not consume the children.
+func (m Mapper) mapAggregationEvent(ev Event) ([]sdk.Msg, error) {
+	if ev.ParentID == "" {
+		return nil, fmt.Errorf("%s: missing parentID", ev.Type)
+	}
+	children, err := toObjects(ev.ChildEPCs, ev.ChildQuantityList)
+	if err != nil {
+		return nil, err
+	}
+	childIDs := make([]string, len(children))
+	for i, c := range children {
+		childIDs[i] = c.ID
+	}
+
+	msgs := []sdk.Msg{}
+	switch ev.Action {
+	case ActionAdd:
+		props := append(contextProperties(ev), stringsProperty(PropChildEPCs, childIDs))
+		msgs = append(msgs, m.createAsset(object{ID: ev.ParentID, Quantity: sdk.OneInt()}, props))
+		for _, id := range childIDs {
+			msgs = append(msgs, m.updateProperties(id, asset.Properties{stringProperty(PropParentID, ev.ParentID)}))
+		}
+	case ActionObserve:
+		msgs = append(msgs, m.updateProperties(ev.ParentID, contextProperties(ev)))
+	case ActionDelete:
+		for _, id := range childIDs {
+			msgs = append(msgs, m.updateProperties(id, asset.Properties{stringProperty(PropParentID, "")}))
+		}
+	default:
+		return nil, fmt.Errorf("invalid action %s", ev.Action)
+	}
+	return msgs, nil
+}
+
+func (m Mapper) mapTransactionEvent(ev Event) ([]sdk.Msg, error) {
+	objects, err := toObjects(ev.EPCList, ev.QuantityList)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(objects)+1)
+	if ev.ParentID != "" {
+		ids = append(ids, ev.ParentID)
+	}
+	for _, o := range objects {
+		ids = append(ids, o.ID)
+	}
+	msgs := make([]sdk.Msg, len(ids))
+	for i, id := range ids {
+		msgs[i] = m.updateProperties(id, contextProperties(ev))
+	}
+	return msgs, nil
+}
+
+// mapTransformationEvent creates the outputs and records every input as a
+// material of the first output.
+func (m Mapper) mapTransformationEvent(ev Event) ([]sdk.Msg, error) {
+	inputs, err := toObjects(ev.InputEPCList, ev.InputQuantityList)
+	if err != nil {
+		return nil, err
+	}
+	outputs, err := toObjects(ev.OutputEPCList, ev.OutputQuantityList)
+	if err != nil {
+		return nil, err
+	}
+	if len(outputs) == 0 {
+		return nil, fmt.Errorf("%s: no outputs", ev.Type)
+	}
+
+	props := append(contextProperties(ev), ilmdProperties(ev)...)
+	if ev.TransformationID != "" {
+		props = append(props, stringProperty(PropTransformationID, ev.TransformationID))
+	}
+	msgs := make([]sdk.Msg, 0, len(outputs)+1)
+	for _, o := range outputs {
+		msgs = append(msgs, m.createAsset(o, props))
+	}
+	if len(inputs) > 0 {
+		materials := make(asset.Materials, len(inputs))
+		for i, in := range inputs {
+			materials[i] = asset.Material{RecordID: in.ID, Amount: in.Quantity}
+		}
+		msgs = append(msgs, asset.MsgAddMaterials{
+			AssetID: outputs[0].ID,
+			Sender:  m.Sender,
+			Amount:  materials,
+		})
+	}
+	return msgs, nil
+}
+
+func (m Mapper) createAsset(o object, props asset.Properties) asset.MsgCreateAsset {
+	msg := asset.NewMsgCreateAsset(m.Sender, o.ID, o.ID, o.Quantity, "")
+	msg.Properties = append(asset.Properties{}, props...)
+	if o.Unit != "" {
+		msg.Properties = append(msg.Properties, stringProperty(PropUnit, o.Unit))
+	}
+	return msg
+}
+
+func (m Mapper) updateProperties(assetID string, props asset.Properties) asset.MsgUpdateProperties {
+	return asset.MsgUpdateProperties{
+		Sender:     m.Sender,
+		AssetID:    assetID,
+		Properties: props,
+	}
+}
+
+func toObjects(epcs []string, quantities []QuantityElement) ([]object, error) {
+	objects := make([]object, 0, len(epcs)+len(quantities))
+	for _, epc := range epcs {
+		epc = strings.TrimSpace(epc)
+		if epc == "" {
+			continue
+		}
+		objects = append(objects, object{ID: epc, Quantity: sdk.OneInt()})
+	}
+	for _, q := range quantities {
+		quantity, err := parseQuantity(q.Quantity)
+		if err != nil {
+			return nil, fmt.Errorf("epcClass %s: %s", q.EPCClass, err.Error())
+		}
+		objects = append(objects, object{
+			ID:       strings.TrimSpace(q.EPCClass),
+			Quantity: quantity,
+			Unit:     q.UOM,
+		})
+	}
+	return objects, nil
+}
+
+// parseQuantity accepts integral quantities only, assets can not hold fractions
+func parseQuantity(s string) (sdk.Int, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return sdk.OneInt(), nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return sdk.Int{}, fmt.Errorf("invalid quantity %s", s)
+	}
+	if f <= 0 || f != math.Trunc(f) || f > math.MaxInt64 {
+		return sdk.Int{}, fmt.Errorf("quantity %s is not a positive integer", s)
+	}
+	return sdk.NewInt(int64(f)), nil
+}
+
+func contextProperties(ev Event) asset.Properties {
+	props := asset.Properties{}
+	add := func(name, value string) {
+		if value = strings.TrimSpace(value); value != "" {
+			props = append(props, stringProperty(name, value))
+		}
+	}
+	add(PropEventTime, ev.EventTime)
+	add(PropBizStep, ev.BizStep)
+	add(PropDisposition, ev.Disposition)
+	add(PropReadPoint, ev.ReadPoint)
+	add(PropBizLocation, ev.BizLocation)
+	if len(ev.BizTransactions) > 0 {
+		values := make([]string, len(ev.BizTransactions))
+		for i, bt := range ev.BizTransactions {
+			values[i] = strings.TrimSpace(bt.Value)
+			if bt.Type != "" {
+				values[i] = bt.Type + "=" + values[i]
+			}
+		}
+		props = append(props, stringsProperty(PropBizTransactions, values))
+	}
+	return props
+}
+
+func ilmdProperties(ev Event) asset.Properties {
+	props := asset.Properties{}
+	for _, f := range ev.ILMD() {
+		if value := strings.TrimSpace(f.Value); value != "" {
+			props = append(props, stringProperty(f.Name(), value))
+		}
+	}
+	return props
+}
+
+func stringProperty(name, value string) asset.Property {
+	return asset.Property{Name: name, Type: asset.PropertyTypeString, StringValue: value}
+}
+
+func stringsProperty(name string, values []string) asset.Property {
+	return asset.Property{Name: name, Type: asset.PropertyTypeEnum, EnumValue: values}
+}
@@ -1,22 +1,32 @@
 package lcd
 
 import (
+	"bufio"
+	"bytes"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"net/http"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/cosmos/cosmos-sdk/client"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/icheckteam/ichain/client/session"
+	"github.com/icheckteam/ichain/client/signature"
+	"github.com/icheckteam/ichain/client/stream"
+	"github.com/icheckteam/ichain/client/tx"
+	"github.com/icheckteam/ichain/client/webhook"
 	"github.com/icheckteam/ichain/x/asset"
 	"github.com/icheckteam/ichain/x/identity"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/require"
-	ctypes "github.com/tendermint/tendermint/rpc/core/types"
 )
 
-func doCreateAsset(t *testing.T, port, seed, name, password string, addr sdk.AccAddress) (resultTx ctypes.ResultBroadcastTxCommit) {
+func doCreateAsset(t *testing.T, port, seed, name, password string, addr sdk.AccAddress) (resultTx tx.BroadcastOutput) {
 	acc := getAccount(t, port, addr)
 	accnum := acc.GetAccountNumber()
 	sequence := acc.GetSequence()
@@ -53,7 +63,7 @@ func doCreateAsset(t *testing.T, port, seed, name, password string, addr sdk.Acc
 
 }
 
-func doUpdateProperties(t *testing.T, port, seed, name, password string, addr sdk.AccAddress) (resultTx ctypes.ResultBroadcastTxCommit) {
+func doUpdateProperties(t *testing.T, port, seed, name, password string, addr sdk.AccAddress) (resultTx tx.BroadcastOutput) {
 	acc := getAccount(t, port, addr)
 	accnum := acc.GetAccountNumber()
 	sequence := acc.GetSequence()
@@ -94,7 +104,7 @@ func getTxsTransferMaterials(t *testing.T, port string) []asset.HistoryTransferM
 	return history
 }
 
-func doAddMaterials(t *testing.T, port, seed, name, password string, addr sdk.AccAddress) (resultTx ctypes.ResultBroadcastTxCommit) {
+func doAddMaterials(t *testing.T, port, seed, name, password string, addr sdk.AccAddress) (resultTx tx.BroadcastOutput) {
 	acc := getAccount(t, port, addr)
 	accnum := acc.GetAccountNumber()
 	sequence := acc.GetSequence()
@@ -124,7 +134,7 @@ func doAddMaterials(t *testing.T, port, seed, name, password string, addr sdk.Ac
 	return resultTx
 }
 
-func doAddQuantity(t *testing.T, port, seed, name, password string, addr sdk.AccAddress) (resultTx ctypes.ResultBroadcastTxCommit) {
+func doAddQuantity(t *testing.T, port, seed, name, password string, addr sdk.AccAddress) (resultTx tx.BroadcastOutput) {
 	acc := getAccount(t, port, addr)
 	accnum := acc.GetAccountNumber()
 	sequence := acc.GetSequence()
@@ -152,7 +162,7 @@ func doAddQuantity(t *testing.T, port, seed, name, password string, addr sdk.Acc
 	return resultTx
 }
 
-func doSubtractQuantity(t *testing.T, port, seed, name, password string, addr sdk.AccAddress) (resultTx ctypes.ResultBroadcastTxCommit) {
+func doSubtractQuantity(t *testing.T, port, seed, name, password string, addr sdk.AccAddress) (resultTx tx.BroadcastOutput) {
 	acc := getAccount(t, port, addr)
 	accnum := acc.GetAccountNumber()
 	sequence := acc.GetSequence()
@@ -180,7 +190,7 @@ func doSubtractQuantity(t *testing.T, port, seed, name, password string, addr sd
 	return resultTx
 }
 
-func doFinalizeAsset(t *testing.T, port, seed, name, password string, addr sdk.AccAddress) (resultTx ctypes.ResultBroadcastTxCommit) {
+func doFinalizeAsset(t *testing.T, port, seed, name, password string, addr sdk.AccAddress) (resultTx tx.BroadcastOutput) {
 	acc := getAccount(t, port, addr)
 	accnum := acc.GetAccountNumber()
 	sequence := acc.GetSequence()
@@ -207,7 +217,7 @@ func doFinalizeAsset(t *testing.T, port, seed, name, password string, addr sdk.A
 	return resultTx
 }
 
-func doCreateProposal(t *testing.T, port, seed, name, password string, addr, recipient sdk.AccAddress) (resultTx ctypes.ResultBroadcastTxCommit) {
+func doCreateProposal(t *testing.T, port, seed, name, password string, addr, recipient sdk.AccAddress) (resultTx tx.BroadcastOutput) {
 	acc := getAccount(t, port, addr)
 	accnum := acc.GetAccountNumber()
 	sequence := acc.GetSequence()
@@ -238,7 +248,7 @@ func doCreateProposal(t *testing.T, port, seed, name, password string, addr, rec
 	return resultTx
 }
 
-func doAnswerProposal(t *testing.T, port, seed, name, password string, addr, recipient sdk.AccAddress) (resultTx ctypes.ResultBroadcastTxCommit) {
+func doAnswerProposal(t *testing.T, port, seed, name, password string, addr, recipient sdk.AccAddress) (resultTx tx.BroadcastOutput) {
 	acc := getAccount(t, port, addr)
 	accnum := acc.GetAccountNumber()
 	sequence := acc.GetSequence()
@@ -267,7 +277,7 @@ func doAnswerProposal(t *testing.T, port, seed, name, password string, addr, rec
 	return resultTx
 }
 
-func doRevokeReporter(t *testing.T, port, seed, name, password string, addr, recipient sdk.AccAddress) (resultTx ctypes.ResultBroadcastTxCommit) {
+func doRevokeReporter(t *testing.T, port, seed, name, password string, addr, recipient sdk.AccAddress) (resultTx tx.BroadcastOutput) {
 	acc := getAccount(t, port, addr)
 	accnum := acc.GetAccountNumber()
 	sequence := acc.GetSequence()
@@ -354,7 +364,7 @@ func getRecordOwners(t *testing.T, port string) []asset.HistoryTransferOutput {
 	return owners
 }
 
-func doAddTrust(t *testing.T, port, seed, name, password string, addr, trusting sdk.AccAddress) (resultTx ctypes.ResultBroadcastTxCommit) {
+func doAddTrust(t *testing.T, port, seed, name, password string, addr, trusting sdk.AccAddress) (resultTx tx.BroadcastOutput) {
 	acc := getAccount(t, port, addr)
 	accnum := acc.GetAccountNumber()
 	sequence := acc.GetSequence()
@@ -382,7 +392,7 @@ func doAddTrust(t *testing.T, port, seed, name, password string, addr, trusting
 	return resultTx
 }
 
-func doAddCerts(t *testing.T, port, seed, name, password string, addr sdk.AccAddress) (resultTx ctypes.ResultBroadcastTxCommit) {
+func doAddCerts(t *testing.T, port, seed, name, password string, addr sdk.AccAddress) (resultTx tx.BroadcastOutput) {
 	acc := getAccount(t, port, addr)
 	accnum := acc.GetAccountNumber()
 	sequence := acc.GetSequence()
@@ -470,7 +480,7 @@ func getOwners(t *testing.T, port string, addr sdk.AccAddress) []sdk.AccAddress
 	return owners
 }
 
-func doRegisterIdentity(t *testing.T, port, seed, name, password string, addr sdk.AccAddress) (resultTx ctypes.ResultBroadcastTxCommit) {
+func doRegisterIdentity(t *testing.T, port, seed, name, password string, addr sdk.AccAddress) (resultTx tx.BroadcastOutput) {
 	acc := getAccount(t, port, addr)
 	accnum := acc.GetAccountNumber()
 	sequence := acc.GetSequence()
@@ -497,7 +507,7 @@ func doRegisterIdentity(t *testing.T, port, seed, name, password string, addr sd
 	return resultTx
 }
 
-func doAddOwner(t *testing.T, port, seed, name, password string, addr sdk.AccAddress) (resultTx ctypes.ResultBroadcastTxCommit) {
+func doAddOwner(t *testing.T, port, seed, name, password string, addr sdk.AccAddress) (resultTx tx.BroadcastOutput) {
 	acc := getAccount(t, port, addr)
 	accnum := acc.GetAccountNumber()
 	sequence := acc.GetSequence()
@@ -525,7 +535,7 @@ func doAddOwner(t *testing.T, port, seed, name, password string, addr sdk.AccAdd
 	return resultTx
 }
 
-func doDelOwner(t *testing.T, port, seed, name, password string, addr sdk.AccAddress) (resultTx ctypes.ResultBroadcastTxCommit) {
+func doDelOwner(t *testing.T, port, seed, name, password string, addr sdk.AccAddress) (resultTx tx.BroadcastOutput) {
 	acc := getAccount(t, port, addr)
 	accnum := acc.GetAccountNumber()
 	sequence := acc.GetSequence()
@@ -554,17 +564,264 @@ func doDelOwner(t *testing.T, port, seed, name, password string, addr sdk.AccAdd
 }
 
 func doAppSignAndVerify(t *testing.T, port string) {
+	res, body := Request(t, port, "POST", "/apps/challenge", []byte(`{"audience": "https://shop.example"}`))
+	require.Equal(t, http.StatusOK, res.StatusCode, body)
+	var challenge signature.Challenge
+	require.Nil(t, json.Unmarshal([]byte(body), &challenge))
+
 	jsonStr := []byte(fmt.Sprintf(`{
+		"webiste": "https://shop.example",
 		"name": "test",
 		"password": "1234567890",
-		"nonce": "7_tKYK2eRacnzPZDHDm7jxqLMRxFTPZ5KKZ_ZXdvuLU="
-	}`))
+		"nonce": "%s"
+	}`, challenge.Nonce))
 
-	res, body := Request(t, port, "POST", "/apps/sign", jsonStr)
+	res, body = Request(t, port, "POST", "/apps/sign", jsonStr)
 	require.Equal(t, http.StatusOK, res.StatusCode, body)
 
 	b, _ := base64.StdEncoding.DecodeString(body)
 
+	var output signature.VerifyOutput
 	res, body = Request(t, port, "POST", "/apps/verify", b)
 	require.Equal(t, http.StatusOK, res.StatusCode, body)
+	require.Nil(t, json.Unmarshal([]byte(body), &output))
+	require.True(t, output.Status, body)
+
+	// the same claim cannot be replayed
+	res, body = Request(t, port, "POST", "/apps/verify", b)
+	require.Equal(t, http.StatusOK, res.StatusCode, body)
+	require.Nil(t, json.Unmarshal([]byte(body), &output))
+	require.False(t, output.Status, body)
+}
+
+func doAppToken(t *testing.T, port string) {
+	res, body := Request(t, port, "POST", "/apps/challenge", []byte(`{"audience": "https://shop.example"}`))
+	require.Equal(t, http.StatusOK, res.StatusCode, body)
+	var challenge signature.Challenge
+	require.Nil(t, json.Unmarshal([]byte(body), &challenge))
+
+	res, body = Request(t, port, "POST", "/apps/sign", []byte(fmt.Sprintf(`{
+		"webiste": "https://shop.example",
+		"name": "test",
+		"password": "1234567890",
+		"nonce": "%s"
+	}`, challenge.Nonce)))
+	require.Equal(t, http.StatusOK, res.StatusCode, body)
+	claim, _ := base64.StdEncoding.DecodeString(body)
+
+	res, body = Request(t, port, "POST", "/apps/token", []byte(fmt.Sprintf(`{"claim": %s, "properties": ["organic"]}`, claim)))
+	require.Equal(t, http.StatusOK, res.StatusCode, body)
+	var output signature.TokenOutput
+	require.Nil(t, json.Unmarshal([]byte(body), &output))
+
+	res, body = Request(t, port, "GET", "/apps/jwks", nil)
+	require.Equal(t, http.StatusOK, res.StatusCode, body)
+	var jwks session.JWKS
+	require.Nil(t, json.Unmarshal([]byte(body), &jwks))
+
+	claims, err := session.Verify(output.IDToken, jwks, "https://lcd.example", "https://shop.example", time.Now())
+	require.Nil(t, err)
+	require.NotEmpty(t, claims.Subject)
+}
+
+func doGenerateAndBroadcast(t *testing.T, port, name, password string, addr sdk.AccAddress) (resultTx tx.BroadcastOutput) {
+	acc := getAccount(t, port, addr)
+	chainID := viper.GetString(client.FlagChainID)
+
+	jsonStr := []byte(fmt.Sprintf(`{
+		"base_req": {
+			"generate_only": true,
+			"from": "%s",
+			"account_number": "%d",
+			"sequence": "%d",
+			"gas": "10000",
+			"chain_id": "%s"
+		},
+		"name": "generated",
+		"asset_id": "generated",
+		"quantity": "100",
+		"unit": "kg"
+	}`, addr, acc.GetAccountNumber(), acc.GetSequence(), chainID))
+	res, body := Request(t, port, "POST", "/assets", jsonStr)
+	require.Equal(t, http.StatusOK, res.StatusCode, body)
+	var generated tx.GenerateOnlyOutput
+	require.Nil(t, cdc.UnmarshalJSON([]byte(body), &generated))
+	require.Empty(t, generated.Tx.GetSignatures())
+
+	// sign on the client side
+	sig, pubkey, err := GetKeyBase(t).Sign(name, password, []byte(generated.SignBytes))
+	require.Nil(t, err)
+	signed := generated.Tx
+	signed.Signatures = []auth.StdSignature{{
+		PubKey:        pubkey,
+		Signature:     sig,
+		AccountNumber: acc.GetAccountNumber(),
+		Sequence:      acc.GetSequence(),
+	}}
+
+	// an unsigned tx is refused
+	bz, err := cdc.MarshalJSON(tx.BroadcastSignedTxBody{Tx: generated.Tx})
+	require.Nil(t, err)
+	res, body = Request(t, port, "POST", "/txs", bz)
+	require.Equal(t, http.StatusBadRequest, res.StatusCode, body)
+
+	bz, err = cdc.MarshalJSON(tx.BroadcastSignedTxBody{Tx: signed})
+	require.Nil(t, err)
+	res, body = Request(t, port, "POST", "/txs", bz)
+	require.Equal(t, http.StatusOK, res.StatusCode, body)
+	require.Nil(t, cdc.UnmarshalJSON([]byte(body), &resultTx))
+	return resultTx
+}
+
+func doCreateAssetMode(t *testing.T, port, name, password, assetID, mode string, addr sdk.AccAddress) (resultTx tx.BroadcastOutput) {
+	acc := getAccount(t, port, addr)
+	chainID := viper.GetString(client.FlagChainID)
+
+	jsonStr := []byte(fmt.Sprintf(`{
+		"base_req": {
+			"name": "%s",
+			"password": "%s",
+			"account_number": "%d",
+			"sequence": "%d",
+			"gas": "10000",
+			"chain_id": "%s",
+			"mode": "%s"
+		},
+		"name": "%s",
+		"asset_id": "%s",
+		"quantity": "100",
+		"unit": "kg"
+	}`, name, password, acc.GetAccountNumber(), acc.GetSequence(), chainID, mode, assetID, assetID))
+	res, body := Request(t, port, "POST", "/assets", jsonStr)
+	require.Equal(t, http.StatusOK, res.StatusCode, body)
+	require.Nil(t, cdc.UnmarshalJSON([]byte(body), &resultTx))
+	return resultTx
+}
+
+// waitForTx polls the tx of the hash until it is in a block
+func waitForTx(t *testing.T, port, hash string) {
+	for i := 0; i < 20; i++ {
+		res, _ := Request(t, port, "GET", fmt.Sprintf("/txs/%s", hash), nil)
+		if res.StatusCode == http.StatusOK {
+			return
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+	t.Fatalf("tx %s not committed", hash)
+}
+
+func searchAssetTxs(t *testing.T, port, query string) ([]tx.TxInfo, string) {
+	res, body := Request(t, port, "GET", "/txs?"+query, nil)
+	require.Equal(t, http.StatusOK, res.StatusCode, body)
+	var txs []tx.TxInfo
+	require.Nil(t, cdc.UnmarshalJSON([]byte(body), &txs))
+	return txs, res.Header.Get("X-Total-Count")
+}
+
+// readAssetEvent reads the first event of the asset event stream
+func readAssetEvent(t *testing.T, port, query string) stream.Event {
+	res, err := http.Get(fmt.Sprintf("http://localhost:%v/events/assets?%s", port, query))
+	require.Nil(t, err)
+	defer res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+
+	scanner := bufio.NewScanner(res.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "data: ") {
+			var ev stream.Event
+			require.Nil(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &ev))
+			return ev
+		}
+	}
+	t.Fatal("the stream ended without events", scanner.Err())
+	return stream.Event{}
+}
+
+func readAssetEventAsync(t *testing.T, port, query string) <-chan stream.Event {
+	events := make(chan stream.Event, 1)
+	go func() {
+		events <- readAssetEvent(t, port, query)
+	}()
+	return events
+}
+
+func doSubscribeWebhook(t *testing.T, port, url string, filter stream.Filter) webhook.Subscription {
+	payload, err := json.Marshal(webhook.SubscribeBody{URL: url, Filter: filter})
+	require.Nil(t, err)
+	res, body := Request(t, port, "POST", "/webhooks", payload)
+	require.Equal(t, http.StatusUnauthorized, res.StatusCode, body)
+
+	req, err := http.NewRequest("POST", fmt.Sprintf("http://localhost:%v/webhooks", port), bytes.NewReader(payload))
+	require.Nil(t, err)
+	req.Header.Set("Authorization", "Bearer "+webhookToken)
+	res, err = http.DefaultClient.Do(req)
+	require.Nil(t, err)
+	defer res.Body.Close()
+	bz, err := ioutil.ReadAll(res.Body)
+	require.Nil(t, err)
+	body = string(bz)
+	require.Equal(t, http.StatusOK, res.StatusCode, body)
+	var sub webhook.Subscription
+	require.Nil(t, json.Unmarshal([]byte(body), &sub))
+	return sub
+}
+
+// webhookRequest makes a request on a subscription with its secret as
+// bearer token
+func webhookRequest(t *testing.T, port, method, path, secret string) (*http.Response, string) {
+	req, err := http.NewRequest(method, fmt.Sprintf("http://localhost:%v%v", port, path), nil)
+	require.Nil(t, err)
+	req.Header.Set("Authorization", "Bearer "+secret)
+	res, err := http.DefaultClient.Do(req)
+	require.Nil(t, err)
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+	require.Nil(t, err)
+	return res, string(body)
+}
+
+func getWebhookDeliveries(t *testing.T, port string, sub webhook.Subscription) []webhook.Attempt {
+	res, body := webhookRequest(t, port, "GET", fmt.Sprintf("/webhooks/%s/deliveries", sub.ID), sub.Secret)
+	require.Equal(t, http.StatusOK, res.StatusCode, body)
+	var attempts []webhook.Attempt
+	require.Nil(t, json.Unmarshal([]byte(body), &attempts))
+	return attempts
+}
+
+func doSetAssetProperties(t *testing.T, port, seed, name, password string, addr sdk.AccAddress, assetID, properties string) (resultTx tx.BroadcastOutput) {
+	acc := getAccount(t, port, addr)
+	chainID := viper.GetString(client.FlagChainID)
+	jsonStr := []byte(fmt.Sprintf(`{
+		"base_req": {
+			"name": "%s",
+			"password": "%s",
+			"account_number": "%d",
+			"sequence": "%d",
+			"gas": "10000",
+			"chain_id": "%s"
+		},
+		"properties": %s
+	}`, name, password, acc.GetAccountNumber(), acc.GetSequence(), chainID, properties))
+
+	res, body := Request(t, port, "POST", fmt.Sprintf("/assets/%s/properties", assetID), jsonStr)
+	require.Equal(t, http.StatusOK, res.StatusCode, body)
+	require.Nil(t, cdc.UnmarshalJSON([]byte(body), &resultTx))
+	return resultTx
+}
+
+// getPassport requests the passport of an asset with the headers
+func getPassport(t *testing.T, port, path string, header http.Header) (*http.Response, string) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("http://localhost:%v%v", port, path), nil)
+	require.Nil(t, err)
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	res, err := http.DefaultClient.Do(req)
+	require.Nil(t, err)
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+	require.Nil(t, err)
+	return res, string(body)
 }
@@ -2,9 +2,13 @@ package lcd
 
 import (
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"regexp"
+	"strconv"
 	"testing"
 	"time"
 
@@ -29,6 +33,10 @@ import (
 	"github.com/cosmos/cosmos-sdk/x/slashing"
 	"github.com/cosmos/cosmos-sdk/x/stake"
 	"github.com/cosmos/cosmos-sdk/x/stake/client/rest"
+
+	"github.com/icheckteam/ichain/client/stream"
+	"github.com/icheckteam/ichain/client/webhook"
+	assetclient "github.com/icheckteam/ichain/x/asset/client"
 )
 
 func init() {
@@ -1133,8 +1141,7 @@ func TestCreateAsset(t *testing.T) {
 	resultTx := doCreateAsset(t, port, seed, name, password, addr)
 	tests.WaitForHeight(resultTx.Height+1, port)
 
-	assert.Equal(t, uint32(0), resultTx.CheckTx.Code)
-	assert.Equal(t, uint32(0), resultTx.DeliverTx.Code)
+	assert.True(t, resultTx.IsOK(), resultTx.Log)
 
 	asset := getAsset(t, port, "test")
 	assert.Equal(t, asset.ID, "test")
@@ -1152,14 +1159,12 @@ func TestCreateAsset(t *testing.T) {
 	// UpdateProperties tests
 	resultTx = doUpdateProperties(t, port, seed, name, password, addr)
 	tests.WaitForHeight(resultTx.Height+1, port)
-	assert.Equal(t, uint32(0), resultTx.CheckTx.Code)
-	assert.Equal(t, uint32(0), resultTx.DeliverTx.Code)
+	assert.True(t, resultTx.IsOK(), resultTx.Log)
 
 	// AddMaterials Tests
 	resultTx = doAddMaterials(t, port, seed, name, password, addr)
 	tests.WaitForHeight(resultTx.Height+1, port)
-	assert.Equal(t, uint32(0), resultTx.CheckTx.Code)
-	assert.Equal(t, uint32(0), resultTx.DeliverTx.Code)
+	assert.True(t, resultTx.IsOK(), resultTx.Log)
 
 	txs := getTxsTransferMaterials(t, port)
 	assert.Equal(t, len(txs), 1)
@@ -1167,20 +1172,254 @@ func TestCreateAsset(t *testing.T) {
 	// AddQuantity
 	resultTx = doAddQuantity(t, port, seed, name, password, addr)
 	tests.WaitForHeight(resultTx.Height+1, port)
-	assert.Equal(t, uint32(0), resultTx.CheckTx.Code)
-	assert.Equal(t, uint32(0), resultTx.DeliverTx.Code)
+	assert.True(t, resultTx.IsOK(), resultTx.Log)
 
 	// SubtractQuantity
 	resultTx = doSubtractQuantity(t, port, seed, name, password, addr)
 	tests.WaitForHeight(resultTx.Height+1, port)
-	assert.Equal(t, uint32(0), resultTx.CheckTx.Code)
-	assert.Equal(t, uint32(0), resultTx.DeliverTx.Code)
+	assert.True(t, resultTx.IsOK(), resultTx.Log)
 
 	// doFinalizeAsset
 	resultTx = doFinalizeAsset(t, port, seed, name, password, addr)
 	tests.WaitForHeight(resultTx.Height+1, port)
-	assert.Equal(t, uint32(0), resultTx.CheckTx.Code)
-	assert.Equal(t, uint32(0), resultTx.DeliverTx.Code)
+	assert.True(t, resultTx.IsOK(), resultTx.Log)
+}
+
+func TestGenerateOnly(t *testing.T) {
+	name, password := "test", "1234567890"
+	addr, _ := CreateAddr(t, name, password, GetKeyBase(t))
+	cleanup, _, port := InitializeTestLCD(t, 1, []sdk.AccAddress{addr})
+	defer cleanup()
+
+	resultTx := doGenerateAndBroadcast(t, port, name, password, addr)
+	tests.WaitForHeight(resultTx.Height+1, port)
+	assert.True(t, resultTx.IsOK(), resultTx.Log)
+
+	asset := getAsset(t, port, "generated")
+	assert.Equal(t, asset.Owner, addr)
+}
+
+func TestBroadcastModes(t *testing.T) {
+	name, password := "test", "1234567890"
+	addr, _ := CreateAddr(t, name, password, GetKeyBase(t))
+	cleanup, _, port := InitializeTestLCD(t, 1, []sdk.AccAddress{addr})
+	defer cleanup()
+
+	// sync returns the CheckTx result, the height comes with the block
+	resultTx := doCreateAssetMode(t, port, name, password, "sync", "sync", addr)
+	assert.True(t, resultTx.IsOK(), resultTx.Log)
+	assert.NotEmpty(t, resultTx.Hash)
+	assert.Equal(t, int64(0), resultTx.Height)
+	waitForTx(t, port, resultTx.Hash)
+
+	resultTx = doCreateAssetMode(t, port, name, password, "async", "async", addr)
+	assert.NotEmpty(t, resultTx.Hash)
+	waitForTx(t, port, resultTx.Hash)
+
+	resultTx = doCreateAssetMode(t, port, name, password, "block", "block", addr)
+	assert.True(t, resultTx.IsOK(), resultTx.Log)
+	assert.NotZero(t, resultTx.Height)
+	assert.NotZero(t, resultTx.GasUsed)
+
+	// a rejected tx comes with its code
+	acc := getAccount(t, port, addr)
+	jsonStr := []byte(fmt.Sprintf(`{
+		"base_req": {
+			"name": "%s",
+			"password": "%s",
+			"account_number": "%d",
+			"sequence": "%d",
+			"gas": "10000",
+			"chain_id": "%s"
+		},
+		"name": "block",
+		"asset_id": "block",
+		"quantity": "100",
+		"unit": "kg"
+	}`, name, password, acc.GetAccountNumber(), acc.GetSequence(), viper.GetString(client.FlagChainID)))
+	res, body := Request(t, port, "POST", "/assets", jsonStr)
+	require.Equal(t, http.StatusBadRequest, res.StatusCode, body)
+	require.Nil(t, cdc.UnmarshalJSON([]byte(body), &resultTx))
+	assert.False(t, resultTx.IsOK())
+	assert.NotEmpty(t, resultTx.Log)
+}
+
+func TestSearchAssetTxs(t *testing.T) {
+	name, password := "test", "1234567890"
+	addr, _ := CreateAddr(t, name, password, GetKeyBase(t))
+	cleanup, _, port := InitializeTestLCD(t, 1, []sdk.AccAddress{addr})
+	defer cleanup()
+
+	first := doCreateAssetMode(t, port, name, password, "first", "block", addr)
+	tests.WaitForHeight(first.Height+1, port)
+	second := doCreateAssetMode(t, port, name, password, "second", "block", addr)
+	tests.WaitForHeight(second.Height+1, port)
+
+	// OR of the tags, one tx a page
+	txs, total := searchAssetTxs(t, port, "tag=asset_id='first'&tag=asset_id='second'&any=true&limit=1&page=2")
+	require.Equal(t, "2", total)
+	require.Equal(t, 1, len(txs))
+	require.Equal(t, second.Height, txs[0].Height)
+
+	// AND of the tags
+	txs, _ = searchAssetTxs(t, port, "tag=asset_id='first'&tag=asset_id='second'")
+	require.Equal(t, 0, len(txs))
+
+	// height range
+	txs, _ = searchAssetTxs(t, port, fmt.Sprintf("tag=asset_id='first'&tag=asset_id='second'&any=true&min_height=%d", second.Height))
+	require.Equal(t, 1, len(txs))
+	require.NotZero(t, txs[0].Time)
+
+	// time range
+	txs, _ = searchAssetTxs(t, port, fmt.Sprintf("tag=asset_id='first'&max_time=%d", txs[0].Time))
+	require.Equal(t, 1, len(txs))
+	txs, _ = searchAssetTxs(t, port, "tag=asset_id='first'&min_time=2100-01-01T00:00:00Z")
+	require.Equal(t, 0, len(txs))
+
+	// the node pages a single query, the merged ones are refused past the
+	// first results
+	res, body := Request(t, port, "GET", "/txs?tag=asset_id='first'&limit=100&page=101", nil)
+	require.Equal(t, http.StatusOK, res.StatusCode, body)
+	res, body = Request(t, port, "GET", "/txs?tag=asset_id='first'&tag=asset_id='second'&any=true&limit=100&page=101", nil)
+	require.Equal(t, http.StatusBadRequest, res.StatusCode, body)
+}
+
+func TestAssetEvents(t *testing.T) {
+	name, password := "test", "1234567890"
+	addr, seed := CreateAddr(t, name, password, GetKeyBase(t))
+	cleanup, _, port := InitializeTestLCD(t, 1, []sdk.AccAddress{addr})
+	defer cleanup()
+
+	resultTx := doCreateAsset(t, port, seed, name, password, addr)
+	tests.WaitForHeight(resultTx.Height+1, port)
+
+	// replayed from the height of the tx
+	ev := readAssetEvent(t, port, fmt.Sprintf("asset_id=test&from_height=%d", resultTx.Height))
+	assert.Equal(t, "create_asset", ev.Action)
+	assert.Equal(t, resultTx.Height, ev.Height)
+	assert.Equal(t, addr, ev.Owner)
+
+	// resumed after the event, the next one is live
+	events := readAssetEventAsync(t, port, "asset_id=test&last_event_id="+ev.ID)
+	resultTx = doFinalizeAsset(t, port, seed, name, password, addr)
+	select {
+	case ev = <-events:
+		assert.Equal(t, "finalize", ev.Action)
+		assert.Equal(t, resultTx.Height, ev.Height)
+	case <-time.After(10 * time.Second):
+		t.Fatal("no live event")
+	}
+}
+
+func TestWebhooks(t *testing.T) {
+	name, password := "test", "1234567890"
+	addr, seed := CreateAddr(t, name, password, GetKeyBase(t))
+	cleanup, _, port := InitializeTestLCD(t, 1, []sdk.AccAddress{addr})
+	defer cleanup()
+
+	deliveries := make(chan *http.Request, 1)
+	bodies := make(chan []byte, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		deliveries <- r
+		bodies <- body
+	}))
+	defer receiver.Close()
+
+	sub := doSubscribeWebhook(t, port, receiver.URL, stream.Filter{Owner: addr})
+	require.NotEmpty(t, sub.Secret)
+
+	// the secret is only returned when registering, then it is required
+	res, body := webhookRequest(t, port, "GET", "/webhooks/"+sub.ID, sub.Secret)
+	require.Equal(t, http.StatusOK, res.StatusCode, body)
+	require.NotContains(t, body, sub.Secret)
+	res, body = Request(t, port, "GET", "/webhooks/"+sub.ID, nil)
+	require.Equal(t, http.StatusUnauthorized, res.StatusCode, body)
+	res, body = webhookRequest(t, port, "DELETE", "/webhooks/"+sub.ID, "other")
+	require.Equal(t, http.StatusUnauthorized, res.StatusCode, body)
+	res, body = Request(t, port, "GET", "/webhooks", nil)
+	require.NotEqual(t, http.StatusOK, res.StatusCode, body)
+
+	resultTx := doCreateAsset(t, port, seed, name, password, addr)
+	select {
+	case r := <-deliveries:
+		body := <-bodies
+		assert.Equal(t, "create_asset", r.Header.Get(webhook.HeaderEvent))
+		timestamp, err := strconv.ParseInt(r.Header.Get(webhook.HeaderTimestamp), 10, 64)
+		require.Nil(t, err)
+		assert.True(t, webhook.Verify(sub.Secret, timestamp, body, r.Header.Get(webhook.HeaderSignature)))
+		var ev stream.Event
+		require.Nil(t, json.Unmarshal(body, &ev))
+		assert.Equal(t, resultTx.Height, ev.Height)
+	case <-time.After(10 * time.Second):
+		t.Fatal("no delivery")
+	}
+
+	// the log is written right after the answer
+	var attempts []webhook.Attempt
+	for i := 0; i < 10 && len(attempts) == 0; i++ {
+		time.Sleep(100 * time.Millisecond)
+		attempts = getWebhookDeliveries(t, port, sub)
+	}
+	require.Equal(t, 1, len(attempts))
+	assert.Equal(t, webhook.StatusDelivered, attempts[0].Status)
+
+	res, body = webhookRequest(t, port, "DELETE", "/webhooks/"+sub.ID, sub.Secret)
+	require.Equal(t, http.StatusNoContent, res.StatusCode, body)
+	res, body = webhookRequest(t, port, "GET", "/webhooks/"+sub.ID, sub.Secret)
+	require.Equal(t, http.StatusNotFound, res.StatusCode, body)
+}
+
+func TestVerifyAsset(t *testing.T) {
+	name, password := "test", "1234567890"
+	addr, seed := CreateAddr(t, name, password, GetKeyBase(t))
+	cleanup, _, port := InitializeTestLCD(t, 1, []sdk.AccAddress{addr})
+	defer cleanup()
+
+	resultTx := doCreateAsset(t, port, seed, name, password, addr)
+	tests.WaitForHeight(resultTx.Height+1, port)
+	created := resultTx.Height
+
+	resultTx = doSetAssetProperties(t, port, seed, name, password, addr, "test", `[
+		{"name": "passport_properties", "type": "5", "enum_value": ["size"]},
+		{"name": "recalled", "type": "3", "boolean_value": true},
+		{"name": "recall_reason", "type": "2", "string_value": "contaminated"}
+	]`)
+	tests.WaitForHeight(resultTx.Height+1, port)
+	require.True(t, resultTx.IsOK(), resultTx.Log)
+
+	res, body := getPassport(t, port, "/verify/test?format=json", nil)
+	require.Equal(t, http.StatusOK, res.StatusCode, body)
+	assert.Equal(t, "public, max-age=5", res.Header.Get("Cache-Control"))
+	var passport assetclient.Passport
+	require.Nil(t, json.Unmarshal([]byte(body), &passport))
+	assert.Equal(t, "test", passport.AssetID)
+	assert.Equal(t, addr, passport.Brand.Address)
+	assert.True(t, passport.Recall.Recalled)
+	assert.Equal(t, "contaminated", passport.Recall.Reason)
+	require.Equal(t, 1, len(passport.Properties))
+	assert.Equal(t, "size", passport.Properties[0].Name)
+	assert.Equal(t, res.Header.Get("X-Ichain-Height"), strconv.FormatInt(passport.Height, 10))
+
+	// pinned to the height of the asset creation, before the recall
+	res, body = getPassport(t, port, fmt.Sprintf("/verify/test?format=json&height=%d", created), nil)
+	require.Equal(t, http.StatusOK, res.StatusCode, body)
+	assert.Equal(t, "public, max-age=31536000", res.Header.Get("Cache-Control"))
+	require.Nil(t, json.Unmarshal([]byte(body), &passport))
+	assert.False(t, passport.Recall.Recalled)
+	assert.Empty(t, passport.Properties)
+
+	etag := res.Header.Get("ETag")
+	res, body = getPassport(t, port, fmt.Sprintf("/verify/test?format=json&height=%d", created), http.Header{"If-None-Match": {etag}})
+	require.Equal(t, http.StatusNotModified, res.StatusCode, body)
+
+	res, body = getPassport(t, port, "/verify/test", http.Header{"Accept": {"text/html"}})
+	require.Equal(t, http.StatusOK, res.StatusCode, body)
+	assert.Equal(t, "text/html; charset=utf-8", res.Header.Get("Content-Type"))
+	assert.Contains(t, body, "Recalled: contaminated")
+
+	res, body = getPassport(t, port, "/verify/unknown", nil)
+	require.Equal(t, http.StatusNotFound, res.StatusCode, body)
 }
 
 func TestCreateProposal(t *testing.T) {
@@ -1198,8 +1437,7 @@ func TestCreateProposal(t *testing.T) {
 	// CreateProposal tests
 	resultTx = doCreateProposal(t, port, seed, name, password, addr, addr2)
 	tests.WaitForHeight(resultTx.Height+1, port)
-	assert.Equal(t, uint32(0), resultTx.CheckTx.Code)
-	assert.Equal(t, uint32(0), resultTx.DeliverTx.Code)
+	assert.True(t, resultTx.IsOK(), resultTx.Log)
 
 	// getProposals
 	proposals := getProposals(t, port)
@@ -1211,8 +1449,7 @@ func TestCreateProposal(t *testing.T) {
 	// AnswerProposal tests
 	resultTx = doAnswerProposal(t, port, seed2, name2, password2, addr2, addr2)
 	tests.WaitForHeight(resultTx.Height+1, port)
-	assert.Equal(t, uint32(0), resultTx.CheckTx.Code)
-	assert.Equal(t, uint32(0), resultTx.DeliverTx.Code)
+	assert.True(t, resultTx.IsOK(), resultTx.Log)
 
 	asset := getAsset(t, port, "test")
 	assert.Equal(t, len(asset.Reporters), 1)
@@ -1220,8 +1457,7 @@ func TestCreateProposal(t *testing.T) {
 	// RevokeReporter tests
 	resultTx = doRevokeReporter(t, port, seed2, name, password, addr, addr2)
 	tests.WaitForHeight(resultTx.Height+1, port)
-	assert.Equal(t, uint32(0), resultTx.CheckTx.Code)
-	assert.Equal(t, uint32(0), resultTx.DeliverTx.Code)
+	assert.True(t, resultTx.IsOK(), resultTx.Log)
 
 	asset = getAsset(t, port, "test")
 	assert.Equal(t, len(asset.Reporters), 0)
@@ -1241,8 +1477,7 @@ func TestAddTrust(t *testing.T) {
 	// AddTrust tests
 	resultTx := doAddTrust(t, port, seed, name, password, addr, addr2)
 	tests.WaitForHeight(resultTx.Height+1, port)
-	assert.Equal(t, uint32(0), resultTx.CheckTx.Code)
-	assert.Equal(t, uint32(0), resultTx.DeliverTx.Code)
+	assert.True(t, resultTx.IsOK(), resultTx.Log)
 
 	trusts := getTrusts(t, port, addr)
 	assert.Equal(t, len(trusts), 1)
@@ -1257,14 +1492,12 @@ func TestAddCerts(t *testing.T) {
 
 	resultTx := doRegisterIdentity(t, port, seed, name, password, addr)
 	tests.WaitForHeight(resultTx.Height+1, port)
-	assert.Equal(t, uint32(0), resultTx.CheckTx.Code)
-	assert.Equal(t, uint32(0), resultTx.DeliverTx.Code)
+	assert.True(t, resultTx.IsOK(), resultTx.Log)
 
 	// AddCerts tests
 	resultTx = doAddCerts(t, port, seed, name, password, addr)
 	tests.WaitForHeight(resultTx.Height+1, port)
-	assert.Equal(t, uint32(0), resultTx.CheckTx.Code)
-	assert.Equal(t, uint32(0), resultTx.DeliverTx.Code)
+	assert.True(t, resultTx.IsOK(), resultTx.Log)
 
 	certs := getCertsByOwner(t, port)
 	assert.Equal(t, len(certs), 2)
@@ -1279,8 +1512,7 @@ func TestRegisterIdent(t *testing.T) {
 	// AddCerts tests
 	resultTx := doRegisterIdentity(t, port, seed, name, password, addr)
 	tests.WaitForHeight(resultTx.Height+1, port)
-	assert.Equal(t, uint32(0), resultTx.CheckTx.Code)
-	assert.Equal(t, uint32(0), resultTx.DeliverTx.Code)
+	assert.True(t, resultTx.IsOK(), resultTx.Log)
 
 	// Onwers
 	owners := getOwners(t, port, addr)
@@ -1289,8 +1521,7 @@ func TestRegisterIdent(t *testing.T) {
 	// Test AddOwner
 	resultTx = doAddOwner(t, port, seed, name, password, addr)
 	tests.WaitForHeight(resultTx.Height+1, port)
-	assert.Equal(t, uint32(0), resultTx.CheckTx.Code)
-	assert.Equal(t, uint32(0), resultTx.DeliverTx.Code)
+	assert.True(t, resultTx.IsOK(), resultTx.Log)
 
 	// Onwers
 	owners = getOwners(t, port, addr)
@@ -1299,8 +1530,7 @@ func TestRegisterIdent(t *testing.T) {
 	// Test DeleteOwner
 	resultTx = doDelOwner(t, port, seed, name, password, addr)
 	tests.WaitForHeight(resultTx.Height+1, port)
-	assert.Equal(t, uint32(0), resultTx.CheckTx.Code)
-	assert.Equal(t, uint32(0), resultTx.DeliverTx.Code)
+	assert.True(t, resultTx.IsOK(), resultTx.Log)
 
 	// Onwers
 	owners = getOwners(t, port, addr)
@@ -1314,5 +1544,6 @@ func TestAppLogin(t *testing.T) {
 	cleanup, _, port := InitializeTestLCD(t, 1, []sdk.AccAddress{addr})
 	defer cleanup()
 	doAppSignAndVerify(t, port)
+	doAppToken(t, port)
 
 }
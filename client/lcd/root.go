@@ -1,15 +1,20 @@
 package lcd
 
 import (
+	"fmt"
 	"net/http"
 	"os"
+	"path/filepath"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"github.com/tendermint/tendermint/libs/cli"
 	"github.com/tendermint/tendermint/libs/log"
 
 	cmn "github.com/tendermint/tendermint/libs/common"
+	dbm "github.com/tendermint/tendermint/libs/db"
 	tmserver "github.com/tendermint/tendermint/rpc/lib/server"
 
 	client "github.com/cosmos/cosmos-sdk/client"
@@ -23,9 +28,13 @@ import (
 	slashing "github.com/cosmos/cosmos-sdk/x/slashing/client/rest"
 	stake "github.com/cosmos/cosmos-sdk/x/stake/client/rest"
 
+	"github.com/icheckteam/ichain/client/epcis"
 	"github.com/icheckteam/ichain/client/rpc"
+	"github.com/icheckteam/ichain/client/session"
 	"github.com/icheckteam/ichain/client/signature"
+	"github.com/icheckteam/ichain/client/stream"
 	"github.com/icheckteam/ichain/client/tx"
+	"github.com/icheckteam/ichain/client/webhook"
 	asset "github.com/icheckteam/ichain/x/asset/client/rest"
 	identity "github.com/icheckteam/ichain/x/identity/client/rest"
 )
@@ -72,10 +81,106 @@ func ServeCommand(cdc *wire.Codec) *cobra.Command {
 	cmd.Flags().String(client.FlagChainID, "", "The chain ID to connect to")
 	cmd.Flags().String(client.FlagNode, "tcp://localhost:26657", "Address of the node to connect to")
 	cmd.Flags().Int(flagMaxOpenConnections, 1000, "The number of maximum open connections")
+	cmd.Flags().String(flagChallengeStore, storeMemory, "Where login challenges are kept (memory|goleveldb)")
+	cmd.Flags().Duration(flagChallengeTTL, signature.DefaultChallengeTTL, "How long a login challenge stays valid")
+	cmd.Flags().String(flagSessionKey, "", "Key file signing the session tokens, defaults to config/session_key.json in the home")
+	cmd.Flags().String(flagSessionAlg, session.AlgEdDSA, "Algorithm of a newly generated session key (EdDSA|ES256K)")
+	cmd.Flags().String(flagSessionIssuer, "", "Issuer of the session tokens, the public url of the LCD, no token is issued without it")
+	cmd.Flags().Duration(flagSessionTTL, signature.DefaultTokenTTL, "Lifetime of the session tokens")
+	cmd.Flags().Duration(flagStreamPollInterval, time.Second, "How often the asset event stream looks for new blocks")
+	cmd.Flags().String(flagWebhookStore, storeLevelDB, "Where webhook subscriptions and pending deliveries are kept (memory|goleveldb)")
+	cmd.Flags().Int(flagWebhookMaxAttempts, webhook.DefaultMaxAttempts, "How many times a webhook delivery is tried")
+	cmd.Flags().Duration(flagWebhookBackoff, webhook.DefaultBackoff, "Wait after a failed webhook delivery, doubled after each attempt")
+	cmd.Flags().Bool(flagWebhookAllowPrivate, false, "Let webhooks target loopback, private and link-local addresses")
+	cmd.Flags().String(flagWebhookToken, "", "Operator token required as bearer token to subscribe a webhook, anyone may subscribe without it")
+	cmd.Flags().Int(flagWebhookMaxSubs, webhook.DefaultMaxSubscriptions, "How many webhook subscriptions are kept")
 
 	return cmd
 }
 
+const (
+	flagChallengeStore = "challenge-store"
+	flagChallengeTTL   = "challenge-ttl"
+	flagSessionKey     = "session-key"
+	flagSessionAlg     = "session-alg"
+	flagSessionIssuer  = "session-issuer"
+	flagSessionTTL     = "session-ttl"
+
+	flagStreamPollInterval  = "stream-poll-interval"
+	flagWebhookStore        = "webhook-store"
+	flagWebhookMaxAttempts  = "webhook-max-attempts"
+	flagWebhookBackoff      = "webhook-backoff"
+	flagWebhookAllowPrivate = "webhook-allow-private"
+	flagWebhookToken        = "webhook-token"
+	flagWebhookMaxSubs      = "webhook-max-subscriptions"
+
+	storeMemory  = "memory"
+	storeLevelDB = "goleveldb"
+)
+
+// openChallengeStore picks the login challenge store, goleveldb keeps
+// pending challenges across restarts of the LCD
+func openChallengeStore() (signature.ChallengeStore, error) {
+	switch viper.GetString(flagChallengeStore) {
+	case "", storeMemory:
+		return signature.NewMemStore(), nil
+	case storeLevelDB:
+		db, err := dbm.NewGoLevelDB("challenges", filepath.Join(viper.GetString(cli.HomeFlag), "data"))
+		if err != nil {
+			return nil, err
+		}
+		return signature.NewDBStore(db), nil
+	default:
+		return nil, fmt.Errorf("unknown challenge store %s", viper.GetString(flagChallengeStore))
+	}
+}
+
+// openWebhookStore picks the webhook store, goleveldb keeps subscriptions
+// and pending deliveries across restarts of the LCD
+func openWebhookStore() (*webhook.Store, error) {
+	switch viper.GetString(flagWebhookStore) {
+	case "", storeLevelDB:
+		db, err := dbm.NewGoLevelDB("webhooks", filepath.Join(viper.GetString(cli.HomeFlag), "data"))
+		if err != nil {
+			return nil, err
+		}
+		return webhook.NewStore(db), nil
+	case storeMemory:
+		return webhook.NewStore(dbm.NewMemDB()), nil
+	default:
+		return nil, fmt.Errorf("unknown webhook store %s", viper.GetString(flagWebhookStore))
+	}
+}
+
+// loadTokenIssuer loads or generates the key signing the session tokens
+func loadTokenIssuer() (signature.TokenIssuer, error) {
+	path := viper.GetString(flagSessionKey)
+	if path == "" {
+		path = filepath.Join(viper.GetString(cli.HomeFlag), "config", "session_key.json")
+	}
+	alg := viper.GetString(flagSessionAlg)
+	if alg == "" {
+		alg = session.AlgEdDSA
+	}
+	key, err := session.LoadOrGenKey(path, alg)
+	if err != nil {
+		return signature.TokenIssuer{}, err
+	}
+	signer, err := session.NewSigner(key)
+	if err != nil {
+		return signature.TokenIssuer{}, err
+	}
+	ttl := viper.GetDuration(flagSessionTTL)
+	if ttl <= 0 {
+		ttl = signature.DefaultTokenTTL
+	}
+	return signature.TokenIssuer{
+		Signer: signer,
+		Issuer: viper.GetString(flagSessionIssuer),
+		TTL:    ttl,
+	}, nil
+}
+
 func createHandler(cdc *wire.Codec) http.Handler {
 	r := mux.NewRouter()
 
@@ -84,8 +189,44 @@ func createHandler(cdc *wire.Codec) http.Handler {
 		panic(err)
 	}
 
+	challenges, err := openChallengeStore()
+	if err != nil {
+		panic(err)
+	}
+	challengeTTL := viper.GetDuration(flagChallengeTTL)
+	if challengeTTL <= 0 {
+		challengeTTL = signature.DefaultChallengeTTL
+	}
+	tokenIssuer, err := loadTokenIssuer()
+	if err != nil {
+		panic(err)
+	}
+
 	cliCtx := context.NewCLIContext().WithCodec(cdc).WithLogger(os.Stdout)
 
+	// the hub follows the chain for the lifetime of the LCD
+	hub := stream.NewHub(cliCtx, cdc, log.NewTMLogger(log.NewSyncWriter(os.Stdout)).With("module", "stream"))
+	if interval := viper.GetDuration(flagStreamPollInterval); interval > 0 {
+		hub = hub.WithPollInterval(interval)
+	}
+	go hub.Run(nil)
+
+	webhooks, err := openWebhookStore()
+	if err != nil {
+		panic(err)
+	}
+	allowPrivate := viper.GetBool(flagWebhookAllowPrivate)
+	dispatcher := webhook.NewDispatcher(hub, webhooks, log.NewTMLogger(log.NewSyncWriter(os.Stdout)).With("module", "webhook")).
+		WithPrivateTargets(allowPrivate)
+	if maxAttempts := viper.GetInt(flagWebhookMaxAttempts); maxAttempts > 0 {
+		backoff := viper.GetDuration(flagWebhookBackoff)
+		if backoff <= 0 {
+			backoff = webhook.DefaultBackoff
+		}
+		dispatcher = dispatcher.WithRetries(maxAttempts, backoff)
+	}
+	go dispatcher.Run(nil)
+
 	// TODO make more functional? aka r = keys.RegisterRoutes(r)
 	r.HandleFunc("/version", CLIVersionRequestHandler).Methods("GET")
 	r.HandleFunc("/node_version", NodeVersionRequestHandler(cliCtx)).Methods("GET")
@@ -101,8 +242,15 @@ func createHandler(cdc *wire.Codec) http.Handler {
 	slashing.RegisterRoutes(cliCtx, r, cdc, kb)
 	gov.RegisterRoutes(cliCtx, r, cdc)
 
-	signature.RegisterRoutes(r)
+	signature.RegisterRoutes(cliCtx, r, cdc, challenges, challengeTTL, tokenIssuer)
 	asset.RegisterRoutes(cliCtx, r, cdc, kb, "asset")
 	identity.RegisterRoutes(cliCtx, r, cdc, kb, "identity")
+	epcis.RegisterRoutes(cliCtx, r, cdc)
+	stream.RegisterRoutes(r, hub)
+	maxSubscriptions := viper.GetInt(flagWebhookMaxSubs)
+	if maxSubscriptions <= 0 {
+		maxSubscriptions = webhook.DefaultMaxSubscriptions
+	}
+	webhook.RegisterRoutes(r, webhooks, allowPrivate, viper.GetString(flagWebhookToken), maxSubscriptions)
 	return r
 }
@@ -40,6 +40,9 @@ import (
 	tmtypes "github.com/tendermint/tendermint/types"
 )
 
+// webhookToken is the operator token the test LCD requires to subscribe
+const webhookToken = "operator"
+
 // makePathname creates a unique pathname for each test. It will panic if it
 // cannot get the current working directory.
 func makePathname() string {
@@ -187,6 +190,11 @@ func InitializeTestLCD(t *testing.T, nValidators int, initAddrs []sdk.AccAddress
 	// XXX: Need to set this so LCD knows the tendermint node address!
 	viper.Set(client.FlagNode, config.RPC.ListenAddress)
 	viper.Set(client.FlagChainID, genDoc.ChainID)
+	viper.Set("webhook-store", "memory")
+	viper.Set("session-issuer", "https://lcd.example")
+	// the test receivers listen on localhost
+	viper.Set("webhook-allow-private", true)
+	viper.Set("webhook-token", webhookToken)
 
 	node, err := startTM(config, logger, genDoc, privVal, iapp)
 	require.NoError(t, err)
@@ -0,0 +1,292 @@
+// Package session issues and verifies the JWT session tokens the LCD hands
+// out for a verified ichain login claim. Third party sites only need this
+// package and the JWKS of the LCD to check a token.
+package session
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+)
+
+// JWS algorithms of the supported key types
+const (
+	AlgEdDSA  = "EdDSA"
+	AlgES256K = "ES256K"
+)
+
+var (
+	// ErrInvalidToken is returned for tokens that are malformed or whose
+	// signature does not verify
+	ErrInvalidToken = errors.New("invalid token")
+	// ErrTokenExpired ...
+	ErrTokenExpired = errors.New("token expired")
+)
+
+// Claims of a session token, the registered OIDC claims plus what ichain
+// knows about the subject
+type Claims struct {
+	Issuer    string `json:"iss"`
+	Subject   string `json:"sub"` // account address
+	Audience  string `json:"aud"`
+	IssuedAt  int64  `json:"iat"`
+	NotBefore int64  `json:"nbf"`
+	Expires   int64  `json:"exp"`
+	ID        string `json:"jti"`
+
+	Identities []string `json:"identities,omitempty"` // identities owned by the subject
+	Certs      []Cert   `json:"certs,omitempty"`
+}
+
+// Cert is a valid cert of the subject or of one of its identities
+type Cert struct {
+	Owner     string          `json:"owner"`
+	Property  string          `json:"property"`
+	Certifier string          `json:"certifier"`
+	Data      json.RawMessage `json:"data,omitempty"`
+	Expires   int64           `json:"exp,omitempty"`
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ"`
+	Kid string `json:"kid"`
+}
+
+// JWK is the public key of a signer as a JSON Web Key, OKP/Ed25519 or
+// EC/secp256k1
+type JWK struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y,omitempty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+}
+
+// JWKS ...
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// NewJWK ...
+func NewJWK(pub crypto.PubKey) (JWK, error) {
+	jwk := JWK{Kid: KeyID(pub), Use: "sig"}
+	switch pub := pub.(type) {
+	case ed25519.PubKeyEd25519:
+		jwk.Kty, jwk.Crv, jwk.Alg = "OKP", "Ed25519", AlgEdDSA
+		jwk.X = encodeSegment(pub[:])
+	case secp256k1.PubKeySecp256k1:
+		key, err := btcec.ParsePubKey(pub[:], btcec.S256())
+		if err != nil {
+			return jwk, err
+		}
+		jwk.Kty, jwk.Crv, jwk.Alg = "EC", "secp256k1", AlgES256K
+		jwk.X = encodeSegment(padded(key.X))
+		jwk.Y = encodeSegment(padded(key.Y))
+	default:
+		return jwk, fmt.Errorf("unsupported key type %T", pub)
+	}
+	return jwk, nil
+}
+
+// PubKey converts the JWK back to a tendermint key
+func (k JWK) PubKey() (crypto.PubKey, error) {
+	x, err := decodeSegment(k.X)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case k.Kty == "OKP" && k.Crv == "Ed25519" && len(x) == 32:
+		var pub ed25519.PubKeyEd25519
+		copy(pub[:], x)
+		return pub, nil
+	case k.Kty == "EC" && k.Crv == "secp256k1" && len(x) == 32:
+		y, err := decodeSegment(k.Y)
+		if err != nil || len(y) != 32 {
+			return nil, fmt.Errorf("invalid secp256k1 key %s", k.Kid)
+		}
+		key := btcec.PublicKey{Curve: btcec.S256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !key.IsOnCurve(key.X, key.Y) {
+			return nil, fmt.Errorf("invalid secp256k1 key %s", k.Kid)
+		}
+		var pub secp256k1.PubKeySecp256k1
+		copy(pub[:], key.SerializeCompressed())
+		return pub, nil
+	default:
+		return nil, fmt.Errorf("unsupported key %s %s", k.Kty, k.Crv)
+	}
+}
+
+// KeyID is the kid of a public key, its address in hex
+func KeyID(pub crypto.PubKey) string {
+	return strings.ToLower(pub.Address().String())
+}
+
+// Signer signs session tokens with the key of the LCD
+type Signer struct {
+	key crypto.PrivKey
+	alg string
+	kid string
+}
+
+// NewSigner ...
+func NewSigner(key crypto.PrivKey) (Signer, error) {
+	jwk, err := NewJWK(key.PubKey())
+	if err != nil {
+		return Signer{}, err
+	}
+	return Signer{key: key, alg: jwk.Alg, kid: jwk.Kid}, nil
+}
+
+// JWKS publishes the public key of the signer
+func (s Signer) JWKS() JWKS {
+	jwk, _ := NewJWK(s.key.PubKey())
+	return JWKS{Keys: []JWK{jwk}}
+}
+
+// Sign returns the compact serialization of the token
+func (s Signer) Sign(claims Claims) (string, error) {
+	h, err := json.Marshal(header{Alg: s.alg, Typ: "JWT", Kid: s.kid})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	input := encodeSegment(h) + "." + encodeSegment(payload)
+	sig, err := s.key.Sign([]byte(input))
+	if err != nil {
+		return "", err
+	}
+	if s.alg == AlgES256K {
+		// tendermint signs secp256k1 in DER, JWS wants R || S
+		parsed, err := btcec.ParseDERSignature(sig, btcec.S256())
+		if err != nil {
+			return "", err
+		}
+		sig = append(padded(parsed.R), padded(parsed.S)...)
+	}
+	return input + "." + encodeSegment(sig), nil
+}
+
+// Verify checks the signature of a token against the JWKS, then its time
+// window, issuer and audience. Both are required, a token issued to another
+// app or by another LCD sharing the key must not pass.
+func Verify(token string, jwks JWKS, issuer, audience string, now time.Time) (Claims, error) {
+	var claims Claims
+	if issuer == "" || audience == "" {
+		return claims, errors.New("the issuer and the audience of a token are required to verify it")
+	}
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return claims, ErrInvalidToken
+	}
+	bz, err := decodeSegment(parts[0])
+	if err != nil {
+		return claims, ErrInvalidToken
+	}
+	var h header
+	if err := json.Unmarshal(bz, &h); err != nil {
+		return claims, ErrInvalidToken
+	}
+	sig, err := decodeSegment(parts[2])
+	if err != nil {
+		return claims, ErrInvalidToken
+	}
+	if !verifySignature(jwks, h, []byte(parts[0]+"."+parts[1]), sig) {
+		return claims, ErrInvalidToken
+	}
+
+	bz, err = decodeSegment(parts[1])
+	if err != nil {
+		return claims, ErrInvalidToken
+	}
+	if err := json.Unmarshal(bz, &claims); err != nil {
+		return claims, ErrInvalidToken
+	}
+	if claims.Expires <= now.Unix() {
+		return claims, ErrTokenExpired
+	}
+	if claims.NotBefore > now.Unix() {
+		return claims, fmt.Errorf("token not valid before %d", claims.NotBefore)
+	}
+	if claims.Issuer != issuer {
+		return claims, fmt.Errorf("token issuer %s is not %s", claims.Issuer, issuer)
+	}
+	if claims.Audience != audience {
+		return claims, fmt.Errorf("token audience %s is not %s", claims.Audience, audience)
+	}
+	return claims, nil
+}
+
+// verifySignature only trusts the algorithm of the matching key, the
+// header alg must agree with it so a token cannot pick its own
+func verifySignature(jwks JWKS, h header, input, sig []byte) bool {
+	for _, jwk := range jwks.Keys {
+		if jwk.Kid != h.Kid || jwk.Alg != h.Alg {
+			continue
+		}
+		pub, err := jwk.PubKey()
+		if err != nil {
+			return false
+		}
+		if jwk.Alg == AlgES256K {
+			if len(sig) != 64 {
+				return false
+			}
+			der := btcec.Signature{R: new(big.Int).SetBytes(sig[:32]), S: new(big.Int).SetBytes(sig[32:])}
+			sig = der.Serialize()
+		}
+		return pub.VerifyBytes(input, sig)
+	}
+	return false
+}
+
+// HasIdentity reports whether the subject owns the identity
+func (c Claims) HasIdentity(ident string) bool {
+	for _, id := range c.Identities {
+		if id == ident {
+			return true
+		}
+	}
+	return false
+}
+
+// CertsOf returns the certs of a property held by the subject or by one
+// of its identities
+func (c Claims) CertsOf(property string) []Cert {
+	certs := []Cert{}
+	for _, cert := range c.Certs {
+		if cert.Property == property {
+			certs = append(certs, cert)
+		}
+	}
+	return certs
+}
+
+func encodeSegment(bz []byte) string {
+	return base64.RawURLEncoding.EncodeToString(bz)
+}
+
+func decodeSegment(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(s)
+}
+
+// padded returns a 32 bytes big endian coordinate or scalar
+func padded(n *big.Int) []byte {
+	bz := n.Bytes()
+	return append(bytes.Repeat([]byte{0}, 32-len(bz)), bz...)
+}
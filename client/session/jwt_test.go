@@ -0,0 +1,95 @@
+package session
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	now := time.Now()
+	claims := Claims{
+		Issuer:     "https://lcd.example",
+		Subject:    "cosmosaccaddr1jawd35d9aq4u76sr3fjalmcqc8hqygs9gtnmv3",
+		Audience:   "https://shop.example",
+		IssuedAt:   now.Unix(),
+		NotBefore:  now.Unix(),
+		Expires:    now.Add(time.Hour).Unix(),
+		Identities: []string{"cosmosaccaddr1753dqa50dlh8l4xl0j0kd9gga0heqsj7c2wwef"},
+		Certs: []Cert{{
+			Owner:     "cosmosaccaddr1753dqa50dlh8l4xl0j0kd9gga0heqsj7c2wwef",
+			Property:  "organic",
+			Certifier: "cosmosaccaddr1jawd35d9aq4u76sr3fjalmcqc8hqygs9gtnmv3",
+			Data:      json.RawMessage(`{"standard":"EU 2018/848"}`),
+		}},
+	}
+
+	for _, key := range []crypto.PrivKey{ed25519.GenPrivKey(), secp256k1.GenPrivKey()} {
+		signer, err := NewSigner(key)
+		require.Nil(t, err)
+		jwks := signer.JWKS()
+
+		token, err := signer.Sign(claims)
+		require.Nil(t, err)
+		got, err := Verify(token, jwks, "https://lcd.example", "https://shop.example", now)
+		require.Nil(t, err, jwks.Keys[0].Alg)
+		assert.Equal(t, claims, got)
+		assert.True(t, got.HasIdentity(claims.Identities[0]))
+		assert.Equal(t, 1, len(got.CertsOf("organic")))
+
+		_, err = Verify(token, jwks, "https://lcd.example", "https://evil.example", now)
+		assert.NotNil(t, err)
+		_, err = Verify(token, jwks, "https://other-lcd.example", "https://shop.example", now)
+		assert.NotNil(t, err)
+		// the issuer and the audience can't be skipped
+		_, err = Verify(token, jwks, "https://lcd.example", "", now)
+		assert.NotNil(t, err)
+		_, err = Verify(token, jwks, "", "https://shop.example", now)
+		assert.NotNil(t, err)
+		_, err = Verify(token, jwks, "https://lcd.example", "https://shop.example", now.Add(2*time.Hour))
+		assert.Equal(t, ErrTokenExpired, err)
+
+		// tampered payload
+		parts := strings.Split(token, ".")
+		other := claims
+		other.Subject = "cosmosaccaddr1753dqa50dlh8l4xl0j0kd9gga0heqsj7c2wwef"
+		payload, _ := json.Marshal(other)
+		_, err = Verify(parts[0]+"."+encodeSegment(payload)+"."+parts[2], jwks, "https://lcd.example", "https://shop.example", now)
+		assert.Equal(t, ErrInvalidToken, err)
+
+		// signed by another key
+		_, err = Verify(token, JWKS{}, "https://lcd.example", "https://shop.example", now)
+		assert.Equal(t, ErrInvalidToken, err)
+	}
+}
+
+func TestAlgorithmMustMatchKey(t *testing.T) {
+	signer, err := NewSigner(ed25519.GenPrivKey())
+	require.Nil(t, err)
+	jwks := signer.JWKS()
+	token, err := signer.Sign(Claims{Issuer: "https://lcd.example", Audience: "https://shop.example", Expires: time.Now().Add(time.Hour).Unix()})
+	require.Nil(t, err)
+
+	parts := strings.Split(token, ".")
+	h, _ := json.Marshal(header{Alg: "none", Typ: "JWT", Kid: jwks.Keys[0].Kid})
+	_, err = Verify(encodeSegment(h)+"."+parts[1]+".", jwks, "https://lcd.example", "https://shop.example", time.Now())
+	assert.Equal(t, ErrInvalidToken, err)
+}
+
+func TestJWKRoundTrip(t *testing.T) {
+	for _, key := range []crypto.PrivKey{ed25519.GenPrivKey(), secp256k1.GenPrivKey()} {
+		jwk, err := NewJWK(key.PubKey())
+		require.Nil(t, err)
+		assert.Equal(t, KeyID(key.PubKey()), jwk.Kid)
+		pub, err := jwk.PubKey()
+		require.Nil(t, err)
+		assert.True(t, key.PubKey().Equals(pub))
+	}
+}
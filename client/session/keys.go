@@ -0,0 +1,73 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	amino "github.com/tendermint/go-amino"
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	cryptoAmino "github.com/tendermint/tendermint/crypto/encoding/amino"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+)
+
+var cdc = amino.NewCodec()
+
+func init() {
+	cryptoAmino.RegisterAmino(cdc)
+}
+
+// LoadOrGenKey reads the signing key of the LCD, a new key of the algorithm
+// is generated on first start
+func LoadOrGenKey(path, alg string) (crypto.PrivKey, error) {
+	var key crypto.PrivKey
+	bz, err := ioutil.ReadFile(path)
+	if err == nil {
+		if err := cdc.UnmarshalJSON(bz, &key); err != nil {
+			return nil, fmt.Errorf("invalid session key %s: %v", path, err)
+		}
+		return key, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	switch alg {
+	case AlgEdDSA:
+		key = ed25519.GenPrivKey()
+	case AlgES256K:
+		key = secp256k1.GenPrivKey()
+	default:
+		return nil, fmt.Errorf("unsupported algorithm %s", alg)
+	}
+	bz, err = cdc.MarshalJSON(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(path, bz, 0600); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// FetchJWKS downloads the keys of an LCD, usually from /apps/jwks
+func FetchJWKS(url string) (JWKS, error) {
+	var jwks JWKS
+	res, err := http.Get(url)
+	if err != nil {
+		return jwks, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return jwks, fmt.Errorf("fetching %s: %s", url, res.Status)
+	}
+	err = json.NewDecoder(res.Body).Decode(&jwks)
+	return jwks, err
+}
@@ -1,14 +1,18 @@
 package signature
 
 import (
+	"crypto/rand"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"io/ioutil"
 	"net/http"
 	"time"
 
+	"github.com/cosmos/cosmos-sdk/client/context"
 	"github.com/cosmos/cosmos-sdk/client/keys"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
 	"github.com/gorilla/mux"
 	"github.com/tendermint/tendermint/crypto"
 )
@@ -21,11 +25,21 @@ type LoginBody struct {
 	Nonce    string `json:"nonce"`
 }
 
+// DefaultChallengeTTL is how long an issued challenge can be signed
+// and verified
+const DefaultChallengeTTL = 5 * time.Minute
+
+// ChallengeBody ...
+type ChallengeBody struct {
+	Audience string `json:"audience"`
+}
+
 // ClaimMsg ...
 type ClaimMsg struct {
-	PubKey  crypto.PubKey `json:"pubkey"`
-	Expires int64         `json:"expires"`
-	Nonce   string        `json:"nonce"`
+	PubKey   crypto.PubKey `json:"pubkey"`
+	Expires  int64         `json:"expires"`
+	Nonce    string        `json:"nonce"`
+	Audience string        `json:"audience"`
 }
 
 // Bytes ...
@@ -46,6 +60,51 @@ type Claim struct {
 ///////////////////////////
 // REST
 
+// ChallengeHandler issues a single use nonce bound to the audience,
+// the website that will verify the claim
+func ChallengeHandler(store ChallengeStore, ttl time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var m ChallengeBody
+		err := json.NewDecoder(r.Body).Decode(&m)
+		if err != nil {
+			w.WriteHeader(400)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		if m.Audience == "" {
+			w.WriteHeader(400)
+			w.Write([]byte("audience is required"))
+			return
+		}
+
+		nonce := make([]byte, 32)
+		if _, err := rand.Read(nonce); err != nil {
+			w.WriteHeader(500)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		c := Challenge{
+			Nonce:    base64.URLEncoding.EncodeToString(nonce),
+			Audience: m.Audience,
+			Expires:  time.Now().Add(ttl).Unix(),
+		}
+		if err := store.Put(c); err != nil {
+			w.WriteHeader(500)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		b, err := json.Marshal(c)
+		if err != nil {
+			w.WriteHeader(500)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		w.Write(b)
+	}
+}
+
 // SignHandler get key REST handler
 func SignHandler(w http.ResponseWriter, r *http.Request) {
 	var m LoginBody
@@ -72,9 +131,10 @@ func SignHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	msg := ClaimMsg{
-		Nonce:   m.Nonce,
-		PubKey:  key.GetPubKey(),
-		Expires: time.Now().Add(60 * time.Second).Unix(),
+		Nonce:    m.Nonce,
+		Audience: m.Website,
+		PubKey:   key.GetPubKey(),
+		Expires:  time.Now().Add(60 * time.Second).Unix(),
 	}
 
 	sign, _, err := keybase.Sign(m.Name, m.Password, msg.Bytes())
@@ -100,48 +160,81 @@ func SignHandler(w http.ResponseWriter, r *http.Request) {
 
 // VerifyOutput ...
 type VerifyOutput struct {
-	Status  bool           `json:"status"`
-	Address sdk.AccAddress `json:"address"`
+	Status   bool           `json:"status"`
+	Address  sdk.AccAddress `json:"address"`
+	Audience string         `json:"audience,omitempty"`
+	Error    string         `json:"error,omitempty"`
 }
 
-// VerifiyHandler ...
-func VerifiyHandler(w http.ResponseWriter, r *http.Request) {
-	var m Claim
-	body, err := ioutil.ReadAll(r.Body)
-	if err != nil {
-		w.WriteHeader(400)
-		w.Write([]byte(err.Error()))
-		return
+// VerifiyHandler checks the claim signature and expiry, then consumes
+// the challenge so the same claim is never accepted twice
+func VerifiyHandler(store ChallengeStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var m Claim
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(400)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		err = cdc.UnmarshalJSON(body, &m)
+		if err != nil {
+			w.WriteHeader(400)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		if m.Msg.PubKey == nil {
+			w.WriteHeader(400)
+			w.Write([]byte("pubkey is required"))
+			return
+		}
+
+		output := VerifyOutput{
+			Status:   true,
+			Address:  sdk.AccAddress(m.Msg.PubKey.Address()),
+			Audience: m.Msg.Audience,
+		}
+		if err := verify(store, m, time.Now()); err != nil {
+			output.Status = false
+			output.Error = err.Error()
+		}
+
+		b, err := json.Marshal(output)
+		if err != nil {
+			w.WriteHeader(400)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		w.Write(b)
 	}
+}
 
-	err = cdc.UnmarshalJSON(body, &m)
-	if err != nil {
-		w.WriteHeader(400)
-		w.Write([]byte(err.Error()))
-		return
+// verify consumes the challenge only once the claim is otherwise valid,
+// so a forged claim cannot burn the nonce of a pending login
+func verify(store ChallengeStore, m Claim, now time.Time) error {
+	if !m.Msg.PubKey.VerifyBytes(m.Msg.Bytes(), m.Signature) {
+		return errors.New("invalid signature")
 	}
-
-	output := VerifyOutput{
-		Status:  verify(m),
-		Address: sdk.AccAddress(m.Msg.PubKey.Address()),
+	if m.Msg.Expires <= now.Unix() {
+		return errors.New("claim expired")
 	}
-
-	b, err := json.Marshal(output)
+	c, err := store.Consume(m.Msg.Nonce, now)
 	if err != nil {
-		w.WriteHeader(400)
-		w.Write([]byte(err.Error()))
-		return
+		return err
 	}
-	w.Write(b)
-
-}
-
-func verify(m Claim) bool {
-	return m.Msg.PubKey.VerifyBytes(m.Msg.Bytes(), m.Signature)
+	if c.Audience != m.Msg.Audience {
+		return errors.New("claim audience does not match the challenge")
+	}
+	return nil
 }
 
 // RegisterRoutes resgister REST routes
-func RegisterRoutes(r *mux.Router) {
+func RegisterRoutes(ctx context.CLIContext, r *mux.Router, cdc *wire.Codec, store ChallengeStore, ttl time.Duration, issuer TokenIssuer) {
+	r.HandleFunc("/apps/challenge", ChallengeHandler(store, ttl)).Methods("POST")
 	r.HandleFunc("/apps/sign", SignHandler).Methods("POST")
-	r.HandleFunc("/apps/verify", VerifiyHandler).Methods("POST")
+	r.HandleFunc("/apps/verify", VerifiyHandler(store)).Methods("POST")
+	r.HandleFunc("/apps/token", TokenHandler(ctx, cdc, store, issuer)).Methods("POST")
+	r.HandleFunc("/apps/jwks", JWKSHandler(issuer)).Methods("GET")
+	r.HandleFunc("/.well-known/openid-configuration", DiscoveryHandler(issuer)).Methods("GET")
 }
@@ -0,0 +1,89 @@
+package signature
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	dbm "github.com/tendermint/tendermint/libs/db"
+)
+
+func TestChallengeStores(t *testing.T) {
+	dir, err := ioutil.TempDir("", "challenges")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+	db, err := dbm.NewGoLevelDB("challenges", dir)
+	require.Nil(t, err)
+	defer db.Close()
+
+	now := time.Now()
+	stores := map[string]ChallengeStore{
+		"memory":  NewMemStore(),
+		"memdb":   NewDBStore(dbm.NewMemDB()),
+		"leveldb": NewDBStore(db),
+	}
+	for name, store := range stores {
+		c := Challenge{Nonce: "n1", Audience: "https://shop.example", Expires: now.Add(time.Minute).Unix()}
+		require.Nil(t, store.Put(c), name)
+
+		got, err := store.Consume("n1", now)
+		require.Nil(t, err, name)
+		require.Equal(t, c, got, name)
+
+		// single use
+		_, err = store.Consume("n1", now)
+		require.Equal(t, ErrUnknownChallenge, err, name)
+
+		// never issued
+		_, err = store.Consume("n2", now)
+		require.Equal(t, ErrUnknownChallenge, err, name)
+
+		// expired
+		require.Nil(t, store.Put(Challenge{Nonce: "n3", Expires: now.Add(time.Minute).Unix()}), name)
+		_, err = store.Consume("n3", now.Add(2*time.Minute))
+		require.Equal(t, ErrUnknownChallenge, err, name)
+	}
+}
+
+func TestVerifyClaim(t *testing.T) {
+	now := time.Now()
+	priv := ed25519.GenPrivKey()
+	sign := func(msg ClaimMsg) Claim {
+		msg.PubKey = priv.PubKey()
+		sig, err := priv.Sign(msg.Bytes())
+		require.Nil(t, err)
+		return Claim{Msg: msg, Signature: sig}
+	}
+
+	store := NewMemStore()
+	issue := func(nonce, audience string) {
+		require.Nil(t, store.Put(Challenge{Nonce: nonce, Audience: audience, Expires: now.Add(time.Minute).Unix()}))
+	}
+
+	issue("ok", "https://shop.example")
+	claim := sign(ClaimMsg{Nonce: "ok", Audience: "https://shop.example", Expires: now.Add(time.Minute).Unix()})
+	require.Nil(t, verify(store, claim, now))
+	require.NotNil(t, verify(store, claim, now), "replayed claim")
+
+	issue("tampered", "https://shop.example")
+	claim = sign(ClaimMsg{Nonce: "tampered", Audience: "https://shop.example", Expires: now.Add(time.Minute).Unix()})
+	claim.Msg.Audience = "https://evil.example"
+	require.NotNil(t, verify(store, claim, now))
+	// the forged claim did not burn the challenge
+	claim.Msg.Audience = "https://shop.example"
+	require.Nil(t, verify(store, claim, now))
+
+	issue("other", "https://shop.example")
+	claim = sign(ClaimMsg{Nonce: "other", Audience: "https://evil.example", Expires: now.Add(time.Minute).Unix()})
+	require.NotNil(t, verify(store, claim, now), "audience mismatch")
+
+	issue("expired", "https://shop.example")
+	claim = sign(ClaimMsg{Nonce: "expired", Audience: "https://shop.example", Expires: now.Add(-time.Second).Unix()})
+	require.NotNil(t, verify(store, claim, now), "expired claim")
+
+	claim = sign(ClaimMsg{Nonce: "unknown", Audience: "https://shop.example", Expires: now.Add(time.Minute).Unix()})
+	require.NotNil(t, verify(store, claim, now), "nonce not issued")
+}
@@ -0,0 +1,162 @@
+package signature
+
+import (
+	"encoding/binary"
+	"errors"
+	"sync"
+	"time"
+
+	dbm "github.com/tendermint/tendermint/libs/db"
+)
+
+var (
+	// ErrUnknownChallenge is returned when the nonce was never issued,
+	// has already been used or has expired
+	ErrUnknownChallenge = errors.New("unknown or already used challenge")
+)
+
+// Challenge is a server issued nonce that a claim must echo back,
+// it can be used only once and only before Expires
+type Challenge struct {
+	Nonce    string `json:"nonce"`
+	Audience string `json:"audience"`
+	Expires  int64  `json:"expires"`
+}
+
+// ChallengeStore keeps the issued challenges until they are consumed
+// or expire
+type ChallengeStore interface {
+	// Put stores a newly issued challenge
+	Put(c Challenge) error
+	// Consume removes the challenge and returns it, it fails with
+	// ErrUnknownChallenge when the nonce is unknown or expired at now
+	Consume(nonce string, now time.Time) (Challenge, error)
+}
+
+// MemStore is a ChallengeStore in process memory, challenges are lost
+// on restart and are not shared between LCD instances
+type MemStore struct {
+	mtx        sync.Mutex
+	challenges map[string]Challenge
+}
+
+var _ ChallengeStore = &MemStore{}
+
+// NewMemStore ...
+func NewMemStore() *MemStore {
+	return &MemStore{challenges: make(map[string]Challenge)}
+}
+
+// Put implements ChallengeStore
+func (s *MemStore) Put(c Challenge) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.prune(time.Now().Unix())
+	s.challenges[c.Nonce] = c
+	return nil
+}
+
+// Consume implements ChallengeStore
+func (s *MemStore) Consume(nonce string, now time.Time) (Challenge, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	c, ok := s.challenges[nonce]
+	if !ok {
+		return Challenge{}, ErrUnknownChallenge
+	}
+	delete(s.challenges, nonce)
+	if c.Expires <= now.Unix() {
+		return Challenge{}, ErrUnknownChallenge
+	}
+	return c, nil
+}
+
+// prune drops the expired challenges, issuing happens often enough
+// that no background sweeper is needed
+func (s *MemStore) prune(now int64) {
+	for nonce, c := range s.challenges {
+		if c.Expires <= now {
+			delete(s.challenges, nonce)
+		}
+	}
+}
+
+var (
+	challengeKey = []byte{0x01}
+	expiryKey    = []byte{0x02}
+)
+
+// DBStore is a ChallengeStore backed by a tendermint db, goleveldb to
+// survive restarts or memdb for tests
+type DBStore struct {
+	mtx sync.Mutex
+	db  dbm.DB
+}
+
+var _ ChallengeStore = &DBStore{}
+
+// NewDBStore ...
+func NewDBStore(db dbm.DB) *DBStore {
+	return &DBStore{db: db}
+}
+
+// Put implements ChallengeStore
+func (s *DBStore) Put(c Challenge) error {
+	bz, err := cdc.MarshalBinary(c)
+	if err != nil {
+		return err
+	}
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	batch := s.db.NewBatch()
+	s.prune(batch, time.Now().Unix())
+	batch.Set(append(challengeKey, []byte(c.Nonce)...), bz)
+	batch.Set(getExpiryKey(c.Expires, c.Nonce), []byte{})
+	batch.Write()
+	return nil
+}
+
+// Consume implements ChallengeStore
+func (s *DBStore) Consume(nonce string, now time.Time) (Challenge, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	key := append(challengeKey, []byte(nonce)...)
+	bz := s.db.Get(key)
+	if bz == nil {
+		return Challenge{}, ErrUnknownChallenge
+	}
+	var c Challenge
+	if err := cdc.UnmarshalBinary(bz, &c); err != nil {
+		return Challenge{}, err
+	}
+	batch := s.db.NewBatch()
+	batch.Delete(key)
+	batch.Delete(getExpiryKey(c.Expires, c.Nonce))
+	batch.WriteSync()
+	if c.Expires <= now.Unix() {
+		return Challenge{}, ErrUnknownChallenge
+	}
+	return c, nil
+}
+
+// prune deletes the challenges expired at now, the expiry index is
+// ordered so only stale entries are visited
+func (s *DBStore) prune(batch dbm.Batch, now int64) {
+	iter := dbm.IteratePrefix(s.db, expiryKey)
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		key := iter.Key()
+		if int64(binary.BigEndian.Uint64(key[len(expiryKey):])) > now {
+			break
+		}
+		batch.Delete(key)
+		batch.Delete(append(challengeKey, key[len(expiryKey)+8:]...))
+	}
+}
+
+func getExpiryKey(expires int64, nonce string) []byte {
+	key := make([]byte, len(expiryKey)+8, len(expiryKey)+8+len(nonce))
+	copy(key, expiryKey)
+	binary.BigEndian.PutUint64(key[len(expiryKey):], uint64(expires))
+	return append(key, nonce...)
+}
@@ -0,0 +1,217 @@
+package signature
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+
+	"github.com/icheckteam/ichain/client/session"
+	"github.com/icheckteam/ichain/x/identity"
+	identclient "github.com/icheckteam/ichain/x/identity/client"
+)
+
+// DefaultTokenTTL is the lifetime of a session token
+const DefaultTokenTTL = time.Hour
+
+// errNoIssuer refuses to issue tokens whose iss would come from the request
+var errNoIssuer = errors.New("the session token issuer is not configured")
+
+// TokenIssuer exchanges verified claims for session tokens
+type TokenIssuer struct {
+	Signer session.Signer
+	Issuer string // required, the url verifiers expect in the iss claim
+	TTL    time.Duration
+}
+
+// TokenBody ...
+type TokenBody struct {
+	Claim      Claim    `json:"claim"`
+	Properties []string `json:"properties"` // certs to include in the token
+}
+
+// TokenOutput follows the OIDC token response
+type TokenOutput struct {
+	IDToken   string `json:"id_token"`
+	TokenType string `json:"token_type"`
+	ExpiresIn int64  `json:"expires_in"`
+}
+
+// TokenHandler verifies a claim like VerifiyHandler, consuming its
+// challenge, and returns a signed session token for the address
+func TokenHandler(ctx context.CLIContext, cdc *wire.Codec, store ChallengeStore, issuer TokenIssuer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if issuer.Issuer == "" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(errNoIssuer.Error()))
+			return
+		}
+		var m TokenBody
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(400)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		err = cdc.UnmarshalJSON(body, &m)
+		if err != nil {
+			w.WriteHeader(400)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		if m.Claim.Msg.PubKey == nil {
+			w.WriteHeader(400)
+			w.Write([]byte("pubkey is required"))
+			return
+		}
+
+		now := time.Now()
+		if err := verify(store, m.Claim, now); err != nil {
+			w.WriteHeader(401)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		addr := sdk.AccAddress(m.Claim.Msg.PubKey.Address())
+		claims := session.Claims{
+			Issuer:    issuer.Issuer,
+			Subject:   addr.String(),
+			Audience:  m.Claim.Msg.Audience,
+			IssuedAt:  now.Unix(),
+			NotBefore: now.Unix(),
+			Expires:   now.Add(issuer.TTL).Unix(),
+			ID:        m.Claim.Msg.Nonce,
+		}
+		if err := addIdentityClaims(ctx, cdc, &claims, addr, m.Properties, now.Unix()); err != nil {
+			w.WriteHeader(500)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		token, err := issuer.Signer.Sign(claims)
+		if err != nil {
+			w.WriteHeader(500)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		b, err := json.Marshal(TokenOutput{
+			IDToken:   token,
+			TokenType: "Bearer",
+			ExpiresIn: int64(issuer.TTL / time.Second),
+		})
+		if err != nil {
+			w.WriteHeader(500)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		w.Write(b)
+	}
+}
+
+// addIdentityClaims adds the identities owned by the address and the valid
+// certs of the requested properties, held by the address or its identities.
+// An identity registered for an address by another key is left out, its
+// owners weren't chosen by the holder of the address.
+func addIdentityClaims(ctx context.CLIContext, cdc *wire.Codec, claims *session.Claims, addr sdk.AccAddress, properties []string, now int64) error {
+	owned, err := identclient.QueryOwnedIdents(ctx, addr)
+	if err != nil {
+		return err
+	}
+	var idents []sdk.AccAddress
+	for _, ident := range owned {
+		registered, err := identclient.QueryRegistered(ctx, ident)
+		if err != nil {
+			return err
+		}
+		if !registered {
+			continue
+		}
+		idents = append(idents, ident)
+		claims.Identities = append(claims.Identities, ident.String())
+	}
+	if len(properties) == 0 {
+		return nil
+	}
+
+	wanted := make(map[string]bool, len(properties))
+	for _, property := range properties {
+		wanted[property] = true
+	}
+	for _, owner := range append([]sdk.AccAddress{addr}, idents...) {
+		certs, err := identclient.QueryCerts(ctx, cdc, owner)
+		if err != nil {
+			return err
+		}
+		for _, cert := range certs {
+			if !wanted[cert.Property] || !cert.IsValidAt(now) {
+				continue
+			}
+			claims.Certs = append(claims.Certs, newSessionCert(cert))
+		}
+	}
+	return nil
+}
+
+func newSessionCert(cert identity.Cert) session.Cert {
+	c := session.Cert{
+		Owner:     cert.Owner.String(),
+		Property:  cert.Property,
+		Certifier: cert.Certifier.String(),
+		Expires:   cert.ExpiresAt,
+	}
+	if len(cert.Data) > 0 && json.Valid(cert.Data) {
+		c.Data = json.RawMessage(cert.Data)
+	}
+	return c
+}
+
+// JWKSHandler publishes the keys that sign the session tokens
+func JWKSHandler(issuer TokenIssuer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		b, err := json.Marshal(issuer.Signer.JWKS())
+		if err != nil {
+			w.WriteHeader(500)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		w.Header().Set("Content-Type", "application/jwk-set+json")
+		w.Write(b)
+	}
+}
+
+// DiscoveryHandler serves the subset of the OIDC discovery document that
+// token verifiers use
+func DiscoveryHandler(issuer TokenIssuer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if issuer.Issuer == "" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(errNoIssuer.Error()))
+			return
+		}
+		iss := issuer.Issuer
+		jwks := issuer.Signer.JWKS()
+		algs := []string{}
+		for _, key := range jwks.Keys {
+			algs = append(algs, key.Alg)
+		}
+		b, err := json.Marshal(map[string]interface{}{
+			"issuer":                                iss,
+			"jwks_uri":                              iss + "/apps/jwks",
+			"token_endpoint":                        iss + "/apps/token",
+			"id_token_signing_alg_values_supported": algs,
+			"subject_types_supported":               []string{"public"},
+		})
+		if err != nil {
+			w.WriteHeader(500)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(b)
+	}
+}
@@ -0,0 +1,279 @@
+package stream
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	cmn "github.com/tendermint/tendermint/libs/common"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+
+	"github.com/icheckteam/ichain/client/tx"
+	"github.com/icheckteam/ichain/x/asset"
+)
+
+// Actions of the asset events
+const (
+	ActionCreateAsset      = "create_asset"
+	ActionAddQuantity      = "add_quantity"
+	ActionSubtractQuantity = "subtract_quantity"
+	ActionUpdateProperties = "update_properties"
+	ActionAddMaterials     = "add_materials"
+	ActionFinalize         = "finalize"
+	ActionRevokeReporter   = "revoke_reporter"
+	ActionCreateProposal   = "create_proposal"
+	ActionAnswerProposal   = "answer_proposal"
+	ActionAnchorDocument   = "anchor_document"
+)
+
+// Event is an asset message of a committed and successful tx. Owner is set
+// when the message makes an address an owner, Reporter when it grants or
+// revokes the reporter role of an address.
+type Event struct {
+	ID       string          `json:"id"`
+	Action   string          `json:"action"`
+	Height   int64           `json:"height"`
+	Time     int64           `json:"time"`
+	TxHash   string          `json:"tx_hash"`
+	TxIndex  int             `json:"tx_index"`
+	MsgIndex int             `json:"msg_index"`
+	AssetIDs []string        `json:"asset_ids"`
+	Sender   sdk.AccAddress  `json:"sender"`
+	Owner    sdk.AccAddress  `json:"owner,omitempty"`
+	Reporter sdk.AccAddress  `json:"reporter,omitempty"`
+	Msg      json.RawMessage `json:"msg"`
+}
+
+// Cursor is the position of an event in the chain, the ID of an event
+type Cursor struct {
+	Height   int64
+	TxIndex  int
+	MsgIndex int
+}
+
+// FromHeight is the cursor before all the events of the height
+func FromHeight(height int64) Cursor {
+	return Cursor{Height: height, TxIndex: -1, MsgIndex: -1}
+}
+
+// ParseCursor reads the ID of an event
+func ParseCursor(id string) (Cursor, error) {
+	parts := strings.Split(id, ":")
+	if len(parts) != 3 {
+		return Cursor{}, fmt.Errorf("invalid event id %s", id)
+	}
+	height, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("invalid event id %s", id)
+	}
+	txIndex, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return Cursor{}, fmt.Errorf("invalid event id %s", id)
+	}
+	msgIndex, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return Cursor{}, fmt.Errorf("invalid event id %s", id)
+	}
+	return Cursor{Height: height, TxIndex: txIndex, MsgIndex: msgIndex}, nil
+}
+
+// IsZero is true for the cursor of a live only stream
+func (c Cursor) IsZero() bool {
+	return c == Cursor{}
+}
+
+func (c Cursor) String() string {
+	return fmt.Sprintf("%d:%d:%d", c.Height, c.TxIndex, c.MsgIndex)
+}
+
+// Before returns whether the cursor is before the other one
+func (c Cursor) Before(other Cursor) bool {
+	if c.Height != other.Height {
+		return c.Height < other.Height
+	}
+	if c.TxIndex != other.TxIndex {
+		return c.TxIndex < other.TxIndex
+	}
+	return c.MsgIndex < other.MsgIndex
+}
+
+// Cursor returns the position of the event
+func (ev Event) Cursor() Cursor {
+	return Cursor{Height: ev.Height, TxIndex: ev.TxIndex, MsgIndex: ev.MsgIndex}
+}
+
+// Filter selects the events of a stream, the fields set must all match
+type Filter struct {
+	AssetID  string         `json:"asset_id,omitempty"`
+	Owner    sdk.AccAddress `json:"owner,omitempty"`
+	Reporter sdk.AccAddress `json:"reporter,omitempty"`
+	Sender   sdk.AccAddress `json:"sender,omitempty"`
+	Action   string         `json:"action,omitempty"`
+}
+
+// ParseFilter reads the asset_id, owner, reporter, sender and action
+// parameters of a query
+func ParseFilter(values url.Values) (f Filter, err error) {
+	f.AssetID = values.Get("asset_id")
+	f.Action = values.Get("action")
+	if f.Owner, err = parseAddr(values.Get("owner")); err != nil {
+		return
+	}
+	if f.Reporter, err = parseAddr(values.Get("reporter")); err != nil {
+		return
+	}
+	f.Sender, err = parseAddr(values.Get("sender"))
+	return
+}
+
+func parseAddr(s string) (sdk.AccAddress, error) {
+	if s == "" {
+		return nil, nil
+	}
+	return sdk.AccAddressFromBech32(s)
+}
+
+// Matches returns whether the event passes the filter
+func (f Filter) Matches(ev Event) bool {
+	if f.AssetID != "" && !containsString(ev.AssetIDs, f.AssetID) {
+		return false
+	}
+	if f.Action != "" && f.Action != ev.Action {
+		return false
+	}
+	if len(f.Owner) > 0 && !bytes.Equal(f.Owner, ev.Owner) {
+		return false
+	}
+	if len(f.Reporter) > 0 && !bytes.Equal(f.Reporter, ev.Reporter) {
+		return false
+	}
+	if len(f.Sender) > 0 && !bytes.Equal(f.Sender, ev.Sender) {
+		return false
+	}
+	return true
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// newEvent types an asset message, ok is false for the other messages
+func newEvent(msg sdk.Msg) (ev Event, ok bool) {
+	switch msg := msg.(type) {
+	case asset.MsgCreateAsset:
+		ev = Event{Action: ActionCreateAsset, AssetIDs: []string{msg.AssetID}, Owner: msg.Owner}
+		if len(msg.Owner) == 0 {
+			ev.Owner = msg.Sender
+		}
+		if msg.Parent != "" {
+			ev.AssetIDs = append(ev.AssetIDs, msg.Parent)
+		}
+	case asset.MsgAddQuantity:
+		ev = Event{Action: ActionAddQuantity, AssetIDs: []string{msg.AssetID}}
+	case asset.MsgSubtractQuantity:
+		ev = Event{Action: ActionSubtractQuantity, AssetIDs: []string{msg.AssetID}}
+	case asset.MsgUpdateProperties:
+		ev = Event{Action: ActionUpdateProperties, AssetIDs: []string{msg.AssetID}}
+	case asset.MsgAddMaterials:
+		ev = Event{Action: ActionAddMaterials, AssetIDs: []string{msg.AssetID}}
+		for _, material := range msg.Amount {
+			ev.AssetIDs = append(ev.AssetIDs, material.RecordID)
+		}
+	case asset.MsgFinalize:
+		ev = Event{Action: ActionFinalize, AssetIDs: []string{msg.AssetID}}
+	case asset.MsgRevokeReporter:
+		ev = Event{Action: ActionRevokeReporter, AssetIDs: []string{msg.AssetID}, Reporter: msg.Reporter}
+	case asset.MsgCreateProposal:
+		ev = Event{Action: ActionCreateProposal, AssetIDs: []string{msg.AssetID}}
+		ev.setRole(msg.Role, msg.Recipient)
+	case asset.MsgAnswerProposal:
+		ev = Event{Action: ActionAnswerProposal, AssetIDs: []string{msg.AssetID}}
+		ev.setRole(msg.Role, msg.Recipient)
+	case asset.MsgAnchorDocument:
+		ev = Event{Action: ActionAnchorDocument, AssetIDs: []string{msg.AssetID}}
+	default:
+		return Event{}, false
+	}
+	if signers := msg.GetSigners(); len(signers) > 0 {
+		ev.Sender = signers[0]
+	}
+	return ev, true
+}
+
+func (ev *Event) setRole(role asset.ProposalRole, recipient sdk.AccAddress) {
+	if role == asset.RoleOwner {
+		ev.Owner = recipient
+	} else {
+		ev.Reporter = recipient
+	}
+}
+
+// TxEvents returns the asset events of a committed tx
+func TxEvents(cdc *wire.Codec, height, time int64, txIndex int, txHash string, stdTx sdk.Tx) ([]Event, error) {
+	var events []Event
+	for i, msg := range stdTx.GetMsgs() {
+		ev, ok := newEvent(msg)
+		if !ok {
+			continue
+		}
+		bz, err := cdc.MarshalJSON(msg)
+		if err != nil {
+			return nil, err
+		}
+		ev.Height = height
+		ev.Time = time
+		ev.TxHash = txHash
+		ev.TxIndex = txIndex
+		ev.MsgIndex = i
+		ev.ID = ev.Cursor().String()
+		ev.Msg = bz
+		events = append(events, ev)
+	}
+	return events, nil
+}
+
+// BlockEvents returns the asset events of the successful txs of a block
+func BlockEvents(cdc *wire.Codec, node rpcclient.Client, height int64) ([]Event, error) {
+	block, err := node.Block(&height)
+	if err != nil {
+		return nil, err
+	}
+	results, err := node.BlockResults(&height)
+	if err != nil {
+		return nil, err
+	}
+
+	txs := block.Block.Data.Txs
+	if len(results.Results.DeliverTx) != len(txs) {
+		return nil, fmt.Errorf("%d results for %d txs", len(results.Results.DeliverTx), len(txs))
+	}
+
+	var events []Event
+	for i, txBytes := range txs {
+		if !results.Results.DeliverTx[i].IsOK() {
+			continue
+		}
+		stdTx, err := tx.ParseTx(cdc, txBytes)
+		if err != nil {
+			return nil, err
+		}
+		hash := cmn.HexBytes(txBytes.Hash()).String()
+		txEvents, err := TxEvents(cdc, height, block.Block.Header.Time.Unix(), i, hash, stdTx)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, txEvents...)
+	}
+	return events, nil
+}
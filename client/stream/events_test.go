@@ -0,0 +1,105 @@
+package stream
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+
+	"github.com/icheckteam/ichain/app"
+	"github.com/icheckteam/ichain/x/asset"
+)
+
+var (
+	addr1 = sdk.AccAddress([]byte("addr1"))
+	addr2 = sdk.AccAddress([]byte("addr2"))
+)
+
+func TestTxEvents(t *testing.T) {
+	tx := auth.NewStdTx([]sdk.Msg{
+		asset.NewMsgCreateAsset(addr1, "asset2", "asset2", sdk.NewInt(10), "asset1"),
+		bank.MsgSend{},
+		asset.MsgCreateProposal{AssetID: "asset2", Sender: addr1, Recipient: addr2, Role: asset.RoleReporter},
+	}, auth.StdFee{}, nil, "")
+
+	events, err := TxEvents(app.MakeCodec(), 5, 50, 2, "HASH", tx)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(events))
+
+	create := events[0]
+	assert.Equal(t, ActionCreateAsset, create.Action)
+	assert.Equal(t, "5:2:0", create.ID)
+	assert.Equal(t, []string{"asset2", "asset1"}, create.AssetIDs)
+	assert.Equal(t, addr1, create.Owner)
+	assert.Equal(t, addr1, create.Sender)
+	assert.NotEmpty(t, create.Msg)
+
+	proposal := events[1]
+	assert.Equal(t, ActionCreateProposal, proposal.Action)
+	assert.Equal(t, "5:2:2", proposal.ID)
+	assert.Equal(t, addr2, proposal.Reporter)
+	assert.Empty(t, proposal.Owner)
+
+	assert.True(t, Filter{AssetID: "asset1"}.Matches(create))
+	assert.False(t, Filter{AssetID: "asset1"}.Matches(proposal))
+	assert.True(t, Filter{Reporter: addr2, Action: ActionCreateProposal}.Matches(proposal))
+	assert.False(t, Filter{Reporter: addr2, Action: ActionFinalize}.Matches(proposal))
+	assert.False(t, Filter{Owner: addr2}.Matches(create))
+	assert.True(t, Filter{}.Matches(create))
+}
+
+func TestParseFilter(t *testing.T) {
+	f, err := ParseFilter(url.Values{"asset_id": {"asset1"}, "reporter": {addr2.String()}})
+	require.Nil(t, err)
+	assert.Equal(t, "asset1", f.AssetID)
+	assert.Equal(t, addr2, f.Reporter)
+	assert.Empty(t, f.Owner)
+
+	_, err = ParseFilter(url.Values{"owner": {"invalid"}})
+	assert.NotNil(t, err)
+}
+
+func TestCursor(t *testing.T) {
+	c, err := ParseCursor("5:2:1")
+	require.Nil(t, err)
+	assert.Equal(t, Cursor{Height: 5, TxIndex: 2, MsgIndex: 1}, c)
+	assert.Equal(t, "5:2:1", c.String())
+
+	assert.True(t, FromHeight(5).Before(Cursor{Height: 5}))
+	assert.False(t, FromHeight(6).Before(c))
+	assert.True(t, c.Before(Cursor{Height: 5, TxIndex: 2, MsgIndex: 2}))
+	assert.False(t, c.Before(c))
+	assert.True(t, Cursor{}.IsZero())
+
+	_, err = ParseCursor("5:2")
+	assert.NotNil(t, err)
+}
+
+func TestPendingEventsBounded(t *testing.T) {
+	h := NewHub(context.NewCLIContext(), app.MakeCodec(), log.NewNopLogger())
+	sub := &Subscription{
+		hub:       h,
+		live:      make(chan Event, subscriptionBuffer),
+		replaying: true,
+	}
+	h.subs[sub] = struct{}{}
+
+	events := make([]Event, maxPendingEvents)
+	h.publish(1, events)
+	require.True(t, h.subscribed(sub))
+	assert.Equal(t, maxPendingEvents, len(sub.pending))
+
+	// a subscriber replaying slower than the blocks come is dropped
+	h.publish(2, events[:1])
+	assert.False(t, h.subscribed(sub))
+	assert.Empty(t, sub.pending)
+	assert.Equal(t, ErrSlowSubscriber, sub.Err())
+}
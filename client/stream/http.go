@@ -0,0 +1,214 @@
+package stream
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+)
+
+// keepAliveInterval is how often an idle stream is pinged, so proxies and
+// clients don't drop it
+const keepAliveInterval = 30 * time.Second
+
+var upgrader = websocket.Upgrader{
+	// the events are public chain data, any dashboard may read them
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// RegisterRoutes ...
+func RegisterRoutes(r *mux.Router, hub *Hub) {
+	r.HandleFunc("/events/assets", EventsHandler(hub)).Methods("GET")
+}
+
+// EventsHandler streams the asset events over WebSocket, or as Server-Sent
+// Events otherwise. The query filters the events with asset_id, owner,
+// reporter, sender and action. from_height replays the events from a
+// height, and last_event_id or the Last-Event-ID header the events after
+// an event, within the last MaxReplayBlocks blocks.
+func EventsHandler(hub *Hub) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		filter, err := ParseFilter(r.URL.Query())
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		after, err := parseResume(r)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		if err := hub.CheckReplay(after); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		if websocket.IsWebSocketUpgrade(r) {
+			serveWebSocket(hub, w, r, filter, after)
+			return
+		}
+		serveSSE(hub, w, filter, after)
+	}
+}
+
+func parseResume(r *http.Request) (Cursor, error) {
+	id := r.Header.Get("Last-Event-ID")
+	if id == "" {
+		id = r.URL.Query().Get("last_event_id")
+	}
+	if id != "" {
+		return ParseCursor(id)
+	}
+	if s := r.URL.Query().Get("from_height"); s != "" {
+		height, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || height < 1 {
+			return Cursor{}, fmt.Errorf("invalid from_height %s", s)
+		}
+		return FromHeight(height), nil
+	}
+	return Cursor{}, nil
+}
+
+func serveWebSocket(hub *Hub, w http.ResponseWriter, r *http.Request, filter Filter, after Cursor) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// the upgrader already replied
+		return
+	}
+	defer conn.Close()
+
+	sub, err := hub.Subscribe(filter, after)
+	if err != nil {
+		conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseInternalServerErr, err.Error()))
+		return
+	}
+	defer sub.Close()
+
+	// the client doesn't send anything, reading only notices it is gone
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(keepAliveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case ev, ok := <-sub.Events():
+			if !ok {
+				msg := ""
+				if sub.Err() != nil {
+					msg = sub.Err().Error()
+				}
+				conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseTryAgainLater, msg))
+				return
+			}
+			if err := conn.WriteJSON(ev); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		case <-closed:
+			return
+		}
+	}
+}
+
+func serveSSE(hub *Hub, w http.ResponseWriter, filter Filter, after Cursor) {
+	out, flush, closeConn, err := openSSE(w)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	defer closeConn()
+
+	sub, err := hub.Subscribe(filter, after)
+	if err != nil {
+		fmt.Fprintf(out, "event: error\ndata: %s\n\n", err.Error())
+		flush()
+		return
+	}
+	defer sub.Close()
+
+	ticker := time.NewTicker(keepAliveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case ev, ok := <-sub.Events():
+			if !ok {
+				if sub.Err() != nil {
+					fmt.Fprintf(out, "event: error\ndata: %s\n\n", sub.Err().Error())
+					flush()
+				}
+				return
+			}
+			bz, err := json.Marshal(ev)
+			if err != nil {
+				return
+			}
+			if _, err := fmt.Fprintf(out, "id: %s\nevent: %s\ndata: %s\n\n", ev.ID, ev.Action, bz); err != nil {
+				return
+			}
+			if err := flush(); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if _, err := io.WriteString(out, ": ping\n\n"); err != nil {
+				return
+			}
+			if err := flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// openSSE starts the event stream response. The LCD handler of the
+// tendermint rpc server can't flush, the connection is then hijacked and
+// the response written on it.
+func openSSE(w http.ResponseWriter) (out io.Writer, flush func() error, closeConn func(), err error) {
+	if flusher, ok := w.(http.Flusher); ok {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+		return w, func() error { flusher.Flush(); return nil }, func() {}, nil
+	}
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		return nil, nil, nil, fmt.Errorf("streaming unsupported")
+	}
+	conn, buf, err := hijacker.Hijack()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	writer := buf.Writer
+	_, err = io.WriteString(writer, "HTTP/1.1 200 OK\r\n"+
+		"Content-Type: text/event-stream\r\n"+
+		"Cache-Control: no-cache\r\n"+
+		"Connection: close\r\n\r\n")
+	if err == nil {
+		err = writer.Flush()
+	}
+	if err != nil {
+		conn.Close()
+		return nil, nil, nil, err
+	}
+	return writer, writer.Flush, func() { conn.Close() }, nil
+}
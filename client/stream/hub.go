@@ -0,0 +1,359 @@
+package stream
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/cosmos/cosmos-sdk/wire"
+)
+
+// subscriptionBuffer is how many live events a subscriber may lag behind
+const subscriptionBuffer = 1024
+
+// maxPendingEvents is how many live events are held back for a subscriber
+// still replaying
+const maxPendingEvents = 16 * subscriptionBuffer
+
+// MaxReplayBlocks is the most blocks a client of the events API may replay,
+// older events are read from the txs search
+const MaxReplayBlocks = 10000
+
+// ErrSlowSubscriber closes the subscriptions falling too far behind
+var ErrSlowSubscriber = errors.New("subscriber too slow, resume from the last event id")
+
+// Hub follows the committed blocks of the node and sends their asset
+// events to the subscriptions
+type Hub struct {
+	ctx          context.CLIContext
+	cdc          *wire.Codec
+	logger       log.Logger
+	pollInterval time.Duration
+
+	mtx    sync.Mutex
+	height int64 // last block sent to the subscriptions
+	subs   map[*Subscription]struct{}
+}
+
+// NewHub ...
+func NewHub(ctx context.CLIContext, cdc *wire.Codec, logger log.Logger) *Hub {
+	return &Hub{
+		ctx:          ctx,
+		cdc:          cdc,
+		logger:       logger,
+		pollInterval: time.Second,
+		subs:         map[*Subscription]struct{}{},
+	}
+}
+
+// WithPollInterval sets how long the hub waits for new blocks
+func (h *Hub) WithPollInterval(interval time.Duration) *Hub {
+	h.pollInterval = interval
+	return h
+}
+
+// Run follows the blocks until stop is closed, errors of the node are
+// logged and the block retried
+func (h *Hub) Run(stop <-chan struct{}) {
+	for {
+		if err := h.sync(stop); err != nil {
+			h.logger.Error("Failed to read the events", "err", err)
+		}
+		select {
+		case <-stop:
+			return
+		case <-time.After(h.pollInterval):
+		}
+	}
+}
+
+func (h *Hub) sync(stop <-chan struct{}) error {
+	latest, err := h.latestHeight()
+	if err != nil {
+		return err
+	}
+	h.mtx.Lock()
+	if h.height == 0 {
+		// live subscribers start with the next block
+		h.height = latest
+	}
+	next := h.height + 1
+	h.mtx.Unlock()
+
+	node, err := h.ctx.GetNode()
+	if err != nil {
+		return err
+	}
+	for height := next; height <= latest; height++ {
+		select {
+		case <-stop:
+			return nil
+		default:
+		}
+		events, err := BlockEvents(h.cdc, node, height)
+		if err != nil {
+			return err
+		}
+		h.publish(height, events)
+	}
+	return nil
+}
+
+// CheckReplay refuses the cursors more than MaxReplayBlocks behind the
+// last block
+func (h *Hub) CheckReplay(after Cursor) error {
+	if after.IsZero() {
+		return nil
+	}
+	h.mtx.Lock()
+	height := h.height
+	h.mtx.Unlock()
+	if height == 0 {
+		latest, err := h.latestHeight()
+		if err != nil {
+			return err
+		}
+		height = latest
+	}
+	if height-after.Height >= MaxReplayBlocks {
+		return fmt.Errorf("can't replay from height %d, at most the last %d blocks can be", after.Height, MaxReplayBlocks)
+	}
+	return nil
+}
+
+func (h *Hub) latestHeight() (int64, error) {
+	node, err := h.ctx.GetNode()
+	if err != nil {
+		return 0, err
+	}
+	status, err := node.Status()
+	if err != nil {
+		return 0, err
+	}
+	return status.SyncInfo.LatestBlockHeight, nil
+}
+
+// publish sends the events of a block and marks it as sent, both under the
+// lock so a new subscription replays exactly the blocks before it. The
+// events of a subscription still replaying are held back, up to
+// maxPendingEvents, they don't count against its buffer.
+func (h *Hub) publish(height int64, events []Event) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	for sub := range h.subs {
+		for _, ev := range events {
+			if !sub.filter.Matches(ev) {
+				continue
+			}
+			if sub.replaying {
+				if len(sub.pending) >= maxPendingEvents {
+					delete(h.subs, sub)
+					sub.pending = nil
+					sub.fail(ErrSlowSubscriber)
+					break
+				}
+				sub.pending = append(sub.pending, ev)
+				continue
+			}
+			select {
+			case sub.live <- ev:
+			default:
+				delete(h.subs, sub)
+				sub.fail(ErrSlowSubscriber)
+			}
+		}
+	}
+	h.height = height
+}
+
+// Subscribe streams the events matching the filter. A zero cursor only
+// streams the next blocks, otherwise the events after the cursor are
+// replayed first.
+func (h *Hub) Subscribe(filter Filter, after Cursor) (*Subscription, error) {
+	// the node is queried out of the lock, publish doesn't wait for it
+	h.mtx.Lock()
+	started := h.height > 0
+	h.mtx.Unlock()
+	var latest int64
+	if !started {
+		var err error
+		if latest, err = h.latestHeight(); err != nil {
+			return nil, err
+		}
+	}
+
+	h.mtx.Lock()
+	if h.height == 0 {
+		h.height = latest
+	}
+	sub := &Subscription{
+		hub:      h,
+		filter:   filter,
+		after:    after,
+		liveFrom: h.height + 1,
+		live:     make(chan Event, subscriptionBuffer),
+		out:      make(chan Event),
+		done:     make(chan struct{}),
+
+		replaying: !after.IsZero(),
+	}
+	h.subs[sub] = struct{}{}
+	replayTo := h.height
+	h.mtx.Unlock()
+
+	go sub.run(replayTo)
+	return sub, nil
+}
+
+func (h *Hub) unsubscribe(sub *Subscription) {
+	h.mtx.Lock()
+	delete(h.subs, sub)
+	h.mtx.Unlock()
+}
+
+// subscribed returns false once the hub dropped the subscription
+func (h *Hub) subscribed(sub *Subscription) bool {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	_, found := h.subs[sub]
+	return found
+}
+
+// Subscription is the stream of events of a subscriber, Events is closed
+// when the subscription fails or is closed
+type Subscription struct {
+	hub      *Hub
+	filter   Filter
+	after    Cursor
+	liveFrom int64
+	live     chan Event
+	out      chan Event
+
+	// the live events published during the replay, guarded by the hub
+	replaying bool
+	pending   []Event
+
+	closeOnce sync.Once
+	done      chan struct{}
+	failOnce  sync.Once
+	err       error
+}
+
+// Events ...
+func (s *Subscription) Events() <-chan Event {
+	return s.out
+}
+
+// LiveFrom is the first height streamed live, the blocks before it are
+// replayed
+func (s *Subscription) LiveFrom() int64 {
+	return s.liveFrom
+}
+
+// Err returns why the events were closed, nil once closed by the subscriber
+func (s *Subscription) Err() error {
+	return s.err
+}
+
+// Close stops the subscription
+func (s *Subscription) Close() {
+	s.hub.unsubscribe(s)
+	s.closeOnce.Do(func() { close(s.done) })
+}
+
+func (s *Subscription) fail(err error) {
+	s.failOnce.Do(func() {
+		s.err = err
+		close(s.live)
+	})
+}
+
+func (s *Subscription) send(ev Event) bool {
+	select {
+	case s.out <- ev:
+		return true
+	case <-s.done:
+		return false
+	}
+}
+
+// run replays the blocks up to the one the hub sent last, then the live
+// events held back meanwhile and the ones queued from then on
+func (s *Subscription) run(replayTo int64) {
+	defer close(s.out)
+
+	if !s.after.IsZero() {
+		node, err := s.hub.ctx.GetNode()
+		if err != nil {
+			s.hub.unsubscribe(s)
+			s.fail(err)
+			return
+		}
+		for height := s.after.Height; height <= replayTo; height++ {
+			if height < 1 {
+				continue
+			}
+			if !s.hub.subscribed(s) {
+				// too slow, or closed
+				return
+			}
+			events, err := BlockEvents(s.hub.cdc, node, height)
+			if err != nil {
+				s.hub.unsubscribe(s)
+				s.fail(err)
+				return
+			}
+			for _, ev := range events {
+				if !s.after.Before(ev.Cursor()) || !s.filter.Matches(ev) {
+					continue
+				}
+				if !s.send(ev) {
+					return
+				}
+			}
+		}
+		if !s.sendPending() {
+			return
+		}
+	}
+
+	for {
+		select {
+		case ev, ok := <-s.live:
+			if !ok {
+				return
+			}
+			if !s.send(ev) {
+				return
+			}
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// sendPending sends the events held back during the replay until none is
+// left, the next ones go through the live buffer
+func (s *Subscription) sendPending() bool {
+	for {
+		s.hub.mtx.Lock()
+		pending := s.pending
+		s.pending = nil
+		if len(pending) == 0 {
+			s.replaying = false
+		}
+		s.hub.mtx.Unlock()
+		if len(pending) == 0 {
+			return true
+		}
+		for _, ev := range pending {
+			if !s.send(ev) {
+				return false
+			}
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package tx
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+)
+
+// maxCachedBlockTimes bounds the cache, about 16 bytes a block
+const maxCachedBlockTimes = 1 << 20
+
+// blockTimeCache keeps the time of the blocks already read, it never
+// changes once the block is committed
+type blockTimeCache struct {
+	mtx   sync.Mutex
+	times map[int64]int64
+}
+
+var blockTimes = &blockTimeCache{times: map[int64]int64{}}
+
+func (c *blockTimeCache) get(height int64) (int64, bool) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	t, ok := c.times[height]
+	return t, ok
+}
+
+func (c *blockTimeCache) set(height, t int64) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if len(c.times) >= maxCachedBlockTimes {
+		// drop any block, the cache is only there to save queries
+		for h := range c.times {
+			delete(c.times, h)
+			break
+		}
+	}
+	c.times[height] = t
+}
+
+// BlockTime returns the unix time of the block at the height
+func BlockTime(ctx context.CLIContext, height int64) (int64, error) {
+	if t, ok := blockTimes.get(height); ok {
+		return t, nil
+	}
+	node, err := ctx.GetNode()
+	if err != nil {
+		return 0, err
+	}
+	res, err := node.BlockchainInfo(height, height)
+	if err != nil {
+		return 0, err
+	}
+	if len(res.BlockMetas) == 0 {
+		return 0, fmt.Errorf("block %d not found", height)
+	}
+	t := res.BlockMetas[0].Header.Time.Unix()
+	blockTimes.set(height, t)
+	return t, nil
+}
+
+// SetTimes fills the Time of the txs with the time of their block
+func SetTimes(ctx context.CLIContext, infos []TxInfo) error {
+	for i := range infos {
+		t, err := BlockTime(ctx, infos[i].Height)
+		if err != nil {
+			return err
+		}
+		infos[i].Time = t
+	}
+	return nil
+}
+
+// firstHeightFrom returns the first block at or after the time, 0 when
+// there is none yet
+func firstHeightFrom(ctx context.CLIContext, t time.Time) (int64, error) {
+	latest, err := latestHeight(ctx)
+	if err != nil {
+		return 0, err
+	}
+	i, err := searchHeights(ctx, latest, func(blockTime int64) bool {
+		return blockTime >= t.Unix()
+	})
+	if err != nil || i > latest {
+		return 0, err
+	}
+	return i, nil
+}
+
+// lastHeightUntil returns the last block at or before the time, 0 when the
+// chain started after it
+func lastHeightUntil(ctx context.CLIContext, t time.Time) (int64, error) {
+	latest, err := latestHeight(ctx)
+	if err != nil {
+		return 0, err
+	}
+	i, err := searchHeights(ctx, latest, func(blockTime int64) bool {
+		return blockTime > t.Unix()
+	})
+	if err != nil {
+		return 0, err
+	}
+	return i - 1, nil
+}
+
+// searchHeights returns the first height in [1, latest] whose block time
+// satisfies f, latest+1 when there is none. Block times only grow.
+func searchHeights(ctx context.CLIContext, latest int64, f func(blockTime int64) bool) (int64, error) {
+	var err error
+	i := sort.Search(int(latest), func(i int) bool {
+		if err != nil {
+			return true
+		}
+		var t int64
+		t, err = BlockTime(ctx, int64(i)+1)
+		return err != nil || f(t)
+	})
+	return int64(i) + 1, err
+}
+
+func latestHeight(ctx context.CLIContext) (int64, error) {
+	node, err := ctx.GetNode()
+	if err != nil {
+		return 0, err
+	}
+	status, err := node.Status()
+	if err != nil {
+		return 0, err
+	}
+	return status.SyncInfo.LatestBlockHeight, nil
+}
@@ -1,19 +1,166 @@
 package tx
 
 import (
+	"encoding/base64"
 	"encoding/json"
+	"fmt"
+	"io/ioutil"
 	"net/http"
 
 	"github.com/cosmos/cosmos-sdk/client/context"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	abci "github.com/tendermint/tendermint/abci/types"
+	cmn "github.com/tendermint/tendermint/libs/common"
 )
 
-// BroadcastTxBody Broadcast Body
+// Broadcast modes, a request picks one with its "mode" field
+const (
+	// BroadcastAsync returns once the tx is sent to the node, before CheckTx
+	BroadcastAsync = "async"
+	// BroadcastSync returns the result of CheckTx
+	BroadcastSync = "sync"
+	// BroadcastBlock waits for the tx to be committed in a block
+	BroadcastBlock = "block"
+)
+
+// ValidateBroadcastMode returns an error when the mode is unknown, an empty
+// mode is the block mode
+func ValidateBroadcastMode(mode string) error {
+	switch mode {
+	case "", BroadcastAsync, BroadcastSync, BroadcastBlock:
+		return nil
+	}
+	return fmt.Errorf("invalid broadcast mode %s, expected %s, %s or %s",
+		mode, BroadcastAsync, BroadcastSync, BroadcastBlock)
+}
+
+// Tag is a tag of a delivered tx
+type Tag struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// BroadcastOutput is the result of a broadcast whatever the mode. Height,
+// gas and tags are only known in block mode, and Code is 0 in async mode
+// as the tx isn't checked yet: clients poll GET /txs/{hash} for the result.
+type BroadcastOutput struct {
+	Hash      string            `json:"hash"`
+	Height    int64             `json:"height"`
+	Code      sdk.CodeType      `json:"code"`
+	Codespace sdk.CodespaceType `json:"codespace"`
+	Log       string            `json:"log"`
+	GasWanted int64             `json:"gas_wanted"`
+	GasUsed   int64             `json:"gas_used"`
+	Tags      []Tag             `json:"tags"`
+}
+
+// IsOK returns whether the tx passed the checks done in its mode
+func (o BroadcastOutput) IsOK() bool {
+	return o.Code == sdk.CodeOK && o.Codespace == 0
+}
+
+// setCode splits the ABCI code, the codespace is in its upper 16 bits
+func (o *BroadcastOutput) setCode(code uint32) {
+	o.Codespace = sdk.CodespaceType(code >> 16)
+	o.Code = sdk.CodeType(code & 0xFFFF)
+}
+
+func newTags(pairs cmn.KVPairs) []Tag {
+	tags := make([]Tag, 0, len(pairs))
+	for _, pair := range pairs {
+		tags = append(tags, Tag{Key: string(pair.Key), Value: string(pair.Value)})
+	}
+	return tags
+}
+
+// Broadcast sends the tx to the node in the given mode. Unlike
+// ctx.BroadcastTx a tx failing CheckTx or DeliverTx isn't an error, the
+// code and the log are in the output.
+func Broadcast(ctx context.CLIContext, txBytes []byte, mode string) (BroadcastOutput, error) {
+	if err := ValidateBroadcastMode(mode); err != nil {
+		return BroadcastOutput{}, err
+	}
+	node, err := ctx.GetNode()
+	if err != nil {
+		return BroadcastOutput{}, err
+	}
+
+	var output BroadcastOutput
+	switch mode {
+	case BroadcastAsync, BroadcastSync:
+		broadcast := node.BroadcastTxSync
+		if mode == BroadcastAsync {
+			broadcast = node.BroadcastTxAsync
+		}
+		res, err := broadcast(txBytes)
+		if err != nil {
+			return BroadcastOutput{}, err
+		}
+		output.Hash = res.Hash.String()
+		output.Log = res.Log
+		output.setCode(res.Code)
+	default:
+		res, err := node.BroadcastTxCommit(txBytes)
+		if err != nil {
+			return BroadcastOutput{}, err
+		}
+		output.Hash = res.Hash.String()
+		output.Height = res.Height
+		result := res.DeliverTx
+		if res.CheckTx.IsErr() {
+			result = abci.ResponseDeliverTx{
+				Code:      res.CheckTx.Code,
+				Log:       res.CheckTx.Log,
+				GasWanted: res.CheckTx.GasWanted,
+				GasUsed:   res.CheckTx.GasUsed,
+			}
+		}
+		output.setCode(result.Code)
+		output.Log = result.Log
+		output.GasWanted = result.GasWanted
+		output.GasUsed = result.GasUsed
+		output.Tags = newTags(result.Tags)
+	}
+	return output, nil
+}
+
+// WriteBroadcastResponse broadcasts the tx and writes its BroadcastOutput,
+// with a 400 status when the tx was rejected
+func WriteBroadcastResponse(w http.ResponseWriter, cdc *wire.Codec, ctx context.CLIContext, txBytes []byte, mode string) {
+	if err := ValidateBroadcastMode(mode); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	res, err := Broadcast(ctx, txBytes, mode)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("BroadcastTx:" + err.Error()))
+		return
+	}
+
+	output, err := wire.MarshalJSONIndent(cdc, res)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	if !res.IsOK() {
+		w.WriteHeader(http.StatusBadRequest)
+	}
+	w.Write(output)
+}
+
+// BroadcastTxBody Broadcast Body, Tx is the base64 of the amino encoded tx
 type BroadcastTxBody struct {
 	TxBytes string `json:"tx"`
+	Mode    string `json:"mode"`
 }
 
 // BroadcastTxRequestHandlerFn BroadcastTx REST Handler
-func BroadcastTxRequestHandlerFn(ctx context.CLIContext) http.HandlerFunc {
+func BroadcastTxRequestHandlerFn(cdc *wire.Codec, ctx context.CLIContext) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var m BroadcastTxBody
 
@@ -24,14 +171,52 @@ func BroadcastTxRequestHandlerFn(ctx context.CLIContext) http.HandlerFunc {
 			w.Write([]byte(err.Error()))
 			return
 		}
+		txBytes, err := base64.StdEncoding.DecodeString(m.TxBytes)
+		if err != nil {
+			w.WriteHeader(400)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		WriteBroadcastResponse(w, cdc, ctx, txBytes, m.Mode)
+	}
+}
+
+// BroadcastSignedTxBody is a tx signed by the client, as generated by the
+// generate_only mode of the REST endpoints
+type BroadcastSignedTxBody struct {
+	Tx   auth.StdTx `json:"tx"`
+	Mode string     `json:"mode"`
+}
+
+// BroadcastSignedTxRequestHandlerFn broadcasts a tx signed by the client,
+// the LCD never sees the keys
+func BroadcastSignedTxRequestHandlerFn(cdc *wire.Codec, ctx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var m BroadcastSignedTxBody
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(400)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		if err := cdc.UnmarshalJSON(body, &m); err != nil {
+			w.WriteHeader(400)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		if len(m.Tx.GetSignatures()) == 0 {
+			w.WriteHeader(400)
+			w.Write([]byte("the tx is not signed"))
+			return
+		}
 
-		res, err := ctx.BroadcastTx([]byte(m.TxBytes))
+		txBytes, err := cdc.MarshalBinary(m.Tx)
 		if err != nil {
 			w.WriteHeader(500)
 			w.Write([]byte(err.Error()))
 			return
 		}
-
-		w.Write([]byte(string(res.Height)))
+		WriteBroadcastResponse(w, cdc, ctx, txBytes, m.Mode)
 	}
 }
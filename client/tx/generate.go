@@ -0,0 +1,47 @@
+package tx
+
+import (
+	"net/http"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	authctx "github.com/cosmos/cosmos-sdk/x/auth/client/context"
+)
+
+// GenerateOnlyOutput is the unsigned transaction returned to wallets that
+// sign on their side, they sign SignBytes and post the tx with its
+// signature to /txs
+type GenerateOnlyOutput struct {
+	Tx        auth.StdTx `json:"tx"`
+	SignBytes string     `json:"sign_bytes"`
+}
+
+// NewGenerateOnlyOutput builds the unsigned tx of the msgs
+func NewGenerateOnlyOutput(txCtx authctx.TxContext, msgs []sdk.Msg) (GenerateOnlyOutput, error) {
+	signMsg, err := txCtx.Build(msgs)
+	if err != nil {
+		return GenerateOnlyOutput{}, err
+	}
+	return GenerateOnlyOutput{
+		Tx:        auth.NewStdTx(signMsg.Msgs, signMsg.Fee, nil, signMsg.Memo),
+		SignBytes: string(signMsg.Bytes()),
+	}, nil
+}
+
+// WriteGenerateOnlyResponse writes the unsigned tx of the msgs
+func WriteGenerateOnlyResponse(w http.ResponseWriter, cdc *wire.Codec, txCtx authctx.TxContext, msgs []sdk.Msg) {
+	output, err := NewGenerateOnlyOutput(txCtx, msgs)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	bz, err := wire.MarshalJSONIndent(cdc, output)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	w.Write(bz)
+}
@@ -20,6 +20,7 @@ func AddCommands(cmd *cobra.Command, cdc *wire.Codec) {
 func RegisterRoutes(ctx context.CLIContext, r *mux.Router, cdc *wire.Codec) {
 	r.HandleFunc("/txs/{hash}", QueryTxRequestHandlerFn(cdc, ctx)).Methods("GET")
 	r.HandleFunc("/txs", SearchTxRequestHandlerFn(ctx, cdc)).Methods("GET")
+	r.HandleFunc("/txs", BroadcastSignedTxRequestHandlerFn(cdc, ctx)).Methods("POST")
+	r.HandleFunc("/txs/broadcast", BroadcastTxRequestHandlerFn(cdc, ctx)).Methods("POST")
 	// r.HandleFunc("/txs/sign", SignTxRequstHandler).Methods("POST")
-	// r.HandleFunc("/txs/broadcast", BroadcastTxRequestHandler).Methods("POST")
 }
@@ -5,7 +5,10 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -19,23 +22,74 @@ import (
 )
 
 const (
-	flagTags = "tag"
-	flagAny  = "any"
+	flagTags      = "tag"
+	flagAny       = "any"
+	flagPage      = "page"
+	flagLimit     = "limit"
+	flagMinHeight = "min-height"
+	flagMaxHeight = "max-height"
+	flagMinTime   = "min-time"
+	flagMaxTime   = "max-time"
 )
 
+const (
+	// DefaultSearchLimit is the number of txs of a page when no limit is given
+	DefaultSearchLimit = 30
+	// MaxSearchLimit is the most txs the node returns in a page
+	MaxSearchLimit = 100
+	// MaxSearchResults bounds page*limit of the searches merging several
+	// queries, they read the txs of all the pages before the requested one
+	MaxSearchResults = 10000
+)
+
+// SearchParams selects the txs to search. Txs match all the tags, or any
+// of them with Any, and are in the height and time ranges, a zero bound
+// isn't checked. Page starts at 1.
+type SearchParams struct {
+	Tags      []string
+	Any       bool
+	MinHeight int64
+	MaxHeight int64
+	MinTime   time.Time
+	MaxTime   time.Time
+	Page      int
+	Limit     int
+}
+
+// SearchResult is a page of txs sorted by height. TotalCount is the number
+// of txs matching the search in all the pages, -1 when it isn't known.
+type SearchResult struct {
+	Txs        []TxInfo `json:"txs"`
+	TotalCount int      `json:"total_count"`
+}
+
 // SearchTxCmd default client command to search through tagged transactions
 func SearchTxCmd(cdc *wire.Codec) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "txs",
 		Short: "Search for all transactions that match the given tags",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			tags := viper.GetStringSlice(flagTags)
+			params := SearchParams{
+				Tags:      viper.GetStringSlice(flagTags),
+				Any:       viper.GetBool(flagAny),
+				MinHeight: viper.GetInt64(flagMinHeight),
+				MaxHeight: viper.GetInt64(flagMaxHeight),
+				Page:      viper.GetInt(flagPage),
+				Limit:     viper.GetInt(flagLimit),
+			}
+			var err error
+			if params.MinTime, err = parseTime(viper.GetString(flagMinTime)); err != nil {
+				return err
+			}
+			if params.MaxTime, err = parseTime(viper.GetString(flagMaxTime)); err != nil {
+				return err
+			}
 
-			txs, err := searchTxs(context.NewCLIContext(), cdc, tags)
+			res, err := SearchTxs(context.NewCLIContext(), cdc, params)
 			if err != nil {
 				return err
 			}
-			output, err := cdc.MarshalJSON(txs)
+			output, err := cdc.MarshalJSON(res)
 			if err != nil {
 				return err
 			}
@@ -50,44 +104,187 @@ func SearchTxCmd(cdc *wire.Codec) *cobra.Command {
 	cmd.Flags().Bool(client.FlagTrustNode, true, "Don't verify proofs for responses")
 	cmd.Flags().StringSlice(flagTags, nil, "Tags that must match (may provide multiple)")
 	cmd.Flags().Bool(flagAny, false, "Return transactions that match ANY tag, rather than ALL")
+	cmd.Flags().Int(flagPage, 1, "Page of the results, starting at 1")
+	cmd.Flags().Int(flagLimit, DefaultSearchLimit, fmt.Sprintf("Transactions per page, at most %d", MaxSearchLimit))
+	cmd.Flags().Int64(flagMinHeight, 0, "Only the transactions at or above this height")
+	cmd.Flags().Int64(flagMaxHeight, 0, "Only the transactions at or below this height")
+	cmd.Flags().String(flagMinTime, "", "Only the transactions at or after this time, RFC3339 or unix")
+	cmd.Flags().String(flagMaxTime, "", "Only the transactions at or before this time, RFC3339 or unix")
 	return cmd
 }
 
-func searchTxs(ctx context.CLIContext, cdc *wire.Codec, tags []string) ([]TxInfo, error) {
-	if len(tags) == 0 {
-		return nil, errors.New("Must declare at least one tag to search")
+// SearchTxs returns a page of the txs matching the params. The node only
+// knows AND queries: with Any each tag is searched on its own and the
+// results are merged, which reads page*limit txs of every tag.
+func SearchTxs(ctx context.CLIContext, cdc *wire.Codec, params SearchParams) (SearchResult, error) {
+	if len(params.Tags) == 0 {
+		return SearchResult{}, errors.New("Must declare at least one tag to search")
+	}
+	if params.Page == 0 {
+		params.Page = 1
+	}
+	if params.Limit == 0 {
+		params.Limit = DefaultSearchLimit
+	}
+	if err := checkPage(params.Page, params.Limit, params.merged()); err != nil {
+		return SearchResult{}, err
+	}
+
+	// the node doesn't index the time, it is turned into a height range
+	minHeight, maxHeight := params.MinHeight, params.MaxHeight
+	if !params.MinTime.IsZero() {
+		height, err := firstHeightFrom(ctx, params.MinTime)
+		if err != nil {
+			return SearchResult{}, err
+		}
+		if height == 0 {
+			return SearchResult{Txs: []TxInfo{}}, nil
+		}
+		if height > minHeight {
+			minHeight = height
+		}
+	}
+	if !params.MaxTime.IsZero() {
+		height, err := lastHeightUntil(ctx, params.MaxTime)
+		if err != nil {
+			return SearchResult{}, err
+		}
+		if height == 0 {
+			return SearchResult{Txs: []TxInfo{}}, nil
+		}
+		if maxHeight == 0 || height < maxHeight {
+			maxHeight = height
+		}
+	}
+	if maxHeight > 0 && minHeight > maxHeight {
+		return SearchResult{Txs: []TxInfo{}}, nil
 	}
-	// XXX: implement ANY
-	query := strings.Join(tags, " AND ")
+
+	var conditions []string
+	if minHeight > 0 {
+		conditions = append(conditions, fmt.Sprintf("tx.height>=%d", minHeight))
+	}
+	if maxHeight > 0 {
+		conditions = append(conditions, fmt.Sprintf("tx.height<=%d", maxHeight))
+	}
+	var queries []string
+	if params.Any {
+		for _, tag := range params.Tags {
+			queries = append(queries, strings.Join(append([]string{tag}, conditions...), " AND "))
+		}
+	} else {
+		queries = []string{strings.Join(append(params.Tags, conditions...), " AND ")}
+	}
+
 	// get the node
 	node, err := ctx.GetNode()
 	if err != nil {
-		return nil, err
+		return SearchResult{}, err
+	}
+	prove := !ctx.TrustNode
+
+	var (
+		txs   []*ctypes.ResultTx
+		total int
+	)
+	if len(queries) == 1 {
+		res, err := node.TxSearch(queries[0], prove, params.Page, params.Limit)
+		if err != nil {
+			return SearchResult{}, err
+		}
+		txs, total = res.Txs, res.TotalCount
+	} else {
+		txs, total, err = searchAny(ctx, queries, prove, params.Page, params.Limit)
+		if err != nil {
+			return SearchResult{}, err
+		}
 	}
 
-	prove := !viper.GetBool(client.FlagTrustNode)
-	// TODO: take these as args
-	page := 0
-	perPage := 100
-	res, err := node.TxSearch(query, prove, page, perPage)
+	info, err := FormatTxResults(cdc, txs)
 	if err != nil {
-		return nil, err
+		return SearchResult{}, err
+	}
+	if err := SetTimes(ctx, info); err != nil {
+		return SearchResult{}, err
 	}
+	return SearchResult{Txs: info, TotalCount: total}, nil
+}
 
-	info, err := FormatTxResults(cdc, res.Txs)
+// merged returns whether the search merges the results of several queries
+func (params SearchParams) merged() bool {
+	return params.Any && len(params.Tags) > 1
+}
+
+// checkPage checks the page and the limit once the defaults are applied,
+// the node pages a single query itself so only a merged search is bounded
+func checkPage(page, limit int, merged bool) error {
+	if page < 0 {
+		return fmt.Errorf("invalid page %d", page)
+	}
+	if limit < 0 || limit > MaxSearchLimit {
+		return fmt.Errorf("invalid limit %d, expected at most %d", limit, MaxSearchLimit)
+	}
+	if merged && page > MaxSearchResults/limit {
+		return fmt.Errorf("invalid page %d, only the first %d txs can be searched", page, MaxSearchResults)
+	}
+	return nil
+}
+
+// searchAny merges the txs of the queries, the total is only known once
+// all the txs of every query are read
+func searchAny(ctx context.CLIContext, queries []string, prove bool, page, limit int) ([]*ctypes.ResultTx, int, error) {
+	node, err := ctx.GetNode()
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
-	for index := range info {
-		block, err := GetBlock(ctx, &info[index].Height)
-		if err != nil {
-			return nil, err
+	needed := page * limit
+	exhausted := true
+	seen := map[string]*ctypes.ResultTx{}
+	for _, query := range queries {
+		read := 0
+		for p := 1; read < needed; p++ {
+			res, err := node.TxSearch(query, prove, p, MaxSearchLimit)
+			if err != nil {
+				return nil, 0, err
+			}
+			for _, tx := range res.Txs {
+				seen[tx.Hash.String()] = tx
+			}
+			read += len(res.Txs)
+			if len(res.Txs) < MaxSearchLimit || read >= res.TotalCount {
+				break
+			}
+		}
+		if read >= needed {
+			exhausted = false
 		}
-		info[index].Time = block.Block.Time.Unix()
 	}
 
-	return info, nil
+	txs := make([]*ctypes.ResultTx, 0, len(seen))
+	for _, tx := range seen {
+		txs = append(txs, tx)
+	}
+	sort.Slice(txs, func(i, j int) bool {
+		if txs[i].Height != txs[j].Height {
+			return txs[i].Height < txs[j].Height
+		}
+		return txs[i].Index < txs[j].Index
+	})
+
+	total := -1
+	if exhausted {
+		total = len(txs)
+	}
+	start := (page - 1) * limit
+	if start > len(txs) {
+		start = len(txs)
+	}
+	end := start + limit
+	if end > len(txs) {
+		end = len(txs)
+	}
+	return txs[start:end], total, nil
 }
 
 // FormatTxResults ...
@@ -103,52 +300,58 @@ func FormatTxResults(cdc *wire.Codec, res []*ctypes.ResultTx) ([]TxInfo, error)
 	return out, nil
 }
 
+// parseTime reads a RFC3339 or unix time, an empty string is the zero time
+func parseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if unix, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(unix, 0), nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %s, expected RFC3339 or unix", s)
+	}
+	return t, nil
+}
+
 /////////////////////////////////////////
 // REST
 
-// SearchTxRequestHandlerFn Search Tx REST Handler
+// SearchTxRequestHandlerFn Search Tx REST Handler. The query takes one or
+// more tag, any=true, page, limit, min_height, max_height, min_time and
+// max_time. The total count of the txs is in the X-Total-Count header.
 func SearchTxRequestHandlerFn(ctx context.CLIContext, cdc *wire.Codec) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		tag := r.FormValue("tag")
-		if tag == "" {
+		r.ParseForm()
+		if len(r.Form["tag"]) == 0 {
 			w.WriteHeader(400)
 			w.Write([]byte("You need to provide at least a tag as a key=value pair to search for. Postfix the key with _bech32 to search bech32-encoded addresses or public keys"))
 			return
 		}
-		keyValue := strings.Split(tag, "=")
-		key := keyValue[0]
-		value, err := url.QueryUnescape(keyValue[1])
+		params, err := parseSearchParams(r)
 		if err != nil {
 			w.WriteHeader(400)
-			w.Write([]byte("Could not decode address: " + err.Error()))
+			w.Write([]byte(err.Error()))
 			return
 		}
-		if strings.HasSuffix(key, "_bech32") {
-			bech32address := strings.Trim(value, "'")
-			prefix := strings.Split(bech32address, "1")[0]
-			bz, err := sdk.GetFromBech32(bech32address, prefix)
-			if err != nil {
-				w.WriteHeader(400)
-				w.Write([]byte(err.Error()))
-				return
-			}
-
-			tag = strings.TrimRight(key, "_bech32") + "='" + sdk.AccAddress(bz).String() + "'"
-		}
 
-		txs, err := searchTxs(ctx, cdc, []string{tag})
+		res, err := SearchTxs(ctx, cdc, params)
 		if err != nil {
 			w.WriteHeader(500)
 			w.Write([]byte(err.Error()))
 			return
 		}
+		if res.TotalCount >= 0 {
+			w.Header().Set("X-Total-Count", strconv.Itoa(res.TotalCount))
+		}
 
-		if len(txs) == 0 {
+		if len(res.Txs) == 0 {
 			w.Write([]byte("[]"))
 			return
 		}
 
-		output, err := cdc.MarshalJSON(txs)
+		output, err := cdc.MarshalJSON(res.Txs)
 		if err != nil {
 			w.WriteHeader(500)
 			w.Write([]byte(err.Error()))
@@ -159,6 +362,84 @@ func SearchTxRequestHandlerFn(ctx context.CLIContext, cdc *wire.Codec) http.Hand
 	}
 }
 
+func parseSearchParams(r *http.Request) (params SearchParams, err error) {
+	for _, tag := range r.Form["tag"] {
+		tag, err = parseTag(tag)
+		if err != nil {
+			return
+		}
+		params.Tags = append(params.Tags, tag)
+	}
+	params.Any = r.FormValue("any") == "true"
+	if params.Page, err = formInt(r, "page"); err != nil {
+		return
+	}
+	if params.Limit, err = formInt(r, "limit"); err != nil {
+		return
+	}
+	page, limit := params.Page, params.Limit
+	if page == 0 {
+		page = 1
+	}
+	if limit == 0 {
+		limit = DefaultSearchLimit
+	}
+	if err = checkPage(page, limit, params.merged()); err != nil {
+		return
+	}
+	var height int
+	if height, err = formInt(r, "min_height"); err != nil {
+		return
+	}
+	params.MinHeight = int64(height)
+	if height, err = formInt(r, "max_height"); err != nil {
+		return
+	}
+	params.MaxHeight = int64(height)
+	if params.MinTime, err = parseTime(r.FormValue("min_time")); err != nil {
+		return
+	}
+	params.MaxTime, err = parseTime(r.FormValue("max_time"))
+	return
+}
+
+// parseTag turns a key_bech32='address' tag into the key='address' tag
+// indexed by the node
+func parseTag(tag string) (string, error) {
+	keyValue := strings.SplitN(tag, "=", 2)
+	if len(keyValue) != 2 {
+		return "", fmt.Errorf("invalid tag %s, expected key=value", tag)
+	}
+	key := keyValue[0]
+	value, err := url.QueryUnescape(keyValue[1])
+	if err != nil {
+		return "", errors.New("Could not decode address: " + err.Error())
+	}
+	if strings.HasSuffix(key, "_bech32") {
+		bech32address := strings.Trim(value, "'")
+		prefix := strings.Split(bech32address, "1")[0]
+		bz, err := sdk.GetFromBech32(bech32address, prefix)
+		if err != nil {
+			return "", err
+		}
+
+		tag = strings.TrimSuffix(key, "_bech32") + "='" + sdk.AccAddress(bz).String() + "'"
+	}
+	return tag, nil
+}
+
+func formInt(r *http.Request, key string) (int, error) {
+	s := r.FormValue(key)
+	if s == "" {
+		return 0, nil
+	}
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %s", key, s)
+	}
+	return i, nil
+}
+
 // GetBlock ...
 func GetBlock(ctx context.CLIContext, height *int64) (*ctypes.ResultBlock, error) {
 	// get the node
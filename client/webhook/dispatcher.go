@@ -0,0 +1,289 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/icheckteam/ichain/client/stream"
+)
+
+const (
+	// DefaultMaxAttempts is how many times a delivery is tried
+	DefaultMaxAttempts = 10
+	// DefaultBackoff is the wait after the first failed attempt, it
+	// doubles after each attempt
+	DefaultBackoff = 10 * time.Second
+	// MaxBackoff bounds the wait between two attempts
+	MaxBackoff = time.Hour
+
+	// batchSize is how many due deliveries of a subscription are read at
+	// once
+	batchSize = 100
+	// maxConcurrentDeliveries is how many subscriptions are posted to at once
+	maxConcurrentDeliveries = 16
+)
+
+// Dispatcher queues the events of the hub for the matching subscriptions
+// and posts them to their URLs
+type Dispatcher struct {
+	hub          *stream.Hub
+	store        *Store
+	client       *http.Client
+	logger       log.Logger
+	maxAttempts  int
+	backoff      time.Duration
+	pollInterval time.Duration
+	now          func() time.Time
+}
+
+// NewDispatcher ...
+func NewDispatcher(hub *stream.Hub, store *Store, logger log.Logger) *Dispatcher {
+	return &Dispatcher{
+		hub:          hub,
+		store:        store,
+		client:       newClient(false),
+		logger:       logger,
+		maxAttempts:  DefaultMaxAttempts,
+		backoff:      DefaultBackoff,
+		pollInterval: time.Second,
+		now:          time.Now,
+	}
+}
+
+// WithRetries sets how many times a delivery is tried and the first wait
+// between two attempts
+func (d *Dispatcher) WithRetries(maxAttempts int, backoff time.Duration) *Dispatcher {
+	d.maxAttempts = maxAttempts
+	d.backoff = backoff
+	return d
+}
+
+// WithPrivateTargets lets the deliveries reach loopback, private and
+// link-local addresses, for endpoints on the network of the LCD
+func (d *Dispatcher) WithPrivateTargets(allow bool) *Dispatcher {
+	d.client = newClient(allow)
+	return d
+}
+
+// WithPollInterval sets how often the queue is checked for due deliveries
+func (d *Dispatcher) WithPollInterval(interval time.Duration) *Dispatcher {
+	d.pollInterval = interval
+	return d
+}
+
+// Run queues the events and delivers them until stop is closed
+func (d *Dispatcher) Run(stop <-chan struct{}) {
+	go d.follow(stop)
+	for {
+		if err := d.DeliverDue(); err != nil {
+			d.logger.Error("Failed to deliver the webhooks", "err", err)
+		}
+		select {
+		case <-stop:
+			return
+		case <-time.After(d.pollInterval):
+		}
+	}
+}
+
+// follow queues the events of the hub from the stored cursor, it
+// subscribes again after the hub dropped it
+func (d *Dispatcher) follow(stop <-chan struct{}) {
+	for {
+		if err := d.followOnce(stop); err != nil {
+			d.logger.Error("Webhook event subscription closed", "err", err)
+		}
+		select {
+		case <-stop:
+			return
+		case <-time.After(d.pollInterval):
+		}
+	}
+}
+
+func (d *Dispatcher) followOnce(stop <-chan struct{}) error {
+	cursor, err := d.store.Cursor()
+	if err != nil {
+		return err
+	}
+	sub, err := d.hub.Subscribe(stream.Filter{}, cursor)
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+	if cursor.IsZero() {
+		// the first run starts live, later ones resume where it stopped
+		d.store.SetCursor(stream.FromHeight(sub.LiveFrom()))
+	}
+
+	for {
+		select {
+		case ev, ok := <-sub.Events():
+			if !ok {
+				return sub.Err()
+			}
+			if err := d.Enqueue(ev); err != nil {
+				return err
+			}
+		case <-stop:
+			return nil
+		}
+	}
+}
+
+// Enqueue queues the event for the subscriptions it matches
+func (d *Dispatcher) Enqueue(ev stream.Event) error {
+	subs, err := d.store.Subscriptions()
+	if err != nil {
+		return err
+	}
+	var matching []Subscription
+	for _, sub := range subs {
+		if sub.Filter.Matches(ev) {
+			matching = append(matching, sub)
+		}
+	}
+	return d.store.Enqueue(ev, matching, d.now().UnixNano())
+}
+
+// DeliverDue tries the deliveries that are due. The subscriptions are
+// posted to concurrently so a slow endpoint doesn't hold back the others,
+// the deliveries of one subscription in order until an attempt fails, the
+// next ones wait for the next round.
+func (d *Dispatcher) DeliverDue() error {
+	deliveries, err := d.store.Due(d.now().UnixNano(), batchSize)
+	if err != nil {
+		return err
+	}
+	var ids []string
+	queues := map[string][]Delivery{}
+	for _, delivery := range deliveries {
+		id := delivery.SubscriptionID
+		if _, found := queues[id]; !found {
+			ids = append(ids, id)
+		}
+		queues[id] = append(queues[id], delivery)
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mtx      sync.Mutex
+		firstErr error
+	)
+	slots := make(chan struct{}, maxConcurrentDeliveries)
+	for _, id := range ids {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(queue []Delivery) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			for _, delivery := range queue {
+				delivered, err := d.deliver(delivery)
+				if err != nil {
+					mtx.Lock()
+					if firstErr == nil {
+						firstErr = err
+					}
+					mtx.Unlock()
+					return
+				}
+				if !delivered {
+					return
+				}
+			}
+		}(queues[id])
+	}
+	wg.Wait()
+	return firstErr
+}
+
+// deliver makes an attempt of the delivery and records it, it returns
+// whether the endpoint accepted it
+func (d *Dispatcher) deliver(delivery Delivery) (bool, error) {
+	sub, err := d.store.GetSubscription(delivery.SubscriptionID)
+	if err == ErrUnknownSubscription {
+		d.store.Drop(delivery)
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	now := d.now()
+	statusCode, postErr := d.post(sub, delivery, now)
+
+	next := delivery
+	next.Attempts++
+	attempt := Attempt{
+		DeliveryID: delivery.ID,
+		EventID:    delivery.Event.ID,
+		Action:     delivery.Event.Action,
+		Attempt:    next.Attempts,
+		Time:       now.Unix(),
+		StatusCode: statusCode,
+	}
+	switch {
+	case postErr == nil:
+		next.Status = StatusDelivered
+	case next.Attempts >= d.maxAttempts:
+		next.Status = StatusFailed
+		attempt.Error = postErr.Error()
+	default:
+		next.NextAttempt = now.Add(d.backoffAfter(next.Attempts)).UnixNano()
+		attempt.Error = postErr.Error()
+	}
+	attempt.Status = next.Status
+	return postErr == nil, d.store.Record(delivery, next, attempt)
+}
+
+// backoffAfter returns the wait after the nth failed attempt
+func (d *Dispatcher) backoffAfter(attempts int) time.Duration {
+	wait := d.backoff
+	for i := 1; i < attempts && wait < MaxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > MaxBackoff {
+		wait = MaxBackoff
+	}
+	return wait
+}
+
+// post sends the event, any answer but a 2xx is a failure
+func (d *Dispatcher) post(sub Subscription, delivery Delivery, now time.Time) (int, error) {
+	body, err := json.Marshal(delivery.Event)
+	if err != nil {
+		return 0, err
+	}
+	req, err := http.NewRequest("POST", sub.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := now.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, delivery.Event.Action)
+	req.Header.Set(HeaderDelivery, delivery.ID)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(sub.Secret, timestamp, body))
+
+	res, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	io.Copy(ioutil.Discard, io.LimitReader(res.Body, 1<<16))
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, fmt.Errorf("%s answered %d", sub.URL, res.StatusCode)
+	}
+	return res.StatusCode, nil
+}
@@ -0,0 +1,209 @@
+package webhook
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/icheckteam/ichain/client/stream"
+)
+
+const (
+	// defaultLogLimit is how many attempts the delivery log returns by default
+	defaultLogLimit = 100
+	// DefaultMaxSubscriptions is how many subscriptions the LCD keeps
+	DefaultMaxSubscriptions = 1000
+)
+
+// ErrUnauthorized answers the requests on a subscription without its secret
+var ErrUnauthorized = errors.New("the secret of the subscription is required as bearer token")
+
+// ErrOperatorToken answers the subscriptions without the operator token
+var ErrOperatorToken = errors.New("the operator token is required as bearer token to subscribe")
+
+// SubscribeBody ...
+type SubscribeBody struct {
+	URL    string        `json:"url"`
+	Secret string        `json:"secret"`
+	Filter stream.Filter `json:"filter"`
+}
+
+// RegisterRoutes registers the webhook API. Subscribing takes the operator
+// token as bearer token when one is set and is refused past
+// maxSubscriptions, a subscription is then only reached with its secret
+// and the subscriptions are not listed. The URLs can't target private
+// addresses unless allowPrivate.
+func RegisterRoutes(r *mux.Router, store *Store, allowPrivate bool, token string, maxSubscriptions int) {
+	r.HandleFunc("/webhooks", SubscribeHandler(store, allowPrivate, token, maxSubscriptions)).Methods("POST")
+	r.HandleFunc("/webhooks/{id}", SubscriptionHandler(store)).Methods("GET")
+	r.HandleFunc("/webhooks/{id}", UnsubscribeHandler(store)).Methods("DELETE")
+	r.HandleFunc("/webhooks/{id}/deliveries", DeliveriesHandler(store)).Methods("GET")
+}
+
+// SubscribeHandler registers a URL, a secret is generated when none is
+// given and the subscription is returned with it this once
+func SubscribeHandler(store *Store, allowPrivate bool, token string, maxSubscriptions int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if token != "" && !hasBearer(r, token) {
+			writeError(w, http.StatusUnauthorized, ErrOperatorToken)
+			return
+		}
+		var body SubscribeBody
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		if err := CheckURL(body.URL, allowPrivate); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		if body.Secret == "" {
+			var err error
+			if body.Secret, err = randomHex(32); err != nil {
+				writeError(w, http.StatusInternalServerError, err)
+				return
+			}
+		}
+		id, err := randomHex(16)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		sub := Subscription{
+			ID:      id,
+			URL:     body.URL,
+			Secret:  body.Secret,
+			Filter:  body.Filter,
+			Created: time.Now().Unix(),
+		}
+		if err := store.AddSubscription(sub, maxSubscriptions); err != nil {
+			writeStoreError(w, err)
+			return
+		}
+		writeJSON(w, sub)
+	}
+}
+
+// SubscriptionHandler ...
+func SubscriptionHandler(store *Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		sub, err := authorizedSubscription(store, r)
+		if err != nil {
+			writeStoreError(w, err)
+			return
+		}
+		sub.Secret = ""
+		writeJSON(w, sub)
+	}
+}
+
+// UnsubscribeHandler ...
+func UnsubscribeHandler(store *Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		sub, err := authorizedSubscription(store, r)
+		if err != nil {
+			writeStoreError(w, err)
+			return
+		}
+		if err := store.DeleteSubscription(sub.ID); err != nil {
+			writeStoreError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// DeliveriesHandler returns the delivery log of a subscription, the latest
+// attempts first, at most limit of them
+func DeliveriesHandler(store *Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		sub, err := authorizedSubscription(store, r)
+		if err != nil {
+			writeStoreError(w, err)
+			return
+		}
+		limit := defaultLogLimit
+		if s := r.URL.Query().Get("limit"); s != "" {
+			if limit, err = strconv.Atoi(s); err != nil || limit < 1 || limit > maxAttemptLogs {
+				writeError(w, http.StatusBadRequest, fmt.Errorf("invalid limit %s", s))
+				return
+			}
+		}
+		attempts, err := store.Attempts(sub.ID, limit)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		writeJSON(w, attempts)
+	}
+}
+
+// authorizedSubscription returns the subscription of the id param when the
+// request carries its secret as bearer token
+func authorizedSubscription(store *Store, r *http.Request) (Subscription, error) {
+	sub, err := store.GetSubscription(mux.Vars(r)["id"])
+	if err != nil {
+		return Subscription{}, err
+	}
+	if !hasBearer(r, sub.Secret) {
+		return Subscription{}, ErrUnauthorized
+	}
+	return sub, nil
+}
+
+// hasBearer returns whether the request carries the secret as bearer token
+func hasBearer(r *http.Request, secret string) bool {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "Bearer ") {
+		return false
+	}
+	token := strings.TrimPrefix(auth, "Bearer ")
+	return subtle.ConstantTimeCompare([]byte(token), []byte(secret)) == 1
+}
+
+func randomHex(n int) (string, error) {
+	bz := make([]byte, n)
+	if _, err := rand.Read(bz); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bz), nil
+}
+
+func writeStoreError(w http.ResponseWriter, err error) {
+	switch err {
+	case ErrUnknownSubscription:
+		writeError(w, http.StatusNotFound, err)
+		return
+	case ErrUnauthorized:
+		writeError(w, http.StatusUnauthorized, err)
+		return
+	case ErrTooManySubscriptions:
+		writeError(w, http.StatusTooManyRequests, err)
+		return
+	}
+	writeError(w, http.StatusInternalServerError, err)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	w.WriteHeader(status)
+	w.Write([]byte(err.Error()))
+}
+
+func writeJSON(w http.ResponseWriter, o interface{}) {
+	bz, err := json.Marshal(o)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(bz)
+}
@@ -0,0 +1,319 @@
+package webhook
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"sync"
+
+	dbm "github.com/tendermint/tendermint/libs/db"
+
+	"github.com/icheckteam/ichain/client/stream"
+)
+
+// maxAttemptLogs is how many delivery attempts are kept per subscription
+const maxAttemptLogs = 1000
+
+// ErrUnknownSubscription ...
+var ErrUnknownSubscription = errors.New("unknown subscription")
+
+// ErrTooManySubscriptions refuses a subscription over the limit of the LCD
+var ErrTooManySubscriptions = errors.New("too many webhook subscriptions")
+
+var (
+	subscriptionKey = []byte{0x01} // id -> Subscription
+	cursorKey       = []byte{0x02} // the last event queued
+	queueKey        = []byte{0x03} // subscription id, delivery id -> next attempt
+	deliveryKey     = []byte{0x04} // delivery id -> Delivery
+	attemptKey      = []byte{0x05} // subscription id, seq -> Attempt
+	seqKey          = []byte{0x06} // last sequence of the deliveries and attempts
+)
+
+// Store keeps the subscriptions, the queue of pending deliveries and the
+// delivery logs in a tendermint db, goleveldb to survive restarts or memdb
+// for tests. Queuing an event and moving the cursor past it are atomic so
+// no event is lost or queued twice on restart.
+type Store struct {
+	mtx sync.Mutex
+	db  dbm.DB
+}
+
+// NewStore ...
+func NewStore(db dbm.DB) *Store {
+	return &Store{db: db}
+}
+
+// SetSubscription stores a subscription
+func (s *Store) SetSubscription(sub Subscription) error {
+	bz, err := cdc.MarshalBinary(sub)
+	if err != nil {
+		return err
+	}
+	s.db.SetSync(append(subscriptionKey, sub.ID...), bz)
+	return nil
+}
+
+// AddSubscription stores a new subscription unless max are already
+func (s *Store) AddSubscription(sub Subscription, max int) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	count := 0
+	iter := dbm.IteratePrefix(s.db, subscriptionKey)
+	for ; iter.Valid(); iter.Next() {
+		count++
+	}
+	iter.Close()
+	if count >= max {
+		return ErrTooManySubscriptions
+	}
+	return s.SetSubscription(sub)
+}
+
+// GetSubscription ...
+func (s *Store) GetSubscription(id string) (Subscription, error) {
+	bz := s.db.Get(append(subscriptionKey, id...))
+	if bz == nil {
+		return Subscription{}, ErrUnknownSubscription
+	}
+	var sub Subscription
+	err := cdc.UnmarshalBinary(bz, &sub)
+	return sub, err
+}
+
+// DeleteSubscription deletes the subscription, its pending deliveries and
+// its delivery logs
+func (s *Store) DeleteSubscription(id string) error {
+	key := append(subscriptionKey, id...)
+	if !s.db.Has(key) {
+		return ErrUnknownSubscription
+	}
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	batch := s.db.NewBatch()
+	batch.Delete(key)
+	prefix := getSubscriptionQueueKey(id)
+	iter := dbm.IteratePrefix(s.db, prefix)
+	for ; iter.Valid(); iter.Next() {
+		batch.Delete(iter.Key())
+		batch.Delete(append(deliveryKey, iter.Key()[len(prefix):]...))
+	}
+	iter.Close()
+	iter = dbm.IteratePrefix(s.db, append(attemptKey, id...))
+	for ; iter.Valid(); iter.Next() {
+		batch.Delete(iter.Key())
+	}
+	iter.Close()
+	batch.WriteSync()
+	return nil
+}
+
+// Subscriptions ...
+func (s *Store) Subscriptions() ([]Subscription, error) {
+	subs := []Subscription{}
+	iter := dbm.IteratePrefix(s.db, subscriptionKey)
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		var sub Subscription
+		if err := cdc.UnmarshalBinary(iter.Value(), &sub); err != nil {
+			return nil, err
+		}
+		subs = append(subs, sub)
+	}
+	return subs, nil
+}
+
+// Cursor returns the position of the last event queued, zero when none was
+func (s *Store) Cursor() (stream.Cursor, error) {
+	bz := s.db.Get(cursorKey)
+	if bz == nil {
+		return stream.Cursor{}, nil
+	}
+	return stream.ParseCursor(string(bz))
+}
+
+// SetCursor moves the cursor without queuing an event
+func (s *Store) SetCursor(c stream.Cursor) {
+	s.db.SetSync(cursorKey, []byte(c.String()))
+}
+
+// Enqueue queues a delivery of the event to each subscription, due at
+// now, and moves the cursor past the event
+func (s *Store) Enqueue(ev stream.Event, subs []Subscription, now int64) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	batch := s.db.NewBatch()
+	seq := s.seq()
+	for _, sub := range subs {
+		seq++
+		d := Delivery{
+			ID:             seqID(seq),
+			SubscriptionID: sub.ID,
+			Event:          ev,
+			NextAttempt:    now,
+			Status:         StatusPending,
+		}
+		bz, err := cdc.MarshalBinary(d)
+		if err != nil {
+			return err
+		}
+		batch.Set(append(deliveryKey, d.ID...), bz)
+		batch.Set(getQueueKey(sub.ID, d.ID), timeBytes(d.NextAttempt))
+	}
+	batch.Set(seqKey, seqBytes(seq))
+	batch.Set(cursorKey, []byte(ev.Cursor().String()))
+	batch.WriteSync()
+	return nil
+}
+
+// Due returns the deliveries that are due at now, at most limit per
+// subscription. The deliveries of a subscription come in the order they
+// were queued up to the first one that isn't due, so one backing off holds
+// back the later ones of its subscription but not those of the others.
+func (s *Store) Due(now int64, limit int) ([]Delivery, error) {
+	subs, err := s.Subscriptions()
+	if err != nil {
+		return nil, err
+	}
+	var deliveries []Delivery
+	for _, sub := range subs {
+		due, err := s.due(sub.ID, now, limit)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, due...)
+	}
+	return deliveries, nil
+}
+
+func (s *Store) due(subID string, now int64, limit int) ([]Delivery, error) {
+	var deliveries []Delivery
+	prefix := getSubscriptionQueueKey(subID)
+	iter := dbm.IteratePrefix(s.db, prefix)
+	defer iter.Close()
+	for ; iter.Valid() && len(deliveries) < limit; iter.Next() {
+		if int64(binary.BigEndian.Uint64(iter.Value())) > now {
+			break
+		}
+		bz := s.db.Get(append(deliveryKey, iter.Key()[len(prefix):]...))
+		if bz == nil {
+			continue
+		}
+		var d Delivery
+		if err := cdc.UnmarshalBinary(bz, &d); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, nil
+}
+
+// Record logs an attempt of the delivery and stores its new state, it
+// leaves the queue and the store once delivered or failed
+func (s *Store) Record(prev, d Delivery, attempt Attempt) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	batch := s.db.NewBatch()
+	if d.Status == StatusPending {
+		bz, err := cdc.MarshalBinary(d)
+		if err != nil {
+			return err
+		}
+		batch.Set(append(deliveryKey, d.ID...), bz)
+		batch.Set(getQueueKey(d.SubscriptionID, d.ID), timeBytes(d.NextAttempt))
+	} else {
+		batch.Delete(getQueueKey(prev.SubscriptionID, prev.ID))
+		batch.Delete(append(deliveryKey, d.ID...))
+	}
+
+	seq := s.seq() + 1
+	bz, err := cdc.MarshalBinary(attempt)
+	if err != nil {
+		return err
+	}
+	prefix := append(append([]byte{}, attemptKey...), d.SubscriptionID...)
+	batch.Set(append(append([]byte{}, prefix...), seqBytes(seq)...), bz)
+	batch.Set(seqKey, seqBytes(seq))
+	s.pruneAttempts(batch, prefix)
+	batch.WriteSync()
+	return nil
+}
+
+// Drop removes a delivery without logging it, when its subscription was
+// deleted
+func (s *Store) Drop(d Delivery) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	batch := s.db.NewBatch()
+	batch.Delete(getQueueKey(d.SubscriptionID, d.ID))
+	batch.Delete(append(deliveryKey, d.ID...))
+	batch.WriteSync()
+}
+
+// Attempts returns the last delivery attempts of a subscription, the
+// latest first
+func (s *Store) Attempts(subID string, limit int) ([]Attempt, error) {
+	var all []Attempt
+	iter := dbm.IteratePrefix(s.db, append(append([]byte{}, attemptKey...), subID...))
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		var a Attempt
+		if err := cdc.UnmarshalBinary(iter.Value(), &a); err != nil {
+			return nil, err
+		}
+		all = append(all, a)
+	}
+	attempts := []Attempt{}
+	for i := len(all) - 1; i >= 0 && len(attempts) < limit; i-- {
+		attempts = append(attempts, all[i])
+	}
+	return attempts, nil
+}
+
+// pruneAttempts keeps the last maxAttemptLogs attempts of the prefix, the
+// one being written included
+func (s *Store) pruneAttempts(batch dbm.Batch, prefix []byte) {
+	var keys [][]byte
+	iter := dbm.IteratePrefix(s.db, prefix)
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		keys = append(keys, iter.Key())
+	}
+	for i := 0; i < len(keys)+1-maxAttemptLogs; i++ {
+		batch.Delete(keys[i])
+	}
+}
+
+func (s *Store) seq() uint64 {
+	bz := s.db.Get(seqKey)
+	if bz == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint64(bz)
+}
+
+func seqBytes(seq uint64) []byte {
+	bz := make([]byte, 8)
+	binary.BigEndian.PutUint64(bz, seq)
+	return bz
+}
+
+func seqID(seq uint64) string {
+	return hex.EncodeToString(seqBytes(seq))
+}
+
+func timeBytes(t int64) []byte {
+	return seqBytes(uint64(t))
+}
+
+// getSubscriptionQueueKey prefixes the id with its length so no id is the
+// prefix of another
+func getSubscriptionQueueKey(subID string) []byte {
+	key := append(append([]byte{}, queueKey...), byte(len(subID)))
+	return append(key, subID...)
+}
+
+// getQueueKey orders the deliveries of a subscription as they were queued,
+// the ids are increasing sequences
+func getQueueKey(subID, id string) []byte {
+	return append(getSubscriptionQueueKey(subID), id...)
+}
@@ -0,0 +1,134 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// ErrPrivateTarget refuses the URLs reaching the LCD host or its network
+var ErrPrivateTarget = errors.New("webhooks can't target loopback, private or link-local addresses")
+
+// privateNets are the ranges a webhook can't reach unless the operator
+// allows them, IPv4 mapped IPv6 addresses are checked as IPv4
+var privateNets = parseCIDRs(
+	"0.0.0.0/8",
+	"10.0.0.0/8",
+	"100.64.0.0/10",
+	"127.0.0.0/8",
+	"169.254.0.0/16",
+	"172.16.0.0/12",
+	"192.0.0.0/24",
+	"192.168.0.0/16",
+	"198.18.0.0/15",
+	"224.0.0.0/4",
+	"240.0.0.0/4",
+	"::/128",
+	"::1/128",
+	"fc00::/7",
+	"fe80::/10",
+	"ff00::/8",
+)
+
+func parseCIDRs(cidrs ...string) []*net.IPNet {
+	nets := make([]*net.IPNet, len(cidrs))
+	for i, cidr := range cidrs {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		nets[i] = n
+	}
+	return nets
+}
+
+// IsPrivateIP returns whether the address is loopback, private, link-local
+// or otherwise not public
+func IsPrivateIP(ip net.IP) bool {
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+	for _, n := range privateNets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckURL checks the URL of a subscription, its host must resolve to
+// public addresses only unless allowPrivate
+func CheckURL(rawURL string, allowPrivate bool) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return fmt.Errorf("invalid url %s", rawURL)
+	}
+	if allowPrivate {
+		return nil
+	}
+	_, err = publicIPs(context.Background(), u.Hostname())
+	return err
+}
+
+// publicIPs resolves the host, it fails when one of its addresses isn't
+// public so a name can't mix a private address among public ones
+func publicIPs(ctx context.Context, host string) ([]net.IP, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		if IsPrivateIP(ip) {
+			return nil, ErrPrivateTarget
+		}
+		return []net.IP{ip}, nil
+	}
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("no address for %s", host)
+	}
+	ips := make([]net.IP, len(addrs))
+	for i, addr := range addrs {
+		if IsPrivateIP(addr.IP) {
+			return nil, ErrPrivateTarget
+		}
+		ips[i] = addr.IP
+	}
+	return ips, nil
+}
+
+// newClient returns the client posting the deliveries. Unless allowPrivate
+// it dials the addresses it checked itself, so neither a DNS answer
+// changing after the subscription nor a redirect reaches a private one.
+func newClient(allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+	transport := &http.Transport{
+		DialContext:         dialer.DialContext,
+		TLSHandshakeTimeout: 5 * time.Second,
+		MaxIdleConns:        100,
+		IdleConnTimeout:     90 * time.Second,
+	}
+	if !allowPrivate {
+		transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			host, port, err := net.SplitHostPort(addr)
+			if err != nil {
+				return nil, err
+			}
+			ips, err := publicIPs(ctx, host)
+			if err != nil {
+				return nil, err
+			}
+			var conn net.Conn
+			for _, ip := range ips {
+				if conn, err = dialer.DialContext(ctx, network, net.JoinHostPort(ip.String(), port)); err == nil {
+					return conn, nil
+				}
+			}
+			return nil, err
+		}
+	}
+	return &http.Client{Transport: transport, Timeout: 10 * time.Second}
+}
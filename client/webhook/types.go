@@ -0,0 +1,77 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	amino "github.com/tendermint/go-amino"
+
+	"github.com/icheckteam/ichain/client/stream"
+)
+
+var cdc = amino.NewCodec()
+
+// Headers of a delivery
+const (
+	HeaderEvent     = "X-Ichain-Event"
+	HeaderDelivery  = "X-Ichain-Delivery"
+	HeaderTimestamp = "X-Ichain-Timestamp"
+	HeaderSignature = "X-Ichain-Signature"
+)
+
+// Statuses of a delivery
+const (
+	StatusPending   = "pending"
+	StatusDelivered = "delivered"
+	StatusFailed    = "failed"
+)
+
+// Subscription is a URL the events matching the filter are posted to. The
+// secret signs the deliveries, it is only returned when registering.
+type Subscription struct {
+	ID      string        `json:"id"`
+	URL     string        `json:"url"`
+	Secret  string        `json:"secret,omitempty"`
+	Filter  stream.Filter `json:"filter"`
+	Created int64         `json:"created"`
+}
+
+// Delivery is an event queued for a subscription until it is accepted or
+// runs out of attempts
+type Delivery struct {
+	ID             string       `json:"id"`
+	SubscriptionID string       `json:"subscription_id"`
+	Event          stream.Event `json:"event"`
+	Attempts       int          `json:"attempts"`
+	NextAttempt    int64        `json:"next_attempt"`
+	Status         string       `json:"status"`
+}
+
+// Attempt is a line of the delivery log of a subscription
+type Attempt struct {
+	DeliveryID string `json:"delivery_id"`
+	EventID    string `json:"event_id"`
+	Action     string `json:"action"`
+	Attempt    int    `json:"attempt"`
+	Time       int64  `json:"time"`
+	StatusCode int    `json:"status_code"`
+	Error      string `json:"error,omitempty"`
+	Status     string `json:"status"`
+}
+
+// Sign returns the signature header of a delivery, the hex HMAC-SHA256
+// with the secret of the timestamp, a dot and the body
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature header of a delivery, receivers should also
+// refuse old timestamps so a delivery can't be replayed
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
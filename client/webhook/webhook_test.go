@@ -0,0 +1,334 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/icheckteam/ichain/client/stream"
+)
+
+var owner = sdk.AccAddress([]byte("owner"))
+
+// receiver is a local endpoint answering with the next status, 200 once
+// none is left
+type receiver struct {
+	mtx      sync.Mutex
+	statuses []int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	rc.mtx.Lock()
+	defer rc.mtx.Unlock()
+	rc.requests = append(rc.requests, r)
+	rc.bodies = append(rc.bodies, body)
+	status := http.StatusOK
+	if len(rc.statuses) > 0 {
+		status, rc.statuses = rc.statuses[0], rc.statuses[1:]
+	}
+	w.WriteHeader(status)
+}
+
+func (rc *receiver) count() int {
+	rc.mtx.Lock()
+	defer rc.mtx.Unlock()
+	return len(rc.requests)
+}
+
+type clock struct{ t time.Time }
+
+func (c *clock) now() time.Time          { return c.t }
+func (c *clock) advance(d time.Duration) { c.t = c.t.Add(d) }
+func newClock() *clock                   { return &clock{time.Unix(1500000000, 0)} }
+
+// transfer is the event of an accepted owner proposal
+func transfer(height int64, id string) stream.Event {
+	return stream.Event{
+		ID:       stream.Cursor{Height: height}.String(),
+		Action:   stream.ActionAnswerProposal,
+		Height:   height,
+		AssetIDs: []string{id},
+		Owner:    owner,
+	}
+}
+
+func setup(t *testing.T, filter stream.Filter, rc *receiver) (*Dispatcher, *Store, Subscription, *clock, func()) {
+	server := httptest.NewServer(rc)
+
+	store := NewStore(dbm.NewMemDB())
+	sub := Subscription{ID: "sub1", URL: server.URL, Secret: "secret", Filter: filter}
+	require.Nil(t, store.SetSubscription(sub))
+
+	c := newClock()
+	d := NewDispatcher(nil, store, log.NewNopLogger()).WithRetries(3, time.Second).WithPrivateTargets(true)
+	d.now = c.now
+	return d, store, sub, c, server.Close
+}
+
+func TestDeliverSigned(t *testing.T) {
+	rc := &receiver{}
+	d, store, sub, _, cleanup := setup(t, stream.Filter{Owner: owner, Action: stream.ActionAnswerProposal}, rc)
+	defer cleanup()
+
+	ev := transfer(5, "shipment1")
+	require.Nil(t, d.Enqueue(ev))
+	require.Nil(t, d.Enqueue(stream.Event{ID: "6:0:0", Height: 6, Action: stream.ActionAddQuantity, AssetIDs: []string{"shipment1"}}))
+	require.Nil(t, d.DeliverDue())
+	require.Equal(t, 1, rc.count())
+
+	req, body := rc.requests[0], rc.bodies[0]
+	assert.Equal(t, stream.ActionAnswerProposal, req.Header.Get(HeaderEvent))
+	timestamp, err := strconv.ParseInt(req.Header.Get(HeaderTimestamp), 10, 64)
+	require.Nil(t, err)
+	assert.True(t, Verify(sub.Secret, timestamp, body, req.Header.Get(HeaderSignature)))
+	assert.False(t, Verify("other", timestamp, body, req.Header.Get(HeaderSignature)))
+
+	var got stream.Event
+	require.Nil(t, json.Unmarshal(body, &got))
+	assert.Equal(t, ev.ID, got.ID)
+	assert.Equal(t, []string{"shipment1"}, got.AssetIDs)
+
+	// the cursor moved past the events, even the one no one subscribed to
+	cursor, err := store.Cursor()
+	require.Nil(t, err)
+	assert.Equal(t, "6:0:0", cursor.String())
+
+	attempts, err := store.Attempts(sub.ID, 10)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(attempts))
+	assert.Equal(t, StatusDelivered, attempts[0].Status)
+	assert.Equal(t, http.StatusOK, attempts[0].StatusCode)
+	assert.Equal(t, ev.ID, attempts[0].EventID)
+
+	// delivered ones leave the queue
+	require.Nil(t, d.DeliverDue())
+	assert.Equal(t, 1, rc.count())
+}
+
+func TestDeliverRetries(t *testing.T) {
+	rc := &receiver{statuses: []int{http.StatusInternalServerError, http.StatusBadGateway}}
+	d, store, sub, c, cleanup := setup(t, stream.Filter{}, rc)
+	defer cleanup()
+
+	require.Nil(t, d.Enqueue(transfer(5, "shipment1")))
+	require.Nil(t, d.DeliverDue())
+	require.Equal(t, 1, rc.count())
+
+	// not due before the backoff
+	c.advance(999 * time.Millisecond)
+	require.Nil(t, d.DeliverDue())
+	require.Equal(t, 1, rc.count())
+	c.advance(time.Millisecond)
+	require.Nil(t, d.DeliverDue())
+	require.Equal(t, 2, rc.count())
+
+	// the backoff doubles
+	c.advance(time.Second)
+	require.Nil(t, d.DeliverDue())
+	require.Equal(t, 2, rc.count())
+	c.advance(time.Second)
+	require.Nil(t, d.DeliverDue())
+	require.Equal(t, 3, rc.count())
+
+	attempts, err := store.Attempts(sub.ID, 10)
+	require.Nil(t, err)
+	require.Equal(t, 3, len(attempts))
+	assert.Equal(t, StatusDelivered, attempts[0].Status)
+	assert.Equal(t, 3, attempts[0].Attempt)
+	assert.Equal(t, StatusPending, attempts[1].Status)
+	assert.Equal(t, http.StatusBadGateway, attempts[1].StatusCode)
+	assert.NotEmpty(t, attempts[1].Error)
+	assert.Equal(t, http.StatusInternalServerError, attempts[2].StatusCode)
+
+	// all the attempts were of the same delivery
+	assert.Equal(t, attempts[0].DeliveryID, attempts[2].DeliveryID)
+	assert.Equal(t, rc.requests[0].Header.Get(HeaderDelivery), rc.requests[2].Header.Get(HeaderDelivery))
+}
+
+func TestDeliverFails(t *testing.T) {
+	rc := &receiver{statuses: []int{500, 500, 500, 500}}
+	d, store, sub, c, cleanup := setup(t, stream.Filter{}, rc)
+	defer cleanup()
+
+	require.Nil(t, d.Enqueue(transfer(5, "shipment1")))
+	for i := 0; i < 5; i++ {
+		require.Nil(t, d.DeliverDue())
+		c.advance(time.Hour)
+	}
+	assert.Equal(t, 3, rc.count())
+
+	attempts, err := store.Attempts(sub.ID, 1)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(attempts))
+	assert.Equal(t, StatusFailed, attempts[0].Status)
+
+	due, err := store.Due(c.now().UnixNano(), 10)
+	require.Nil(t, err)
+	assert.Empty(t, due)
+}
+
+func TestDeleteSubscription(t *testing.T) {
+	rc := &receiver{statuses: []int{500}}
+	d, store, sub, c, cleanup := setup(t, stream.Filter{}, rc)
+	defer cleanup()
+
+	require.Nil(t, d.Enqueue(transfer(5, "shipment1")))
+	require.Nil(t, d.DeliverDue())
+	require.Nil(t, store.DeleteSubscription(sub.ID))
+	assert.Equal(t, ErrUnknownSubscription, store.DeleteSubscription(sub.ID))
+
+	// the pending delivery is dropped with its subscription
+	c.advance(time.Hour)
+	require.Nil(t, d.DeliverDue())
+	assert.Equal(t, 1, rc.count())
+	due, err := store.Due(c.now().UnixNano(), 10)
+	require.Nil(t, err)
+	assert.Empty(t, due)
+
+	attempts, err := store.Attempts(sub.ID, 10)
+	require.Nil(t, err)
+	assert.Empty(t, attempts)
+}
+
+func TestDeliverConcurrently(t *testing.T) {
+	rc := &receiver{}
+	d, store, _, _, cleanup := setup(t, stream.Filter{}, rc)
+	defer cleanup()
+
+	// an endpoint hanging until released
+	release := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer slow.Close()
+	require.Nil(t, store.SetSubscription(Subscription{ID: "sub0", URL: slow.URL, Secret: "secret"}))
+
+	require.Nil(t, d.Enqueue(transfer(5, "shipment1")))
+	done := make(chan error)
+	go func() { done <- d.DeliverDue() }()
+
+	// the other subscription doesn't wait for the slow one
+	for i := 0; i < 50 && rc.count() == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, 1, rc.count())
+	close(release)
+	require.Nil(t, <-done)
+}
+
+func TestDeliverBacklog(t *testing.T) {
+	rc := &receiver{}
+	d, store, _, c, cleanup := setup(t, stream.Filter{}, rc)
+	defer cleanup()
+
+	// a dead endpoint with more deliveries queued than a batch
+	dead := &receiver{}
+	for i := 0; i < 10; i++ {
+		dead.statuses = append(dead.statuses, http.StatusInternalServerError)
+	}
+	server := httptest.NewServer(dead)
+	defer server.Close()
+	require.Nil(t, store.SetSubscription(Subscription{ID: "sub0", URL: server.URL, Secret: "secret"}))
+
+	for i := 1; i <= batchSize+50; i++ {
+		require.Nil(t, d.Enqueue(transfer(int64(i), "shipment1")))
+	}
+
+	// the dead subscription doesn't take the batch of the other one, and
+	// its later deliveries wait for the first one backing off
+	require.Nil(t, d.DeliverDue())
+	assert.Equal(t, 1, dead.count())
+	assert.Equal(t, batchSize, rc.count())
+	require.Nil(t, d.DeliverDue())
+	assert.Equal(t, 1, dead.count())
+	assert.Equal(t, batchSize+50, rc.count())
+
+	// in the order they were queued
+	for i, body := range rc.bodies {
+		var ev stream.Event
+		require.Nil(t, json.Unmarshal(body, &ev))
+		require.Equal(t, int64(i+1), ev.Height)
+	}
+
+	c.advance(time.Second)
+	require.Nil(t, d.DeliverDue())
+	assert.Equal(t, 2, dead.count())
+	assert.Equal(t, dead.requests[0].Header.Get(HeaderDelivery), dead.requests[1].Header.Get(HeaderDelivery))
+}
+
+func TestSubscribeLimits(t *testing.T) {
+	store := NewStore(dbm.NewMemDB())
+	handler := SubscribeHandler(store, true, "operator", 2)
+	subscribe := func(token string) int {
+		req := httptest.NewRequest("POST", "/webhooks", strings.NewReader(`{"url":"http://localhost:8080/hook"}`))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		handler(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, subscribe(""))
+	assert.Equal(t, http.StatusUnauthorized, subscribe("other"))
+	assert.Equal(t, http.StatusOK, subscribe("operator"))
+	assert.Equal(t, http.StatusOK, subscribe("operator"))
+	assert.Equal(t, http.StatusTooManyRequests, subscribe("operator"))
+
+	subs, err := store.Subscriptions()
+	require.Nil(t, err)
+	assert.Equal(t, 2, len(subs))
+}
+
+func TestBackoff(t *testing.T) {
+	d := NewDispatcher(nil, nil, log.NewNopLogger())
+	assert.Equal(t, DefaultBackoff, d.backoffAfter(1))
+	assert.Equal(t, 4*DefaultBackoff, d.backoffAfter(3))
+	assert.Equal(t, MaxBackoff, d.backoffAfter(20))
+}
+
+func TestPrivateTargets(t *testing.T) {
+	for _, ip := range []string{"127.0.0.1", "10.1.2.3", "172.16.0.1", "192.168.1.1", "169.254.169.254", "0.0.0.0", "::1", "fe80::1", "fd00::1", "::ffff:127.0.0.1"} {
+		assert.True(t, IsPrivateIP(net.ParseIP(ip)), ip)
+	}
+	for _, ip := range []string{"8.8.8.8", "172.32.0.1", "2001:4860:4860::8888"} {
+		assert.False(t, IsPrivateIP(net.ParseIP(ip)), ip)
+	}
+
+	assert.Equal(t, ErrPrivateTarget, CheckURL("http://127.0.0.1:8080/hook", false))
+	assert.Equal(t, ErrPrivateTarget, CheckURL("http://[::1]/hook", false))
+	assert.Equal(t, ErrPrivateTarget, CheckURL("http://localhost/hook", false))
+	assert.Nil(t, CheckURL("http://127.0.0.1:8080/hook", true))
+	assert.NotNil(t, CheckURL("ftp://example.com/hook", true))
+
+	// the dispatcher checks the address it dials too
+	rc := &receiver{}
+	d, store, sub, _, cleanup := setup(t, stream.Filter{}, rc)
+	defer cleanup()
+	d.WithPrivateTargets(false)
+	require.Nil(t, d.Enqueue(transfer(5, "shipment1")))
+	require.Nil(t, d.DeliverDue())
+	assert.Equal(t, 0, rc.count())
+	attempts, err := store.Attempts(sub.ID, 1)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(attempts))
+	assert.Contains(t, attempts[0].Error, ErrPrivateTarget.Error())
+}
@@ -15,10 +15,13 @@ import (
 	stakecmd "github.com/cosmos/cosmos-sdk/x/stake/client/cli"
 
 	"github.com/icheckteam/ichain/app"
+	"github.com/icheckteam/ichain/client/epcis"
 	"github.com/icheckteam/ichain/client/lcd"
 	"github.com/icheckteam/ichain/client/rpc"
 	"github.com/icheckteam/ichain/client/tx"
 	"github.com/icheckteam/ichain/version"
+	assetcli "github.com/icheckteam/ichain/x/asset/client/cli"
+	identitycli "github.com/icheckteam/ichain/x/identity/client/cli"
 )
 
 // rootCmd is the entry point for this binary
@@ -140,6 +143,76 @@ func main() {
 		govCmd,
 	)
 
+	//Add asset commands
+	assetCmd := &cobra.Command{
+		Use:   "asset",
+		Short: "Asset tracking subcommands",
+	}
+	assetCmd.AddCommand(
+		client.GetCommands(
+			assetcli.GetCmdQueryAsset(cdc),
+			assetcli.GetCmdQueryChildren(cdc),
+			assetcli.GetCmdQueryAccountAssets(cdc),
+			assetcli.GetCmdQueryProposals(cdc),
+			assetcli.GetCmdQueryHistory(cdc),
+		)...)
+	assetCmd.AddCommand(
+		client.PostCommands(
+			assetcli.GetCmdCreateAsset(cdc),
+			assetcli.GetCmdAddQuantity(cdc),
+			assetcli.GetCmdSubtractQuantity(cdc),
+			assetcli.GetCmdUpdateProperties(cdc),
+			assetcli.GetCmdAddMaterials(cdc),
+			assetcli.GetCmdFinalize(cdc),
+			assetcli.GetCmdCreateProposal(cdc),
+			assetcli.GetCmdAnswerProposal(cdc),
+			assetcli.GetCmdRevokeReporter(cdc),
+		)...)
+	rootCmd.AddCommand(
+		assetCmd,
+	)
+
+	//Add identity commands
+	identityCmd := &cobra.Command{
+		Use:   "identity",
+		Short: "Identity and certificate subcommands",
+	}
+	identityCmd.AddCommand(
+		client.GetCommands(
+			identitycli.GetCmdQueryOwners(cdc),
+			identitycli.GetCmdQueryCerts(cdc),
+			identitycli.GetCmdQueryTrusts(cdc),
+			identitycli.GetCmdCredentials(cdc),
+		)...)
+	identityCmd.AddCommand(
+		client.PostCommands(
+			identitycli.GetCmdRegister(cdc),
+			identitycli.GetCmdAddOwner(cdc),
+			identitycli.GetCmdDelOwner(cdc),
+			identitycli.GetCmdSetTrust(cdc),
+			identitycli.GetCmdSetCerts(cdc),
+		)...)
+	rootCmd.AddCommand(
+		identityCmd,
+	)
+
+	//Add epcis commands
+	epcisCmd := &cobra.Command{
+		Use:   "epcis",
+		Short: "EPCIS document subcommands",
+	}
+	epcisCmd.AddCommand(
+		client.GetCommands(
+			epcis.ExportCmd(cdc),
+		)...)
+	epcisCmd.AddCommand(
+		client.PostCommands(
+			epcis.ImportCmd(cdc),
+		)...)
+	rootCmd.AddCommand(
+		epcisCmd,
+	)
+
 	// prepare and add flags
 	executor := cli.PrepareMainCmd(rootCmd, "IC", app.DefaultCLIHome)
 	executor.Execute()
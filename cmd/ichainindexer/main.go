@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/tendermint/tendermint/libs/cli"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/wire"
+
+	"github.com/icheckteam/ichain/app"
+	"github.com/icheckteam/ichain/indexer"
+	"github.com/icheckteam/ichain/x/mongo"
+)
+
+const (
+	flagStore     = "store"
+	flagMongoURL  = "mongo-url"
+	flagMongoDB   = "mongo-db"
+	flagPollEvery = "poll-interval"
+
+	storeLevelDB = "goleveldb"
+	storeMongo   = "mongo"
+)
+
+// DefaultHome is where the goleveldb store is kept
+var DefaultHome = os.ExpandEnv("$HOME/.ichainindexer")
+
+func main() {
+	cdc := app.MakeCodec()
+
+	rootCmd := &cobra.Command{
+		Use:   "ichainindexer",
+		Short: "Ichain indexer, follows committed blocks and keeps read models off chain",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return run(cdc)
+		},
+	}
+	rootCmd.Flags().String(client.FlagNode, "tcp://localhost:26657", "Address of the node to follow")
+	rootCmd.Flags().String(flagStore, storeLevelDB, "Storage backend of the read models (goleveldb|mongo)")
+	rootCmd.Flags().String(flagMongoURL, "mongodb://localhost:27017", "MongoDB connection url")
+	rootCmd.Flags().String(flagMongoDB, "ichain", "MongoDB database name")
+	rootCmd.Flags().Duration(flagPollEvery, 0, "How long to wait for new blocks, defaults to 1s")
+
+	executor := cli.PrepareBaseCmd(rootCmd, "ICI", DefaultHome)
+	executor.Execute()
+}
+
+func run(cdc *wire.Codec) error {
+	logger := log.NewTMLogger(log.NewSyncWriter(os.Stdout)).With("module", "indexer")
+
+	store, err := openStore(cdc)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	node := rpcclient.NewHTTP(viper.GetString(client.FlagNode), "/websocket")
+	ix := indexer.NewIndexer(cdc, node, store, logger)
+	if interval := viper.GetDuration(flagPollEvery); interval > 0 {
+		ix = ix.WithPollInterval(interval)
+	}
+
+	stop := make(chan struct{})
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigs
+		close(stop)
+	}()
+
+	logger.Info("Indexer started", "node", viper.GetString(client.FlagNode), "store", viper.GetString(flagStore))
+	return ix.Run(stop)
+}
+
+func openStore(cdc *wire.Codec) (indexer.Store, error) {
+	switch viper.GetString(flagStore) {
+	case storeMongo:
+		store, err := mongo.NewStore(viper.GetString(flagMongoURL), viper.GetString(flagMongoDB), cdc)
+		if err != nil {
+			return nil, err
+		}
+		return store, nil
+	case storeLevelDB:
+		db, err := dbm.NewGoLevelDB("indexer", filepath.Join(viper.GetString(cli.HomeFlag), "data"))
+		if err != nil {
+			return nil, err
+		}
+		return indexer.NewKVStore(db, cdc), nil
+	default:
+		return nil, fmt.Errorf("unknown store %s", viper.GetString(flagStore))
+	}
+}
@@ -0,0 +1,675 @@
+package indexer
+
+import (
+	"bytes"
+	"encoding/hex"
+	"reflect"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+
+	"github.com/icheckteam/ichain/x/asset"
+	"github.com/icheckteam/ichain/x/identity"
+)
+
+// batch replays the messages of a block on top of the stored read models,
+// only messages of successful transactions are applied so their checks
+// already passed on chain.
+type batch struct {
+	cdc    *wire.Codec
+	store  Store
+	height int64
+	time   int64
+
+	assets     map[string]*Asset
+	identities map[string]*Identity
+	order      []string // asset ids in the order they were first touched
+	idOrder    []string
+	histories  []History
+}
+
+func newBatch(cdc *wire.Codec, store Store, height int64, time int64) *batch {
+	return &batch{
+		cdc:        cdc,
+		store:      store,
+		height:     height,
+		time:       time,
+		assets:     map[string]*Asset{},
+		identities: map[string]*Identity{},
+	}
+}
+
+// changes returns the read models touched by the batch
+func (b *batch) changes() Changes {
+	changes := Changes{Histories: b.histories}
+	for _, id := range b.order {
+		a := b.assets[id]
+		a.Updated = b.height
+		changes.Assets = append(changes.Assets, *a)
+	}
+	for _, addr := range b.idOrder {
+		ident := b.identities[addr]
+		ident.Updated = b.height
+		changes.Identities = append(changes.Identities, *ident)
+	}
+	return changes
+}
+
+func (b *batch) getAsset(id string) (*Asset, error) {
+	if a, ok := b.assets[id]; ok {
+		return a, nil
+	}
+	a, found, err := b.store.GetAsset(id)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		a = Asset{ID: id, Quantity: sdk.ZeroInt()}
+	}
+	b.assets[id] = &a
+	b.order = append(b.order, id)
+	return &a, nil
+}
+
+func (b *batch) getIdentity(addr sdk.AccAddress) (*Identity, error) {
+	key := addr.String()
+	if ident, ok := b.identities[key]; ok {
+		return ident, nil
+	}
+	ident, found, err := b.store.GetIdentity(addr)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		ident = Identity{Address: addr}
+	}
+	b.identities[key] = &ident
+	b.idOrder = append(b.idOrder, key)
+	return &ident, nil
+}
+
+// addHistory records the message in the history of every asset it touched
+func (b *batch) addHistory(msg sdk.Msg, txIndex, msgIndex int, txHash, memo string, assetIDs ...string) error {
+	bz, err := b.cdc.MarshalJSON(msg)
+	if err != nil {
+		return err
+	}
+	var sender sdk.AccAddress
+	if signers := msg.GetSigners(); len(signers) > 0 {
+		sender = signers[0]
+	}
+	for _, id := range assetIDs {
+		b.histories = append(b.histories, History{
+			AssetID:  id,
+			Height:   b.height,
+			TxIndex:  txIndex,
+			MsgIndex: msgIndex,
+			TxHash:   txHash,
+			Time:     b.time,
+			Type:     reflect.TypeOf(msg).Name(),
+			Sender:   sender,
+			Memo:     memo,
+			Msg:      bz,
+		})
+	}
+	return nil
+}
+
+// applyTx applies the messages of a successful transaction
+func (b *batch) applyTx(txIndex int, txHash string, tx sdk.Tx, memo string) error {
+	for i, msg := range tx.GetMsgs() {
+		assetIDs, err := b.apply(msg)
+		if err != nil {
+			return err
+		}
+		if len(assetIDs) > 0 {
+			if err := b.addHistory(msg, txIndex, i, txHash, memo, assetIDs...); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// apply applies a message and returns the ids of the assets it touched
+func (b *batch) apply(msg sdk.Msg) ([]string, error) {
+	switch msg := msg.(type) {
+	case asset.MsgCreateAsset:
+		return b.applyCreateAsset(msg)
+	case asset.MsgAddQuantity:
+		return b.updateAsset(msg.AssetID, func(a *Asset) {
+			a.Quantity = a.Quantity.Add(msg.Quantity)
+		})
+	case asset.MsgSubtractQuantity:
+		return b.updateAsset(msg.AssetID, func(a *Asset) {
+			a.Quantity = a.Quantity.Sub(msg.Quantity)
+		})
+	case asset.MsgUpdateProperties:
+		return b.updateAsset(msg.AssetID, func(a *Asset) {
+			a.Properties = setProperties(a.Properties, msg.Properties)
+		})
+	case asset.MsgAddMaterials:
+		return b.applyAddMaterials(msg)
+	case asset.MsgFinalize:
+		return b.updateAsset(msg.AssetID, func(a *Asset) {
+			a.Final = true
+		})
+	case asset.MsgRevokeReporter:
+		return b.updateAsset(msg.AssetID, func(a *Asset) {
+			a.Reporters = removeReporter(a.Reporters, msg.Reporter)
+		})
+	case asset.MsgAnchorDocument:
+		digest, err := asset.ParseContentHash(msg.Hash)
+		if err != nil {
+			return nil, err
+		}
+		return b.updateAsset(msg.AssetID, func(a *Asset) {
+			a.Documents = append(a.Documents, asset.Document{
+				AssetID:   msg.AssetID,
+				Hash:      msg.Hash,
+				SHA256:    hex.EncodeToString(digest),
+				MediaType: msg.MediaType,
+				Size:      msg.Size,
+				Title:     msg.Title,
+				Reporter:  msg.Sender,
+				Created:   b.time,
+				Height:    b.height,
+			})
+		})
+	case asset.MsgCreateProposal:
+		return b.updateAsset(msg.AssetID, func(a *Asset) {
+			a.Proposals = append(removeProposal(a.Proposals, msg.Recipient), asset.Proposal{
+				Role:       msg.Role,
+				Status:     asset.StatusPending,
+				Properties: msg.Properties,
+				Issuer:     msg.Sender,
+				Recipient:  msg.Recipient,
+			})
+		})
+	case asset.MsgAnswerProposal:
+		return b.applyAnswerProposal(msg)
+	case identity.MsgReg:
+		return nil, b.updateIdentity(msg.Sender, func(ident *Identity) {
+			ident.Owners = []sdk.AccAddress{msg.Sender}
+		})
+	case identity.MsgAddOwner:
+		return nil, b.authorize(msg.Ident, msg)
+	case identity.MsgDelOwner:
+		return nil, b.authorize(msg.Ident, msg)
+	case identity.MsgSetThreshold:
+		return nil, b.authorize(msg.Ident, msg)
+	case identity.MsgSetGuardians:
+		return nil, b.authorize(msg.Ident, msg)
+	case identity.MsgRecover:
+		return nil, b.applyRecover(msg)
+	case identity.MsgVetoRecovery:
+		return nil, b.updateIdentity(msg.Ident, func(ident *Identity) {
+			ident.Recovery = nil
+		})
+	case identity.MsgFinalizeRecovery:
+		return nil, b.applyFinalizeRecovery(msg)
+	case identity.MsgSetProfile:
+		return nil, b.authorize(msg.Ident, msg)
+	case identity.MsgApproveAction:
+		return nil, b.applyApproveAction(msg)
+	case identity.MsgCancelAction:
+		return nil, b.updateIdentity(msg.Ident, func(ident *Identity) {
+			ident.Pending = removeAction(ident.Pending, msg.ActionID)
+		})
+	case identity.MsgAddService:
+		return nil, b.authorize(msg.Ident, msg)
+	case identity.MsgDelService:
+		return nil, b.authorize(msg.Ident, msg)
+	case identity.MsgSetTrust:
+		return nil, b.updateIdentity(msg.Trustor, func(ident *Identity) {
+			if msg.Trust {
+				ident.Trusting = appendAddress(ident.Trusting, msg.Trusting)
+			} else {
+				ident.Trusting = removeAddress(ident.Trusting, msg.Trusting)
+			}
+		})
+	case identity.MsgSetCerts:
+		return nil, b.authorize(msg.Issuer, msg)
+	case identity.MsgRevokeCerts:
+		return nil, b.authorize(msg.Issuer, msg)
+	case identity.MsgSetCertSchema:
+		return nil, b.authorize(msg.Issuer, msg)
+	default:
+		// messages of other modules are not indexed
+		return nil, nil
+	}
+}
+
+func (b *batch) updateAsset(id string, update func(a *Asset)) ([]string, error) {
+	a, err := b.getAsset(id)
+	if err != nil {
+		return nil, err
+	}
+	update(a)
+	return []string{id}, nil
+}
+
+// isAssetOwner mirrors the asset keeper, the owners of an identity owning
+// the asset own it too
+func (b *batch) isAssetOwner(a *Asset, addr sdk.AccAddress) (bool, error) {
+	if bytes.Equal(a.Owner, addr) {
+		return true, nil
+	}
+	ident, ok := b.identities[a.Owner.String()]
+	if !ok {
+		// read only, an unknown owner must not create an identity
+		stored, found, err := b.store.GetIdentity(a.Owner)
+		if err != nil || !found {
+			return false, err
+		}
+		ident = &stored
+	}
+	return containsAddress(ident.Owners, addr), nil
+}
+
+func (b *batch) updateIdentity(addr sdk.AccAddress, update func(ident *Identity)) error {
+	ident, err := b.getIdentity(addr)
+	if err != nil {
+		return err
+	}
+	update(ident)
+	return nil
+}
+
+// authorize mirrors the threshold of the identity keeper, the message
+// becomes a pending action when more than one approval is required
+func (b *batch) authorize(addr sdk.AccAddress, msg sdk.Msg) error {
+	ident, err := b.getIdentity(addr)
+	if err != nil {
+		return err
+	}
+	if ident.Threshold <= 1 {
+		return b.execute(msg)
+	}
+	ident.LastActionID++
+	ident.Pending = append(ident.Pending, identity.PendingAction{
+		ID:        ident.LastActionID,
+		Ident:     addr,
+		Msg:       msg,
+		Approvals: msg.GetSigners(),
+		CreatedAt: b.time,
+	})
+	return nil
+}
+
+// execute applies an authorized action of the owners
+func (b *batch) execute(msg sdk.Msg) error {
+	switch msg := msg.(type) {
+	case identity.MsgAddOwner:
+		return b.updateIdentity(msg.Ident, func(ident *Identity) {
+			ident.Owners = appendAddress(ident.Owners, msg.Owner)
+		})
+	case identity.MsgDelOwner:
+		return b.updateIdentity(msg.Ident, func(ident *Identity) {
+			ident.Owners = removeAddress(ident.Owners, msg.Owner)
+		})
+	case identity.MsgSetThreshold:
+		return b.updateIdentity(msg.Ident, func(ident *Identity) {
+			ident.Threshold = msg.Threshold
+		})
+	case identity.MsgSetGuardians:
+		return b.updateIdentity(msg.Ident, func(ident *Identity) {
+			ident.RecoveryConfig = msg.Config
+			ident.Recovery = nil
+		})
+	case identity.MsgSetCerts:
+		for _, value := range msg.Values {
+			value := value
+			err := b.updateIdentity(value.Owner, func(ident *Identity) {
+				ident.Certs = setCert(ident.Certs, msg.Issuer, value, b.time)
+			})
+			if err != nil {
+				return err
+			}
+		}
+	case identity.MsgRevokeCerts:
+		for _, revocation := range msg.Revocations {
+			revocation := revocation
+			err := b.updateIdentity(revocation.Owner, func(ident *Identity) {
+				ident.Certs = revokeCert(ident.Certs, msg.Issuer, revocation.Property, revocation.Reason, b.time)
+			})
+			if err != nil {
+				return err
+			}
+		}
+	case identity.MsgAddService:
+		return b.updateIdentity(msg.Ident, func(ident *Identity) {
+			ident.Services = append(removeService(ident.Services, msg.Service.ID), msg.Service)
+		})
+	case identity.MsgDelService:
+		return b.updateIdentity(msg.Ident, func(ident *Identity) {
+			ident.Services = removeService(ident.Services, msg.ServiceID)
+		})
+	case identity.MsgSetProfile:
+		return b.updateIdentity(msg.Ident, func(ident *Identity) {
+			if msg.Profile.IsEmpty() {
+				ident.Profile = nil
+				return
+			}
+			profile := msg.Profile
+			profile.Address = msg.Ident
+			profile.UpdatedAt = b.time
+			ident.Profile = &profile
+		})
+	case identity.MsgSetCertSchema:
+		return b.updateIdentity(msg.Issuer, func(ident *Identity) {
+			ident.Schemas = removeSchema(ident.Schemas, msg.Property)
+			if len(msg.Schema) > 0 {
+				ident.Schemas = append(ident.Schemas, identity.CertSchema{
+					Certifier: msg.Issuer,
+					Property:  msg.Property,
+					Schema:    msg.Schema,
+					UpdatedAt: b.time,
+				})
+			}
+		})
+	}
+	return nil
+}
+
+func (b *batch) applyApproveAction(msg identity.MsgApproveAction) error {
+	ident, err := b.getIdentity(msg.Ident)
+	if err != nil {
+		return err
+	}
+	for i, action := range ident.Pending {
+		if action.ID != msg.ActionID {
+			continue
+		}
+		action.Approvals = append(action.Approvals, msg.Sender)
+		ident.Pending[i] = action
+		var approvals int64
+		for _, approval := range action.Approvals {
+			if containsAddress(ident.Owners, approval) {
+				approvals++
+			}
+		}
+		if approvals < ident.Threshold {
+			return nil
+		}
+		ident.Pending = removeAction(ident.Pending, action.ID)
+		return b.execute(action.Msg)
+	}
+	return nil
+}
+
+// applyRecover mirrors the recovery of the identity keeper
+func (b *batch) applyRecover(msg identity.MsgRecover) error {
+	return b.updateIdentity(msg.Ident, func(ident *Identity) {
+		if ident.Recovery == nil {
+			ident.Recovery = &identity.Recovery{
+				Ident:       msg.Ident,
+				NewOwner:    msg.NewOwner,
+				Approvals:   []sdk.AccAddress{},
+				InitiatedAt: b.time,
+			}
+		}
+		r := ident.Recovery
+		r.Approvals = append(r.Approvals, msg.Sender)
+		if r.QuorumAt == 0 && int64(len(r.Approvals)) >= ident.RecoveryConfig.Quorum {
+			r.QuorumAt = b.time
+			r.ExecutableAt = b.time + ident.RecoveryConfig.Delay
+		}
+	})
+}
+
+// applyFinalizeRecovery mirrors the identity keeper, the lost owners are
+// removed when the config says so and the threshold is reset
+func (b *batch) applyFinalizeRecovery(msg identity.MsgFinalizeRecovery) error {
+	return b.updateIdentity(msg.Ident, func(ident *Identity) {
+		if ident.Recovery == nil {
+			return
+		}
+		newOwner := ident.Recovery.NewOwner
+		ident.Recovery = nil
+		if ident.RecoveryConfig.RemoveOwners {
+			ident.Owners = []sdk.AccAddress{newOwner}
+		} else {
+			ident.Owners = appendAddress(ident.Owners, newOwner)
+		}
+		threshold := ident.RecoveryConfig.Threshold
+		if threshold < 1 {
+			threshold = 1
+		}
+		if owners := int64(len(ident.Owners)); threshold > owners {
+			threshold = owners
+		}
+		ident.Threshold = threshold
+	})
+}
+
+func (b *batch) applyCreateAsset(msg asset.MsgCreateAsset) ([]string, error) {
+	a, err := b.getAsset(msg.AssetID)
+	if err != nil {
+		return nil, err
+	}
+	owner := msg.Sender
+	if len(msg.Owner) > 0 {
+		owner = msg.Owner
+	}
+	*a = Asset{
+		ID:         msg.AssetID,
+		Name:       msg.Name,
+		Owner:      owner,
+		Quantity:   msg.Quantity,
+		Parent:     msg.Parent,
+		Height:     b.height,
+		Created:    b.time,
+		Properties: setProperties(nil, msg.Properties),
+	}
+	if msg.Parent == "" {
+		return []string{a.ID}, nil
+	}
+
+	parent, err := b.getAsset(msg.Parent)
+	if err != nil {
+		return nil, err
+	}
+	parent.Quantity = parent.Quantity.Sub(msg.Quantity)
+	if parent.Root != "" && parent.Quantity.IsZero() {
+		parent.Final = true
+	}
+	if parent.Root != "" {
+		a.Root = parent.Root
+	} else {
+		a.Root = parent.ID
+	}
+	return []string{a.ID, parent.ID}, nil
+}
+
+func (b *batch) applyAddMaterials(msg asset.MsgAddMaterials) ([]string, error) {
+	a, err := b.getAsset(msg.AssetID)
+	if err != nil {
+		return nil, err
+	}
+	ids := []string{a.ID}
+	for _, amount := range msg.Amount {
+		m, err := b.getAsset(amount.RecordID)
+		if err != nil {
+			return nil, err
+		}
+		m.Quantity = m.Quantity.Sub(amount.Amount)
+		a.Materials = addMaterial(a.Materials, amount)
+		ids = append(ids, m.ID)
+	}
+	return ids, nil
+}
+
+func (b *batch) applyAnswerProposal(msg asset.MsgAnswerProposal) ([]string, error) {
+	a, err := b.getAsset(msg.AssetID)
+	if err != nil {
+		return nil, err
+	}
+	var proposal asset.Proposal
+	for _, p := range a.Proposals {
+		if bytes.Equal(p.Recipient, msg.Recipient) {
+			proposal = p
+		}
+	}
+	a.Proposals = removeProposal(a.Proposals, msg.Recipient)
+	isOwner, err := b.isAssetOwner(a, proposal.Issuer)
+	if err != nil {
+		return nil, err
+	}
+	if !isOwner || msg.Response != asset.StatusAccepted {
+		return []string{a.ID}, nil
+	}
+
+	switch proposal.Role {
+	case asset.RoleOwner:
+		a.Owner = proposal.Recipient
+		a.Reporters = nil
+	case asset.RoleReporter:
+		a.Reporters = append(removeReporter(a.Reporters, proposal.Recipient), asset.Reporter{
+			Addr:       proposal.Recipient,
+			Properties: proposal.Properties,
+			Created:    b.time,
+		})
+	}
+	return []string{a.ID}, nil
+}
+
+func setProperties(props asset.Properties, updates asset.Properties) asset.Properties {
+	for _, update := range updates {
+		replaced := false
+		for i, p := range props {
+			if p.Name == update.Name {
+				props[i] = update
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			props = append(props, update)
+		}
+	}
+	return props
+}
+
+func addMaterial(materials []asset.Material, input asset.Material) []asset.Material {
+	for i, m := range materials {
+		if m.RecordID == input.RecordID {
+			materials[i].Amount = m.Amount.Add(input.Amount)
+			return materials
+		}
+	}
+	return append(materials, input)
+}
+
+func removeReporter(reporters []asset.Reporter, addr sdk.AccAddress) []asset.Reporter {
+	out := []asset.Reporter{}
+	for _, r := range reporters {
+		if !bytes.Equal(r.Addr, addr) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+func removeProposal(proposals []asset.Proposal, recipient sdk.AccAddress) []asset.Proposal {
+	out := []asset.Proposal{}
+	for _, p := range proposals {
+		if !bytes.Equal(p.Recipient, recipient) {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// setCert mirrors the keeper: a cert is revoked instead of deleted, the
+// keeper refuses to issue a revoked cert again
+func setCert(certs identity.Certs, issuer sdk.AccAddress, value identity.CertValue, time int64) identity.Certs {
+	if !value.Confidence {
+		return revokeCert(certs, issuer, value.Property, "", time)
+	}
+	cert := identity.Cert{
+		Property:  value.Property,
+		Owner:     value.Owner,
+		Certifier: issuer,
+		Data:      value.Data,
+		CreatedAt: time,
+		ExpiresAt: value.ExpiresAt,
+	}
+	for i, c := range certs {
+		if c.Property == value.Property && bytes.Equal(c.Certifier, issuer) {
+			if !c.Revoked {
+				cert.CreatedAt = c.CreatedAt
+			}
+			certs[i] = cert
+			return certs
+		}
+	}
+	return append(certs, cert)
+}
+
+func revokeCert(certs identity.Certs, issuer sdk.AccAddress, property, reason string, time int64) identity.Certs {
+	for i, c := range certs {
+		if c.Property == property && bytes.Equal(c.Certifier, issuer) && !c.Revoked {
+			certs[i].Revoked = true
+			certs[i].RevokedAt = time
+			certs[i].RevokeReason = reason
+		}
+	}
+	return certs
+}
+
+func appendAddress(addrs []sdk.AccAddress, addr sdk.AccAddress) []sdk.AccAddress {
+	if containsAddress(addrs, addr) {
+		return addrs
+	}
+	return append(addrs, addr)
+}
+
+func containsAddress(addrs []sdk.AccAddress, addr sdk.AccAddress) bool {
+	for _, a := range addrs {
+		if bytes.Equal(a, addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func removeAddress(addrs []sdk.AccAddress, addr sdk.AccAddress) []sdk.AccAddress {
+	out := []sdk.AccAddress{}
+	for _, a := range addrs {
+		if !bytes.Equal(a, addr) {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+func removeService(services []identity.Service, id string) []identity.Service {
+	out := []identity.Service{}
+	for _, s := range services {
+		if s.ID != id {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func removeSchema(schemas []identity.CertSchema, property string) []identity.CertSchema {
+	out := []identity.CertSchema{}
+	for _, s := range schemas {
+		if s.Property != property {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func removeAction(actions []identity.PendingAction, id int64) []identity.PendingAction {
+	out := []identity.PendingAction{}
+	for _, a := range actions {
+		if a.ID != id {
+			out = append(out, a)
+		}
+	}
+	return out
+}
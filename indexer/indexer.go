@@ -0,0 +1,117 @@
+package indexer
+
+import (
+	"fmt"
+	"time"
+
+	cmn "github.com/tendermint/tendermint/libs/common"
+	"github.com/tendermint/tendermint/libs/log"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+
+	"github.com/cosmos/cosmos-sdk/wire"
+
+	"github.com/icheckteam/ichain/client/tx"
+)
+
+// Indexer follows the committed blocks of a node and keeps the read models
+// of the store up to date
+type Indexer struct {
+	cdc          *wire.Codec
+	node         rpcclient.Client
+	store        Store
+	logger       log.Logger
+	pollInterval time.Duration
+}
+
+// NewIndexer ...
+func NewIndexer(cdc *wire.Codec, node rpcclient.Client, store Store, logger log.Logger) *Indexer {
+	return &Indexer{
+		cdc:          cdc,
+		node:         node,
+		store:        store,
+		logger:       logger,
+		pollInterval: time.Second,
+	}
+}
+
+// WithPollInterval sets how long the indexer waits for new blocks
+func (ix *Indexer) WithPollInterval(interval time.Duration) *Indexer {
+	ix.pollInterval = interval
+	return ix
+}
+
+// Run indexes blocks until stop is closed
+func (ix *Indexer) Run(stop <-chan struct{}) error {
+	for {
+		if err := ix.Sync(stop); err != nil {
+			return err
+		}
+		select {
+		case <-stop:
+			return nil
+		case <-time.After(ix.pollInterval):
+		}
+	}
+}
+
+// Sync indexes all the blocks committed since the last indexed height
+func (ix *Indexer) Sync(stop <-chan struct{}) error {
+	status, err := ix.node.Status()
+	if err != nil {
+		return err
+	}
+	latest := status.SyncInfo.LatestBlockHeight
+	last, err := ix.store.Height()
+	if err != nil {
+		return err
+	}
+	for height := last + 1; height <= latest; height++ {
+		select {
+		case <-stop:
+			return nil
+		default:
+		}
+		if err := ix.IndexBlock(height); err != nil {
+			return fmt.Errorf("index block %d: %s", height, err.Error())
+		}
+	}
+	return nil
+}
+
+// IndexBlock applies the successful transactions of the block
+func (ix *Indexer) IndexBlock(height int64) error {
+	block, err := ix.node.Block(&height)
+	if err != nil {
+		return err
+	}
+	results, err := ix.node.BlockResults(&height)
+	if err != nil {
+		return err
+	}
+
+	txs := block.Block.Data.Txs
+	if len(results.Results.DeliverTx) != len(txs) {
+		return fmt.Errorf("%d results for %d txs", len(results.Results.DeliverTx), len(txs))
+	}
+
+	b := newBatch(ix.cdc, ix.store, height, block.Block.Header.Time.Unix())
+	for i, txBytes := range txs {
+		if !results.Results.DeliverTx[i].IsOK() {
+			continue
+		}
+		stdTx, err := tx.ParseTx(ix.cdc, txBytes)
+		if err != nil {
+			return err
+		}
+		hash := cmn.HexBytes(txBytes.Hash()).String()
+		if err := b.applyTx(i, hash, stdTx, stdTx.Memo); err != nil {
+			return err
+		}
+	}
+
+	if err := ix.store.Commit(height, b.changes()); err != nil {
+		return err
+	}
+	ix.logger.Debug("Indexed block", "height", height, "txs", len(txs))
+	return nil
+}
@@ -0,0 +1,255 @@
+package indexer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	dbm "github.com/tendermint/tendermint/libs/db"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+
+	"github.com/icheckteam/ichain/app"
+	"github.com/icheckteam/ichain/x/asset"
+	"github.com/icheckteam/ichain/x/identity"
+)
+
+var (
+	addr1 = sdk.AccAddress([]byte("addr1"))
+	addr2 = sdk.AccAddress([]byte("addr2"))
+)
+
+func commitBlock(t *testing.T, store Store, height int64, msgs ...sdk.Msg) {
+	b := newBatch(app.MakeCodec(), store, height, height*10)
+	for i, msg := range msgs {
+		tx := auth.NewStdTx([]sdk.Msg{msg}, auth.StdFee{}, nil, "")
+		require.Nil(t, b.applyTx(i, "hash", tx, ""))
+	}
+	require.Nil(t, store.Commit(height, b.changes()))
+}
+
+func TestIndexAssets(t *testing.T) {
+	store := NewKVStore(dbm.NewMemDB(), app.MakeCodec())
+
+	commitBlock(t, store, 1,
+		asset.NewMsgCreateAsset(addr1, "asset1", "asset1", sdk.NewInt(100), ""),
+		asset.NewMsgCreateAsset(addr1, "asset2", "asset2", sdk.NewInt(10), ""),
+	)
+	commitBlock(t, store, 2,
+		asset.NewMsgCreateAsset(addr1, "asset3", "asset3", sdk.NewInt(40), "asset1"),
+		asset.MsgAddMaterials{AssetID: "asset2", Sender: addr1, Amount: asset.Materials{{RecordID: "asset1", Amount: sdk.NewInt(5)}}},
+		asset.MsgCreateProposal{AssetID: "asset2", Sender: addr1, Recipient: addr2, Role: asset.RoleOwner},
+		asset.MsgAnswerProposal{AssetID: "asset2", Sender: addr2, Recipient: addr2, Role: asset.RoleOwner, Response: asset.StatusAccepted},
+	)
+
+	height, err := store.Height()
+	require.Nil(t, err)
+	assert.Equal(t, int64(2), height)
+
+	a, found, err := store.GetAsset("asset1")
+	require.Nil(t, err)
+	require.True(t, found)
+	assert.Equal(t, sdk.NewInt(55), a.Quantity)
+
+	a, _, _ = store.GetAsset("asset3")
+	assert.Equal(t, "asset1", a.Root)
+	assert.Equal(t, int64(20), a.Created)
+
+	children, err := store.Children("asset1")
+	require.Nil(t, err)
+	require.Equal(t, 1, len(children))
+	assert.Equal(t, "asset3", children[0].ID)
+
+	assets, err := store.AssetsByMaterial("asset1")
+	require.Nil(t, err)
+	require.Equal(t, 1, len(assets))
+	assert.Equal(t, "asset2", assets[0].ID)
+
+	// the owner index follows the transfer
+	assets, _ = store.AssetsByOwner(addr1)
+	assert.Equal(t, 2, len(assets))
+	assets, _ = store.AssetsByOwner(addr2)
+	require.Equal(t, 1, len(assets))
+	assert.Equal(t, "asset2", assets[0].ID)
+	assert.Equal(t, 0, len(assets[0].Proposals))
+
+	histories, err := store.History("asset1")
+	require.Nil(t, err)
+	require.Equal(t, 3, len(histories))
+	assert.Equal(t, "MsgCreateAsset", histories[0].Type)
+	assert.Equal(t, int64(2), histories[1].Height)
+	assert.Equal(t, "MsgAddMaterials", histories[2].Type)
+}
+
+func TestIndexReporters(t *testing.T) {
+	store := NewKVStore(dbm.NewMemDB(), app.MakeCodec())
+	commitBlock(t, store, 1,
+		asset.NewMsgCreateAsset(addr1, "asset1", "asset1", sdk.NewInt(100), ""),
+		asset.MsgCreateProposal{AssetID: "asset1", Sender: addr1, Recipient: addr2, Role: asset.RoleReporter, Properties: []string{"size"}},
+		asset.MsgAnswerProposal{AssetID: "asset1", Sender: addr2, Recipient: addr2, Role: asset.RoleReporter, Response: asset.StatusAccepted},
+	)
+	assets, err := store.AssetsByReporter(addr2)
+	require.Nil(t, err)
+	assert.Equal(t, 1, len(assets))
+
+	commitBlock(t, store, 2, asset.MsgRevokeReporter{AssetID: "asset1", Sender: addr1, Reporter: addr2})
+	assets, _ = store.AssetsByReporter(addr2)
+	assert.Equal(t, 0, len(assets))
+}
+
+func TestIndexDocuments(t *testing.T) {
+	store := NewKVStore(dbm.NewMemDB(), app.MakeCodec())
+	commitBlock(t, store, 1,
+		asset.NewMsgCreateAsset(addr1, "asset1", "asset1", sdk.NewInt(100), ""),
+		asset.MsgAnchorDocument{AssetID: "asset1", Sender: addr1, Hash: "QmRN6wdp1S2A5EtjW9A3M1vKSBuQQGcgvuhoMUoEz4iiT5", Title: "invoice"},
+	)
+	a, _, err := store.GetAsset("asset1")
+	require.Nil(t, err)
+	require.Equal(t, 1, len(a.Documents))
+	assert.Equal(t, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", a.Documents[0].SHA256)
+	assert.Equal(t, int64(10), a.Documents[0].Created)
+}
+
+func TestIndexIdentities(t *testing.T) {
+	store := NewKVStore(dbm.NewMemDB(), app.MakeCodec())
+	commitBlock(t, store, 1,
+		identity.MsgReg{Sender: addr1},
+		identity.MsgAddOwner{Sender: addr1, Ident: addr1, Owner: addr2},
+		identity.NewMsgSetCerts(addr1, addr1, []identity.CertValue{{Owner: addr2, Property: "owner", Confidence: true}}),
+	)
+	ident, found, err := store.GetIdentity(addr1)
+	require.Nil(t, err)
+	require.True(t, found)
+	assert.Equal(t, 2, len(ident.Owners))
+
+	ident, _, _ = store.GetIdentity(addr2)
+	require.Equal(t, 1, len(ident.Certs))
+	assert.Equal(t, int64(10), ident.Certs[0].CreatedAt)
+
+	commitBlock(t, store, 2,
+		identity.NewMsgSetCerts(addr1, addr1, []identity.CertValue{{Owner: addr2, Property: "owner", Confidence: false}}),
+	)
+	ident, _, _ = store.GetIdentity(addr2)
+	require.Equal(t, 1, len(ident.Certs))
+	assert.True(t, ident.Certs[0].Revoked)
+	assert.Equal(t, int64(20), ident.Certs[0].RevokedAt)
+}
+
+func TestIndexPendingActions(t *testing.T) {
+	store := NewKVStore(dbm.NewMemDB(), app.MakeCodec())
+	commitBlock(t, store, 1,
+		identity.MsgReg{Sender: addr1},
+		identity.MsgAddOwner{Sender: addr1, Ident: addr1, Owner: addr2},
+		identity.NewMsgSetThreshold(addr1, addr1, 2),
+		identity.NewMsgSetCerts(addr1, addr1, []identity.CertValue{{Owner: addr2, Property: "organic", Confidence: true}}),
+	)
+	ident, _, _ := store.GetIdentity(addr1)
+	require.Equal(t, 1, len(ident.Pending))
+	assert.Equal(t, int64(1), ident.Pending[0].ID)
+	ident, _, _ = store.GetIdentity(addr2)
+	assert.Equal(t, 0, len(ident.Certs))
+
+	commitBlock(t, store, 2, identity.NewMsgApproveAction(addr2, addr1, 1))
+	ident, _, _ = store.GetIdentity(addr1)
+	assert.Equal(t, 0, len(ident.Pending))
+	ident, _, _ = store.GetIdentity(addr2)
+	assert.Equal(t, 1, len(ident.Certs))
+}
+
+func TestIndexThresholdActions(t *testing.T) {
+	addr3 := sdk.AccAddress([]byte("addr3"))
+	store := NewKVStore(dbm.NewMemDB(), app.MakeCodec())
+	commitBlock(t, store, 1,
+		identity.MsgReg{Sender: addr1},
+		identity.MsgAddOwner{Sender: addr1, Ident: addr1, Owner: addr2},
+		identity.NewMsgSetThreshold(addr1, addr1, 2),
+	)
+
+	// every sensitive action waits for a second owner
+	schema := []byte(`{"type": "object"}`)
+	commitBlock(t, store, 2,
+		identity.NewMsgSetCerts(addr1, addr1, []identity.CertValue{{Owner: addr2, Property: "organic", Confidence: true}}),
+		identity.NewMsgAddService(addr1, addr1, identity.Service{ID: "hub", Type: "IdentityHub", Endpoint: "https://hub.example.com"}),
+		identity.NewMsgSetProfile(addr1, addr1, identity.Profile{Name: "Acme"}),
+		identity.NewMsgSetCertSchema(addr1, addr1, "organic", schema),
+		identity.NewMsgSetGuardians(addr1, addr1, identity.RecoveryConfig{Guardians: []sdk.AccAddress{addr3}, Quorum: 1}),
+		identity.MsgAddOwner{Sender: addr1, Ident: addr1, Owner: addr3},
+	)
+	ident, _, _ := store.GetIdentity(addr1)
+	require.Equal(t, 6, len(ident.Pending))
+	assert.Equal(t, int64(6), ident.LastActionID)
+	assert.Empty(t, ident.Services)
+	assert.Nil(t, ident.Profile)
+	assert.Empty(t, ident.Schemas)
+	assert.Empty(t, ident.RecoveryConfig.Guardians)
+	assert.Equal(t, 2, len(ident.Owners))
+	ident, _, _ = store.GetIdentity(addr2)
+	assert.Empty(t, ident.Certs)
+
+	commitBlock(t, store, 3,
+		identity.NewMsgApproveAction(addr2, addr1, 1),
+		identity.NewMsgApproveAction(addr2, addr1, 2),
+		identity.NewMsgApproveAction(addr2, addr1, 3),
+		identity.NewMsgApproveAction(addr2, addr1, 4),
+		identity.NewMsgApproveAction(addr2, addr1, 5),
+		identity.NewMsgApproveAction(addr2, addr1, 6),
+	)
+	ident, _, _ = store.GetIdentity(addr1)
+	assert.Empty(t, ident.Pending)
+	require.Equal(t, 1, len(ident.Services))
+	require.NotNil(t, ident.Profile)
+	assert.Equal(t, "Acme", ident.Profile.Name)
+	require.Equal(t, 1, len(ident.Schemas))
+	assert.Equal(t, int64(30), ident.Schemas[0].UpdatedAt)
+	assert.Equal(t, []sdk.AccAddress{addr3}, ident.RecoveryConfig.Guardians)
+	assert.Equal(t, 3, len(ident.Owners))
+	ident, _, _ = store.GetIdentity(addr2)
+	require.Equal(t, 1, len(ident.Certs))
+
+	commitBlock(t, store, 4,
+		identity.NewMsgRevokeCerts(addr1, addr1, []identity.CertRevocation{{Owner: addr2, Property: "organic"}}),
+		identity.NewMsgDelService(addr1, addr1, "hub"),
+		identity.MsgDelOwner{Sender: addr1, Ident: addr1, Owner: addr3},
+		identity.NewMsgSetThreshold(addr1, addr1, 1),
+	)
+	ident, _, _ = store.GetIdentity(addr1)
+	require.Equal(t, 4, len(ident.Pending))
+	assert.Equal(t, int64(7), ident.Pending[0].ID)
+	ident, _, _ = store.GetIdentity(addr2)
+	assert.False(t, ident.Certs[0].Revoked)
+
+	commitBlock(t, store, 5,
+		identity.NewMsgApproveAction(addr2, addr1, 7),
+		identity.NewMsgApproveAction(addr2, addr1, 8),
+		identity.NewMsgApproveAction(addr2, addr1, 9),
+		identity.NewMsgApproveAction(addr2, addr1, 10),
+	)
+	ident, _, _ = store.GetIdentity(addr1)
+	assert.Empty(t, ident.Pending)
+	assert.Empty(t, ident.Services)
+	assert.Equal(t, 2, len(ident.Owners))
+	assert.Equal(t, int64(1), ident.Threshold)
+	ident, _, _ = store.GetIdentity(addr2)
+	assert.True(t, ident.Certs[0].Revoked)
+}
+
+func TestIndexRecovery(t *testing.T) {
+	addr3 := sdk.AccAddress([]byte("addr3"))
+	addr4 := sdk.AccAddress([]byte("addr4"))
+	store := NewKVStore(dbm.NewMemDB(), app.MakeCodec())
+	commitBlock(t, store, 1,
+		identity.MsgReg{Sender: addr1},
+		identity.MsgAddOwner{Sender: addr1, Ident: addr1, Owner: addr2},
+		identity.NewMsgSetGuardians(addr1, addr1, identity.RecoveryConfig{Guardians: []sdk.AccAddress{addr3}, Quorum: 1, RemoveOwners: true}),
+		identity.NewMsgSetThreshold(addr1, addr1, 2),
+	)
+	commitBlock(t, store, 2,
+		identity.NewMsgRecover(addr3, addr1, addr4),
+		identity.NewMsgFinalizeRecovery(addr4, addr1),
+	)
+	ident, _, _ := store.GetIdentity(addr1)
+	assert.Nil(t, ident.Recovery)
+	assert.Equal(t, []sdk.AccAddress{addr4}, ident.Owners)
+	assert.Equal(t, int64(1), ident.Threshold)
+}
@@ -0,0 +1,224 @@
+package indexer
+
+import (
+	"encoding/binary"
+
+	dbm "github.com/tendermint/tendermint/libs/db"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+)
+
+var (
+	heightKey        = []byte{0x00}
+	assetKey         = []byte{0x01}
+	identityKey      = []byte{0x02}
+	historyKey       = []byte{0x03}
+	ownerIndexKey    = []byte{0x04}
+	reporterIndexKey = []byte{0x05}
+	materialIndexKey = []byte{0x06}
+	childIndexKey    = []byte{0x07}
+)
+
+// KVStore keeps the read models in an embedded key value database,
+// goleveldb for a standalone indexer or memdb for tests
+type KVStore struct {
+	db  dbm.DB
+	cdc *wire.Codec
+}
+
+var _ Store = KVStore{}
+
+// NewKVStore ...
+func NewKVStore(db dbm.DB, cdc *wire.Codec) KVStore {
+	return KVStore{db: db, cdc: cdc}
+}
+
+// Height implements Store
+func (s KVStore) Height() (int64, error) {
+	bz := s.db.Get(heightKey)
+	if bz == nil {
+		return 0, nil
+	}
+	return int64(binary.BigEndian.Uint64(bz)), nil
+}
+
+// Commit implements Store
+func (s KVStore) Commit(height int64, changes Changes) error {
+	batch := s.db.NewBatch()
+	for _, a := range changes.Assets {
+		prev, found, err := s.GetAsset(a.ID)
+		if err != nil {
+			return err
+		}
+		if found {
+			s.deleteIndexes(batch, prev)
+		}
+		bz, err := s.cdc.MarshalBinary(a)
+		if err != nil {
+			return err
+		}
+		batch.Set(append(assetKey, []byte(a.ID)...), bz)
+		s.setIndexes(batch, a)
+	}
+	for _, ident := range changes.Identities {
+		bz, err := s.cdc.MarshalBinary(ident)
+		if err != nil {
+			return err
+		}
+		batch.Set(append(identityKey, ident.Address.Bytes()...), bz)
+	}
+	for _, h := range changes.Histories {
+		bz, err := s.cdc.MarshalBinary(h)
+		if err != nil {
+			return err
+		}
+		batch.Set(getHistoryKey(h), bz)
+	}
+	batch.Set(heightKey, uint64Bytes(uint64(height)))
+	batch.Write()
+	return nil
+}
+
+func (s KVStore) setIndexes(batch dbm.Batch, a Asset) {
+	for _, key := range indexKeys(a) {
+		batch.Set(key, []byte{})
+	}
+}
+
+func (s KVStore) deleteIndexes(batch dbm.Batch, a Asset) {
+	for _, key := range indexKeys(a) {
+		batch.Delete(key)
+	}
+}
+
+// GetAsset implements Store
+func (s KVStore) GetAsset(id string) (a Asset, found bool, err error) {
+	bz := s.db.Get(append(assetKey, []byte(id)...))
+	if bz == nil {
+		return
+	}
+	err = s.cdc.UnmarshalBinary(bz, &a)
+	return a, err == nil, err
+}
+
+// GetIdentity implements Store
+func (s KVStore) GetIdentity(addr sdk.AccAddress) (ident Identity, found bool, err error) {
+	bz := s.db.Get(append(identityKey, addr.Bytes()...))
+	if bz == nil {
+		return
+	}
+	err = s.cdc.UnmarshalBinary(bz, &ident)
+	return ident, err == nil, err
+}
+
+// History implements Store
+func (s KVStore) History(assetID string) ([]History, error) {
+	iter := dbm.IteratePrefix(s.db, append(historyKey, lengthPrefixed(assetID)...))
+	defer iter.Close()
+	histories := []History{}
+	for ; iter.Valid(); iter.Next() {
+		var h History
+		if err := s.cdc.UnmarshalBinary(iter.Value(), &h); err != nil {
+			return nil, err
+		}
+		histories = append(histories, h)
+	}
+	return histories, nil
+}
+
+// AssetsByOwner implements Store
+func (s KVStore) AssetsByOwner(owner sdk.AccAddress) ([]Asset, error) {
+	return s.indexedAssets(append(ownerIndexKey, owner.Bytes()...))
+}
+
+// AssetsByReporter implements Store
+func (s KVStore) AssetsByReporter(reporter sdk.AccAddress) ([]Asset, error) {
+	return s.indexedAssets(append(reporterIndexKey, reporter.Bytes()...))
+}
+
+// AssetsByMaterial implements Store
+func (s KVStore) AssetsByMaterial(materialID string) ([]Asset, error) {
+	return s.indexedAssets(append(materialIndexKey, lengthPrefixed(materialID)...))
+}
+
+// Children implements Store
+func (s KVStore) Children(parentID string) ([]Asset, error) {
+	return s.indexedAssets(append(childIndexKey, lengthPrefixed(parentID)...))
+}
+
+// indexedAssets loads the assets whose id follows the prefix in the index keys
+func (s KVStore) indexedAssets(prefix []byte) ([]Asset, error) {
+	iter := dbm.IteratePrefix(s.db, prefix)
+	ids := []string{}
+	for ; iter.Valid(); iter.Next() {
+		ids = append(ids, string(iter.Key()[len(prefix):]))
+	}
+	iter.Close()
+
+	assets := make([]Asset, 0, len(ids))
+	for _, id := range ids {
+		a, found, err := s.GetAsset(id)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			assets = append(assets, a)
+		}
+	}
+	return assets, nil
+}
+
+// Close implements Store
+func (s KVStore) Close() error {
+	s.db.Close()
+	return nil
+}
+
+func indexKeys(a Asset) [][]byte {
+	id := []byte(a.ID)
+	keys := [][]byte{}
+	if len(a.Owner) > 0 {
+		keys = append(keys, concat(ownerIndexKey, a.Owner.Bytes(), id))
+	}
+	for _, r := range a.Reporters {
+		keys = append(keys, concat(reporterIndexKey, r.Addr.Bytes(), id))
+	}
+	for _, m := range a.Materials {
+		keys = append(keys, concat(materialIndexKey, lengthPrefixed(m.RecordID), id))
+	}
+	if a.Parent != "" {
+		keys = append(keys, concat(childIndexKey, lengthPrefixed(a.Parent), id))
+	}
+	return keys
+}
+
+// getHistoryKey orders the history of an asset by height, tx and msg index
+func getHistoryKey(h History) []byte {
+	return concat(
+		historyKey,
+		lengthPrefixed(h.AssetID),
+		uint64Bytes(uint64(h.Height)),
+		uint64Bytes(uint64(h.TxIndex)),
+		uint64Bytes(uint64(h.MsgIndex)),
+	)
+}
+
+// lengthPrefixed keeps an id followed by other key parts unambiguous
+func lengthPrefixed(id string) []byte {
+	return append(uint64Bytes(uint64(len(id))), []byte(id)...)
+}
+
+func uint64Bytes(n uint64) []byte {
+	bz := make([]byte, 8)
+	binary.BigEndian.PutUint64(bz, n)
+	return bz
+}
+
+func concat(parts ...[]byte) []byte {
+	key := []byte{}
+	for _, p := range parts {
+		key = append(key, p...)
+	}
+	return key
+}
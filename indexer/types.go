@@ -0,0 +1,92 @@
+package indexer
+
+import (
+	"encoding/json"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/icheckteam/ichain/x/asset"
+	"github.com/icheckteam/ichain/x/identity"
+)
+
+// Asset is the denormalised read model of an asset
+type Asset struct {
+	ID         string           `json:"id"`
+	Name       string           `json:"name"`
+	Owner      sdk.AccAddress   `json:"owner"`
+	Parent     string           `json:"parent"`
+	Root       string           `json:"root"`
+	Final      bool             `json:"final"`
+	Quantity   sdk.Int          `json:"quantity"`
+	Created    int64            `json:"created"`
+	Height     int64            `json:"height"`  // the height the asset was created at
+	Updated    int64            `json:"updated"` // the height of the last change
+	Properties asset.Properties `json:"properties"`
+	Materials  []asset.Material `json:"materials"`
+	Reporters  []asset.Reporter `json:"reporters"`
+	Proposals  []asset.Proposal `json:"proposals"`
+	Documents  []asset.Document `json:"documents"`
+}
+
+// Identity is the read model of an identity
+type Identity struct {
+	Address  sdk.AccAddress        `json:"address"`
+	Owners   []sdk.AccAddress      `json:"owners"`
+	Certs    identity.Certs        `json:"certs"`    // the certs issued to the identity
+	Trusting []sdk.AccAddress      `json:"trusting"` // the accounts trusted by the identity
+	Services []identity.Service    `json:"services"`
+	Profile  *identity.Profile     `json:"profile"`
+	Schemas  []identity.CertSchema `json:"schemas"` // the schemas of the certs issued by the identity
+
+	// Threshold is the number of owner approvals of the sensitive
+	// actions, LastActionID the id of the last pending action
+	Threshold    int64                    `json:"threshold"`
+	LastActionID int64                    `json:"last_action_id"`
+	Pending      []identity.PendingAction `json:"pending"`
+
+	RecoveryConfig identity.RecoveryConfig `json:"recovery_config"`
+	Recovery       *identity.Recovery      `json:"recovery"` // the recovery in progress
+	Updated        int64                   `json:"updated"`
+}
+
+// History is a message that touched an asset
+type History struct {
+	AssetID  string          `json:"asset_id"`
+	Height   int64           `json:"height"`
+	TxIndex  int             `json:"tx_index"`
+	MsgIndex int             `json:"msg_index"`
+	TxHash   string          `json:"tx_hash"`
+	Time     int64           `json:"time"`
+	Type     string          `json:"type"` // the name of the message, eg. MsgCreateAsset
+	Sender   sdk.AccAddress  `json:"sender"`
+	Memo     string          `json:"memo"`
+	Msg      json.RawMessage `json:"msg"`
+}
+
+// Changes are the read models written by a block
+type Changes struct {
+	Assets     []Asset
+	Identities []Identity
+	Histories  []History
+}
+
+// Store persists the read models
+type Store interface {
+	// Height returns the last indexed height
+	Height() (int64, error)
+	// Commit writes the changes of the block at height atomically
+	Commit(height int64, changes Changes) error
+
+	GetAsset(id string) (Asset, bool, error)
+	GetIdentity(addr sdk.AccAddress) (Identity, bool, error)
+
+	// History returns the messages that touched the asset, oldest first
+	History(assetID string) ([]History, error)
+	AssetsByOwner(owner sdk.AccAddress) ([]Asset, error)
+	AssetsByReporter(reporter sdk.AccAddress) ([]Asset, error)
+	// AssetsByMaterial returns the assets made of the material
+	AssetsByMaterial(materialID string) ([]Asset, error)
+	Children(parentID string) ([]Asset, error)
+
+	Close() error
+}
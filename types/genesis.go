@@ -4,6 +4,8 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/auth"
 	"github.com/cosmos/cosmos-sdk/x/stake"
+
+	"github.com/icheckteam/ichain/x/asset"
 )
 
 //___________________________________________________________________________________
@@ -12,6 +14,7 @@ import (
 type GenesisState struct {
 	Accounts  []GenesisAccount   `json:"accounts"`
 	StakeData stake.GenesisState `json:"stake"`
+	AssetData asset.GenesisState `json:"asset"`
 }
 
 // GenesisAccount doesn't need pubkey or sequence
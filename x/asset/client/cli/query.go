@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+
+	assetclient "github.com/icheckteam/ichain/x/asset/client"
+)
+
+// GetCmdQueryAsset ...
+func GetCmdQueryAsset(cdc *wire.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "asset [asset_id]",
+		Short: "Query an asset with its properties, materials and reporters",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCLIContext().WithCodec(cdc)
+			record, err := assetclient.GetRecord(ctx, args[0], cdc)
+			if err != nil {
+				return err
+			}
+			return printJSON(cdc, record)
+		},
+	}
+}
+
+// GetCmdQueryChildren ...
+func GetCmdQueryChildren(cdc *wire.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "children [asset_id]",
+		Short: "Query the assets split from an asset",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCLIContext().WithCodec(cdc)
+			records, err := assetclient.GetChildRecords(ctx, args[0], cdc)
+			if err != nil {
+				return err
+			}
+			return printJSON(cdc, records.Sort())
+		},
+	}
+}
+
+// GetCmdQueryAccountAssets ...
+func GetCmdQueryAccountAssets(cdc *wire.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "account-assets [address]",
+		Short: "Query the assets owned by an address",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCLIContext().WithCodec(cdc)
+			addr, err := sdk.AccAddressFromBech32(args[0])
+			if err != nil {
+				return err
+			}
+			records, err := assetclient.GetAccountRecords(ctx, addr, cdc)
+			if err != nil {
+				return err
+			}
+			return printJSON(cdc, records.Sort())
+		},
+	}
+}
+
+// GetCmdQueryProposals ...
+func GetCmdQueryProposals(cdc *wire.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "proposals [asset_id]",
+		Short: "Query the proposals of an asset",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCLIContext().WithCodec(cdc)
+			proposals, err := assetclient.GetProposals(ctx, args[0], cdc)
+			if err != nil {
+				return err
+			}
+			return printJSON(cdc, proposals)
+		},
+	}
+}
+
+// GetCmdQueryHistory ...
+func GetCmdQueryHistory(cdc *wire.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "history [asset_id]",
+		Short: "Query the transactions of an asset and of its parents",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCLIContext().WithCodec(cdc)
+			txs, err := assetclient.QueryAssetTxs(ctx, args[0], cdc, 0)
+			if err != nil {
+				return err
+			}
+			return printJSON(cdc, txs)
+		},
+	}
+}
+
+func printJSON(cdc *wire.Codec, o interface{}) error {
+	output, err := wire.MarshalJSONIndent(cdc, o)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(output))
+	return nil
+}
@@ -0,0 +1,299 @@
+package cli
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/cosmos/cosmos-sdk/client/utils"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+	authcmd "github.com/cosmos/cosmos-sdk/x/auth/client/cli"
+	authctx "github.com/cosmos/cosmos-sdk/x/auth/client/context"
+
+	"github.com/icheckteam/ichain/x/asset"
+)
+
+const (
+	flagParent     = "parent"
+	flagOwner      = "owner"
+	flagFile       = "file"
+	flagRole       = "role"
+	flagProperties = "properties"
+)
+
+// sendTx signs the msg with the --name key and broadcasts it
+func sendTx(cdc *wire.Codec, build func(from sdk.AccAddress) (sdk.Msg, error)) error {
+	txCtx := authctx.NewTxContextFromCLI().WithCodec(cdc)
+	cliCtx := context.NewCLIContext().
+		WithCodec(cdc).
+		WithLogger(os.Stdout).
+		WithAccountDecoder(authcmd.GetAccountDecoder(cdc))
+
+	from, err := cliCtx.GetFromAddress()
+	if err != nil {
+		return err
+	}
+	msg, err := build(from)
+	if err != nil {
+		return err
+	}
+	if err := msg.ValidateBasic(); err != nil {
+		return err
+	}
+	return utils.SendTx(txCtx, cliCtx, []sdk.Msg{msg})
+}
+
+// GetCmdCreateAsset ...
+func GetCmdCreateAsset(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create [asset_id] [name] [quantity]",
+		Short: "Create an asset",
+		Args:  cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return sendTx(cdc, func(from sdk.AccAddress) (sdk.Msg, error) {
+				quantity, ok := sdk.NewIntFromString(args[2])
+				if !ok {
+					return nil, fmt.Errorf("invalid quantity %s", args[2])
+				}
+				msg := asset.NewMsgCreateAsset(from, args[0], args[1], quantity, viper.GetString(flagParent))
+				if s := viper.GetString(flagOwner); s != "" {
+					owner, err := sdk.AccAddressFromBech32(s)
+					if err != nil {
+						return nil, err
+					}
+					msg.Owner = owner
+				}
+				if file := viper.GetString(flagFile); file != "" {
+					props, err := readProperties(cdc, file)
+					if err != nil {
+						return nil, err
+					}
+					msg.Properties = props
+				}
+				return msg, nil
+			})
+		},
+	}
+	cmd.Flags().String(flagParent, "", "Id of the asset this one is split from")
+	cmd.Flags().String(flagOwner, "", "Identity of the sender owning the asset")
+	cmd.Flags().String(flagFile, "", "JSON file of the initial properties")
+	return cmd
+}
+
+// GetCmdAddQuantity ...
+func GetCmdAddQuantity(cdc *wire.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "add-quantity [asset_id] [quantity]",
+		Short: "Add to the quantity of an asset",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return sendTx(cdc, func(from sdk.AccAddress) (sdk.Msg, error) {
+				quantity, ok := sdk.NewIntFromString(args[1])
+				if !ok {
+					return nil, fmt.Errorf("invalid quantity %s", args[1])
+				}
+				return asset.MsgAddQuantity{Sender: from, AssetID: args[0], Quantity: quantity}, nil
+			})
+		},
+	}
+}
+
+// GetCmdSubtractQuantity ...
+func GetCmdSubtractQuantity(cdc *wire.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "subtract-quantity [asset_id] [quantity]",
+		Short: "Subtract from the quantity of an asset",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return sendTx(cdc, func(from sdk.AccAddress) (sdk.Msg, error) {
+				quantity, ok := sdk.NewIntFromString(args[1])
+				if !ok {
+					return nil, fmt.Errorf("invalid quantity %s", args[1])
+				}
+				return asset.MsgSubtractQuantity{Sender: from, AssetID: args[0], Quantity: quantity}, nil
+			})
+		},
+	}
+}
+
+// GetCmdUpdateProperties ...
+func GetCmdUpdateProperties(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "update-properties [asset_id]",
+		Short: "Update the properties of an asset from a JSON file",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return sendTx(cdc, func(from sdk.AccAddress) (sdk.Msg, error) {
+				props, err := readProperties(cdc, viper.GetString(flagFile))
+				if err != nil {
+					return nil, err
+				}
+				return asset.MsgUpdateProperties{Sender: from, AssetID: args[0], Properties: props}, nil
+			})
+		},
+	}
+	cmd.Flags().String(flagFile, "", "JSON file of the properties, as in the REST body")
+	return cmd
+}
+
+// GetCmdAddMaterials ...
+func GetCmdAddMaterials(cdc *wire.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "add-materials [asset_id] [material_id:amount]...",
+		Short: "Add materials to an asset, taken from the quantity of the material assets",
+		Args:  cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return sendTx(cdc, func(from sdk.AccAddress) (sdk.Msg, error) {
+				materials := asset.Materials{}
+				for _, arg := range args[1:] {
+					i := strings.LastIndex(arg, ":")
+					if i <= 0 {
+						return nil, fmt.Errorf("invalid material %s, expected material_id:amount", arg)
+					}
+					amount, ok := sdk.NewIntFromString(arg[i+1:])
+					if !ok {
+						return nil, fmt.Errorf("invalid amount in %s", arg)
+					}
+					materials = append(materials, asset.Material{RecordID: arg[:i], Amount: amount})
+				}
+				return asset.MsgAddMaterials{Sender: from, AssetID: args[0], Amount: materials}, nil
+			})
+		},
+	}
+}
+
+// GetCmdFinalize ...
+func GetCmdFinalize(cdc *wire.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "finalize [asset_id]",
+		Short: "Finalize an asset, it cannot be changed afterwards",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return sendTx(cdc, func(from sdk.AccAddress) (sdk.Msg, error) {
+				return asset.MsgFinalize{Sender: from, AssetID: args[0]}, nil
+			})
+		},
+	}
+}
+
+// GetCmdCreateProposal ...
+func GetCmdCreateProposal(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "propose [asset_id] [recipient]",
+		Short: "Propose a recipient to become an owner or a reporter of an asset",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return sendTx(cdc, func(from sdk.AccAddress) (sdk.Msg, error) {
+				recipient, err := sdk.AccAddressFromBech32(args[1])
+				if err != nil {
+					return nil, err
+				}
+				role, err := parseRole(viper.GetString(flagRole))
+				if err != nil {
+					return nil, err
+				}
+				var props []string
+				if s := viper.GetString(flagProperties); s != "" {
+					props = strings.Split(s, ",")
+				}
+				return asset.MsgCreateProposal{
+					AssetID:    args[0],
+					Sender:     from,
+					Recipient:  recipient,
+					Properties: props,
+					Role:       role,
+				}, nil
+			})
+		},
+	}
+	cmd.Flags().String(flagRole, "reporter", "Role proposed to the recipient (reporter|owner)")
+	cmd.Flags().String(flagProperties, "", "Comma separated properties the recipient may update")
+	return cmd
+}
+
+// GetCmdAnswerProposal ...
+func GetCmdAnswerProposal(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "answer [asset_id] [accept|reject]",
+		Short: "Answer a proposal made to the --name key",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return sendTx(cdc, func(from sdk.AccAddress) (sdk.Msg, error) {
+				var response asset.ProposalStatus
+				switch args[1] {
+				case "accept":
+					response = asset.StatusAccepted
+				case "reject":
+					response = asset.StatusRejected
+				default:
+					return nil, fmt.Errorf("invalid answer %s, expected accept or reject", args[1])
+				}
+				role, err := parseRole(viper.GetString(flagRole))
+				if err != nil {
+					return nil, err
+				}
+				return asset.MsgAnswerProposal{
+					AssetID:   args[0],
+					Sender:    from,
+					Recipient: from,
+					Response:  response,
+					Role:      role,
+				}, nil
+			})
+		},
+	}
+	cmd.Flags().String(flagRole, "reporter", "Role of the proposal (reporter|owner)")
+	return cmd
+}
+
+// GetCmdRevokeReporter ...
+func GetCmdRevokeReporter(cdc *wire.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "revoke-reporter [asset_id] [reporter]",
+		Short: "Revoke a reporter of an asset",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return sendTx(cdc, func(from sdk.AccAddress) (sdk.Msg, error) {
+				reporter, err := sdk.AccAddressFromBech32(args[1])
+				if err != nil {
+					return nil, err
+				}
+				return asset.MsgRevokeReporter{Sender: from, Reporter: reporter, AssetID: args[0]}, nil
+			})
+		},
+	}
+}
+
+// readProperties decodes a JSON array of properties with the codec, the
+// same encoding the REST server accepts
+func readProperties(cdc *wire.Codec, file string) (asset.Properties, error) {
+	if file == "" {
+		return nil, fmt.Errorf("--%s is required", flagFile)
+	}
+	bz, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var props asset.Properties
+	if err := cdc.UnmarshalJSON(bz, &props); err != nil {
+		return nil, fmt.Errorf("invalid properties in %s: %v", file, err)
+	}
+	return props, nil
+}
+
+func parseRole(s string) (asset.ProposalRole, error) {
+	switch s {
+	case "reporter":
+		return asset.RoleReporter, nil
+	case "owner":
+		return asset.RoleOwner, nil
+	default:
+		return 0, fmt.Errorf("invalid role %s, expected reporter or owner", s)
+	}
+}
@@ -0,0 +1,49 @@
+package client
+
+import (
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/cosmos/cosmos-sdk/wire"
+
+	"github.com/icheckteam/ichain/x/asset"
+)
+
+// GetDocuments returns the documents anchored to an asset
+func GetDocuments(ctx context.CLIContext, assetID string, cdc *wire.Codec) ([]asset.Document, error) {
+	kvs, err := ctx.QuerySubspace(asset.GetDocumentsKey(assetID), storeName)
+	if err != nil {
+		return nil, err
+	}
+	docs := make([]asset.Document, len(kvs))
+	for i, kv := range kvs {
+		if docs[i], err = asset.UnmarshalDocument(cdc, kv.Value); err != nil {
+			return nil, err
+		}
+	}
+	return docs, nil
+}
+
+// GetDocumentsByHash returns the anchors of a content, given by its sha256
+// digest, on every asset
+func GetDocumentsByHash(ctx context.CLIContext, digest []byte, cdc *wire.Codec) ([]asset.Document, error) {
+	kvs, err := ctx.QuerySubspace(asset.GetDocumentHashesKey(digest), storeName)
+	if err != nil {
+		return nil, err
+	}
+	docs := []asset.Document{}
+	for _, kv := range kvs {
+		assetID := asset.AssetIDFromDocumentHashKey(kv.Key)
+		res, err := ctx.QueryStore(asset.GetDocumentKey(assetID, digest), storeName)
+		if err != nil {
+			return nil, err
+		}
+		if len(res) == 0 {
+			continue
+		}
+		doc, err := asset.UnmarshalDocument(cdc, res)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, doc)
+	}
+	return docs, nil
+}
@@ -0,0 +1,293 @@
+package client
+
+import (
+	"bytes"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+
+	"github.com/icheckteam/ichain/client/tx"
+	"github.com/icheckteam/ichain/x/asset"
+	"github.com/icheckteam/ichain/x/identity"
+	identityclient "github.com/icheckteam/ichain/x/identity/client"
+)
+
+// Properties read by the passport, set like any other property. An asset
+// inherits them from the assets it was split from, the closest one wins
+// except for recalls: a recalled batch recalls everything split from it.
+const (
+	PropertyRecalled           = "recalled"            // boolean
+	PropertyRecallReason       = "recall_reason"       // string
+	PropertyExpiresAt          = "expires_at"          // number, unix time
+	PropertyPassportProperties = "passport_properties" // enum, the names shown
+)
+
+// Passport is the curated view of an asset shown to the consumers scanning
+// it, as of a block
+type Passport struct {
+	AssetID  string  `json:"asset_id"`
+	Name     string  `json:"name"`
+	Type     string  `json:"type"`
+	SubType  string  `json:"subtype"`
+	Barcode  string  `json:"barcode"`
+	Quantity sdk.Int `json:"quantity"`
+	Unit     string  `json:"unit"`
+	Final    bool    `json:"final"`
+
+	// Brand is the profile of the owner of the root asset, Address is set
+	// even when it has no profile
+	Brand        identity.Profile   `json:"brand"`
+	Origin       []OriginStep       `json:"origin"` // from the root asset to this one
+	Certificates CertificateSummary `json:"certificates"`
+	Recall       RecallStatus       `json:"recall"`
+	Expiry       ExpiryStatus       `json:"expiry"`
+	Properties   []PassportProperty `json:"properties"`
+
+	Height int64 `json:"height"`
+	Time   int64 `json:"time"` // of the block, the statuses are as of it
+}
+
+// OriginStep is an asset of the origin chain
+type OriginStep struct {
+	AssetID   string         `json:"asset_id"`
+	Name      string         `json:"name"`
+	Owner     sdk.AccAddress `json:"owner"`
+	OwnerName string         `json:"owner_name,omitempty"`
+	Quantity  sdk.Int        `json:"quantity"`
+	Created   int64          `json:"created"`
+	Height    int64          `json:"height"`
+}
+
+// CertificateSummary sums up the certs of the brand
+type CertificateSummary struct {
+	Valid int               `json:"valid"`
+	Certs []CertificateLine `json:"certs"`
+}
+
+// CertificateLine is a cert of the brand and its status
+type CertificateLine struct {
+	Property      string         `json:"property"`
+	Certifier     sdk.AccAddress `json:"certifier"`
+	CertifierName string         `json:"certifier_name,omitempty"`
+	Status        string         `json:"status"`
+	ExpiresAt     int64          `json:"expires_at"`
+}
+
+// RecallStatus ...
+type RecallStatus struct {
+	Recalled bool   `json:"recalled"`
+	AssetID  string `json:"asset_id,omitempty"` // the asset of the chain the recall was set on
+	Reason   string `json:"reason,omitempty"`
+}
+
+// ExpiryStatus ...
+type ExpiryStatus struct {
+	ExpiresAt int64 `json:"expires_at"` // 0 if the asset never expires
+	Expired   bool  `json:"expired"`
+}
+
+// PassportProperty is an allowed property and its value
+type PassportProperty struct {
+	Name  string      `json:"name"`
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+// LatestHeight returns the height of the last committed block
+func LatestHeight(ctx context.CLIContext) (int64, error) {
+	node, err := ctx.GetNode()
+	if err != nil {
+		return 0, err
+	}
+	status, err := node.Status()
+	if err != nil {
+		return 0, err
+	}
+	return status.SyncInfo.LatestBlockHeight, nil
+}
+
+// GetPassport builds the passport of an asset from the state at the height,
+// which must be committed
+func GetPassport(ctx context.CLIContext, assetID string, cdc *wire.Codec, height int64) (Passport, error) {
+	ctx.Height = height
+
+	chain, err := getOriginChain(ctx, assetID, cdc)
+	if err != nil {
+		return Passport{}, err
+	}
+	root := chain[0]
+
+	addrs := []sdk.AccAddress{}
+	for _, record := range chain {
+		addrs = append(addrs, record.Owner)
+	}
+	certs, err := identityclient.QueryCerts(ctx, cdc, root.Owner)
+	if err != nil {
+		return Passport{}, err
+	}
+	for _, cert := range certs {
+		addrs = append(addrs, cert.Certifier)
+	}
+	addrs, err = registeredAddrs(ctx, addrs)
+	if err != nil {
+		return Passport{}, err
+	}
+	profiles, err := identityclient.QueryProfiles(ctx, cdc, addrs...)
+	if err != nil {
+		return Passport{}, err
+	}
+
+	blockTime, err := tx.BlockTime(ctx, height)
+	if err != nil {
+		return Passport{}, err
+	}
+	passport := NewPassport(chain, certs, profiles, blockTime)
+	passport.Height = height
+	return passport, nil
+}
+
+// registeredAddrs keeps the addresses registered as identities by their own
+// key, the profile of an address registered by another key was set by
+// owners its holder didn't choose and would let them name its brand
+func registeredAddrs(ctx context.CLIContext, addrs []sdk.AccAddress) ([]sdk.AccAddress, error) {
+	registered := []sdk.AccAddress{}
+	for _, addr := range addrs {
+		ok, err := identityclient.QueryRegistered(ctx, addr)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			registered = append(registered, addr)
+		}
+	}
+	return registered, nil
+}
+
+// getOriginChain returns the asset and the assets it was split from, the
+// root first
+func getOriginChain(ctx context.CLIContext, assetID string, cdc *wire.Codec) ([]asset.RecordOutput, error) {
+	var chain []asset.RecordOutput
+	for id := assetID; id != ""; {
+		record, err := GetRecord(ctx, id, cdc, "properties")
+		if err != nil {
+			return nil, err
+		}
+		chain = append([]asset.RecordOutput{*record}, chain...)
+		id = record.Parent
+	}
+	return chain, nil
+}
+
+// NewPassport builds the passport of the last asset of the origin chain
+// with the certs of its brand at the block time t
+func NewPassport(chain []asset.RecordOutput, certs identity.Certs, profiles []identity.Profile, t int64) Passport {
+	record := chain[len(chain)-1]
+	root := chain[0]
+	passport := Passport{
+		AssetID:  record.ID,
+		Name:     record.Name,
+		Type:     record.Type,
+		SubType:  record.SubType,
+		Barcode:  record.Barcode,
+		Quantity: record.Quantity,
+		Final:    record.Final,
+		Brand:    identity.Profile{Address: root.Owner},
+		Time:     t,
+	}
+	if profile, found := findProfile(profiles, root.Owner); found {
+		passport.Brand = profile
+	}
+
+	for _, r := range chain {
+		step := OriginStep{
+			AssetID:  r.ID,
+			Name:     r.Name,
+			Owner:    r.Owner,
+			Quantity: r.Quantity,
+			Created:  r.Created,
+			Height:   r.Height,
+		}
+		if profile, found := findProfile(profiles, r.Owner); found {
+			step.OwnerName = profile.Name
+		}
+		passport.Origin = append(passport.Origin, step)
+	}
+
+	passport.Certificates.Certs = []CertificateLine{}
+	for _, cert := range certs {
+		line := CertificateLine{
+			Property:  cert.Property,
+			Certifier: cert.Certifier,
+			Status:    cert.StatusAt(t),
+			ExpiresAt: cert.ExpiresAt,
+		}
+		if profile, found := findProfile(profiles, cert.Certifier); found {
+			line.CertifierName = profile.Name
+		}
+		if line.Status == identity.CertStatusValid {
+			passport.Certificates.Valid++
+		}
+		passport.Certificates.Certs = append(passport.Certificates.Certs, line)
+	}
+
+	for _, r := range chain {
+		if p, found := findProperty(r.Properties, PropertyRecalled); found && p.BooleanValue {
+			passport.Recall = RecallStatus{Recalled: true, AssetID: r.ID}
+			if reason, found := findProperty(r.Properties, PropertyRecallReason); found {
+				passport.Recall.Reason = reason.StringValue
+			}
+			break
+		}
+	}
+
+	if p, found := inheritedProperty(chain, PropertyExpiresAt); found && p.NumberValue > 0 {
+		passport.Expiry = ExpiryStatus{ExpiresAt: p.NumberValue, Expired: t >= p.NumberValue}
+	}
+	if p, found := inheritedProperty(chain, "unit"); found {
+		passport.Unit = p.StringValue
+	}
+
+	passport.Properties = []PassportProperty{}
+	if allowed, found := inheritedProperty(chain, PropertyPassportProperties); found {
+		for _, name := range allowed.EnumValue {
+			if p, found := inheritedProperty(chain, name); found {
+				passport.Properties = append(passport.Properties, PassportProperty{
+					Name:  p.Name,
+					Type:  asset.PropertyTypeToString(p.Type),
+					Value: p.GetValue(),
+				})
+			}
+		}
+	}
+	return passport
+}
+
+// inheritedProperty returns the property of the closest asset of the chain
+// that has it
+func inheritedProperty(chain []asset.RecordOutput, name string) (asset.Property, bool) {
+	for i := len(chain) - 1; i >= 0; i-- {
+		if p, found := findProperty(chain[i].Properties, name); found {
+			return p, true
+		}
+	}
+	return asset.Property{}, false
+}
+
+func findProperty(props asset.Properties, name string) (asset.Property, bool) {
+	for _, p := range props {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return asset.Property{}, false
+}
+
+func findProfile(profiles []identity.Profile, addr sdk.AccAddress) (identity.Profile, bool) {
+	for _, profile := range profiles {
+		if bytes.Equal(profile.Address, addr) {
+			return profile, true
+		}
+	}
+	return identity.Profile{}, false
+}
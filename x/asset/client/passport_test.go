@@ -0,0 +1,85 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/icheckteam/ichain/x/asset"
+	"github.com/icheckteam/ichain/x/identity"
+)
+
+func TestNewPassport(t *testing.T) {
+	brand := sdk.AccAddress([]byte("brand"))
+	retailer := sdk.AccAddress([]byte("retailer"))
+	certifier := sdk.AccAddress([]byte("certifier"))
+
+	chain := []asset.RecordOutput{
+		{ID: "batch", Name: "Coffee batch", Owner: brand, Quantity: sdk.NewInt(100), Created: 10, Properties: asset.Properties{
+			{Name: "unit", Type: asset.PropertyTypeString, StringValue: "kg"},
+			{Name: "origin", Type: asset.PropertyTypeString, StringValue: "Da Lat"},
+			{Name: "secret", Type: asset.PropertyTypeString, StringValue: "supplier price"},
+			{Name: PropertyExpiresAt, Type: asset.PropertyTypeNumber, NumberValue: 1000},
+			{Name: PropertyRecalled, Type: asset.PropertyTypeBoolean, BooleanValue: true},
+			{Name: PropertyRecallReason, Type: asset.PropertyTypeString, StringValue: "mold"},
+			{Name: PropertyPassportProperties, Type: asset.PropertyTypeEnum, EnumValue: []string{"origin", "roast"}},
+		}},
+		{ID: "bag", Name: "Coffee bag", Owner: retailer, Parent: "batch", Root: "batch", Quantity: sdk.NewInt(1), Created: 20, Properties: asset.Properties{
+			{Name: "roast", Type: asset.PropertyTypeString, StringValue: "medium"},
+			{Name: PropertyExpiresAt, Type: asset.PropertyTypeNumber, NumberValue: 500},
+		}},
+	}
+	certs := identity.Certs{
+		{Property: "organic", Certifier: certifier, Owner: brand, CreatedAt: 5},
+		{Property: "fairtrade", Certifier: certifier, Owner: brand, CreatedAt: 5, ExpiresAt: 100},
+	}
+	profiles := []identity.Profile{
+		{Address: brand, Name: "Brand", Country: "VN"},
+		{Address: certifier, Name: "Certifier"},
+	}
+
+	p := NewPassport(chain, certs, profiles, 200)
+	assert.Equal(t, "bag", p.AssetID)
+	assert.Equal(t, "kg", p.Unit)
+	assert.Equal(t, "Brand", p.Brand.Name)
+	assert.Equal(t, int64(200), p.Time)
+
+	require.Equal(t, 2, len(p.Origin))
+	assert.Equal(t, "batch", p.Origin[0].AssetID)
+	assert.Equal(t, "Brand", p.Origin[0].OwnerName)
+	assert.Equal(t, "bag", p.Origin[1].AssetID)
+	assert.Empty(t, p.Origin[1].OwnerName)
+
+	assert.Equal(t, 1, p.Certificates.Valid)
+	require.Equal(t, 2, len(p.Certificates.Certs))
+	assert.Equal(t, identity.CertStatusValid, p.Certificates.Certs[0].Status)
+	assert.Equal(t, "Certifier", p.Certificates.Certs[0].CertifierName)
+	assert.Equal(t, identity.CertStatusExpired, p.Certificates.Certs[1].Status)
+
+	// the recall of the batch applies to the bag
+	assert.Equal(t, RecallStatus{Recalled: true, AssetID: "batch", Reason: "mold"}, p.Recall)
+
+	// the closest expiry wins
+	assert.Equal(t, ExpiryStatus{ExpiresAt: 500, Expired: false}, p.Expiry)
+	assert.True(t, NewPassport(chain, certs, profiles, 500).Expiry.Expired)
+
+	// only the allowed properties are shown, in the order of the list
+	assert.Equal(t, []PassportProperty{
+		{Name: "origin", Type: "string", Value: "Da Lat"},
+		{Name: "roast", Type: "string", Value: "medium"},
+	}, p.Properties)
+}
+
+func TestNewPassportDefaults(t *testing.T) {
+	owner := sdk.AccAddress([]byte("owner"))
+	p := NewPassport([]asset.RecordOutput{{ID: "a", Owner: owner, Quantity: sdk.NewInt(1)}}, nil, nil, 100)
+	assert.Equal(t, owner, p.Brand.Address)
+	assert.False(t, p.Recall.Recalled)
+	assert.Equal(t, ExpiryStatus{}, p.Expiry)
+	assert.Empty(t, p.Properties)
+	assert.NotNil(t, p.Properties)
+	assert.NotNil(t, p.Certificates.Certs)
+}
@@ -0,0 +1,126 @@
+package rest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/cosmos/cosmos-sdk/crypto/keys"
+	"github.com/cosmos/cosmos-sdk/wire"
+
+	"github.com/icheckteam/ichain/x/asset"
+	assetclient "github.com/icheckteam/ichain/x/asset/client"
+)
+
+// maxVerifyUploadSize is the largest file the verify endpoint hashes
+const maxVerifyUploadSize = 64 << 20
+
+type anchorDocumentBody struct {
+	BaseReq   baseBody `json:"base_req"`
+	Hash      string   `json:"hash"`
+	MediaType string   `json:"media_type"`
+	Size      int64    `json:"size"`
+	Title     string   `json:"title"`
+}
+
+func (b anchorDocumentBody) ValidateBasic() error {
+	return b.BaseReq.Validate()
+}
+
+// verifyDocumentOutput tells whether, when and by whom a file was anchored
+type verifyDocumentOutput struct {
+	SHA256    string           `json:"sha256"`
+	Size      int64            `json:"size"`
+	Anchored  bool             `json:"anchored"`
+	Documents []asset.Document `json:"documents"`
+}
+
+func anchorDocumentHandlerFn(ctx context.CLIContext, cdc *wire.Codec, kb keys.Keybase) func(http.ResponseWriter, *http.Request) {
+	return withErrHandler(func(w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+
+		var m anchorDocumentBody
+		if err := validateAndGetDecodeBody(r, cdc, &m); err != nil {
+			return err
+		}
+
+		sender, err := m.BaseReq.sender(kb)
+		if err != nil {
+			return err
+		}
+		// build message
+		msg := asset.MsgAnchorDocument{
+			Sender:    sender,
+			AssetID:   vars["id"],
+			Hash:      m.Hash,
+			MediaType: m.MediaType,
+			Size:      m.Size,
+			Title:     m.Title,
+		}
+		if err := msg.ValidateBasic(); err != nil {
+			return err
+		}
+		signAndBuild(ctx, cdc, w, m.BaseReq, msg)
+		return nil
+	})
+}
+
+func queryDocumentsHandlerFn(ctx context.CLIContext, cdc *wire.Codec) func(http.ResponseWriter, *http.Request) {
+	return withErrHandler(func(w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		docs, err := assetclient.GetDocuments(ctx, vars["id"], cdc)
+		if err != nil {
+			return err
+		}
+		WriteJSON(w, cdc, docs)
+		return nil
+	})
+}
+
+// verifyDocumentHandlerFn hashes the uploaded file, sent as the "file" field
+// of a multipart form or as the raw body, and returns its anchors. The
+// asset_id query parameter only keeps the anchors to that asset.
+func verifyDocumentHandlerFn(ctx context.CLIContext, cdc *wire.Codec) func(http.ResponseWriter, *http.Request) {
+	return withErrHandler(func(w http.ResponseWriter, r *http.Request) error {
+		r.Body = http.MaxBytesReader(w, r.Body, maxVerifyUploadSize)
+		var content io.Reader = r.Body
+		if file, _, err := r.FormFile("file"); err == nil {
+			defer file.Close()
+			content = file
+		} else if err != http.ErrNotMultipart {
+			return err
+		}
+
+		hasher := sha256.New()
+		size, err := io.Copy(hasher, content)
+		if err != nil {
+			return err
+		}
+		digest := hasher.Sum(nil)
+
+		docs, err := assetclient.GetDocumentsByHash(ctx, digest, cdc)
+		if err != nil {
+			return err
+		}
+		if assetID := r.URL.Query().Get("asset_id"); assetID != "" {
+			filtered := []asset.Document{}
+			for _, doc := range docs {
+				if doc.AssetID == assetID {
+					filtered = append(filtered, doc)
+				}
+			}
+			docs = filtered
+		}
+		WriteJSON(w, cdc, verifyDocumentOutput{
+			SHA256:    hex.EncodeToString(digest),
+			Size:      size,
+			Anchored:  len(docs) > 0,
+			Documents: docs,
+		})
+		return nil
+	})
+}
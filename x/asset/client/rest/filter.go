@@ -1,76 +1,12 @@
 package rest
 
 import (
-	"fmt"
-
-	"github.com/cosmos/cosmos-sdk/client/context"
 	sdk "github.com/cosmos/cosmos-sdk/types"
-	"github.com/cosmos/cosmos-sdk/wire"
 	"github.com/cosmos/cosmos-sdk/x/auth"
 	"github.com/icheckteam/ichain/client/tx"
 	"github.com/icheckteam/ichain/x/asset"
 )
 
-func queryAssetTxs(ctx context.CLIContext, assetID string, cdc *wire.Codec, height int64) ([]tx.TxInfo, error) {
-	record, err := getRecord(ctx, assetID, cdc)
-	if err != nil {
-		return nil, err
-	}
-	node, err := ctx.GetNode()
-	if err != nil {
-		return nil, err
-	}
-
-	query := fmt.Sprintf("asset_id='%s'", record.ID)
-	page := 0
-	perPage := 500
-	prove := false
-	res, err := node.TxSearch(query, prove, page, perPage)
-	if err != nil {
-		return nil, err
-	}
-	info, err := tx.FormatTxResults(cdc, res.Txs)
-	if err != nil {
-		return nil, err
-	}
-
-	if height > 0 {
-		info = filterByHeight(info, height)
-	}
-
-	// load tx from parents ....
-	if record.Parent != "" {
-		txs, err := queryAssetTxs(ctx, record.Parent, cdc, record.Height)
-		if err != nil {
-			return nil, err
-		}
-		info = append(info, txs...)
-	}
-
-	// get block time
-	for index, inf := range info {
-		block, err := tx.GetBlock(ctx, &inf.Height)
-		if err != nil {
-			return nil, err
-		}
-		info[index].Time = block.Block.Header.Time.Unix()
-	}
-	return info, nil
-}
-
-func filterByHeight(infos []tx.TxInfo, height int64) []tx.TxInfo {
-	newInfos := make([]tx.TxInfo, len(infos))
-	var index = 0
-	for _, info := range infos {
-		if info.Height > height {
-			continue
-		}
-		newInfos[index] = info
-		index++
-	}
-	return newInfos[:index]
-}
-
 func newHistoryUpdateProperties(sender sdk.AccAddress, memo string, time int64, props asset.Properties, name string) []asset.HistoryUpdateProperty {
 	history := make([]asset.HistoryUpdateProperty, len(props))
 	var i = 0
@@ -12,6 +12,7 @@ import (
 	"github.com/cosmos/cosmos-sdk/wire"
 
 	"github.com/icheckteam/ichain/x/asset"
+	assetclient "github.com/icheckteam/ichain/x/asset/client"
 )
 
 // AssetOutput ..
@@ -27,7 +28,7 @@ type AssetOutput struct {
 func queryAssetRequestHandlerFn(ctx context.CLIContext, storeName string, cdc *wire.Codec) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		vars := mux.Vars(r)
-		record, err := getRecord(ctx, vars["id"], cdc)
+		record, err := assetclient.GetRecord(ctx, vars["id"], cdc)
 
 		if err != nil {
 			w.WriteHeader(http.StatusNotFound)
@@ -71,7 +72,7 @@ func queryAccountAssetsHandlerFn(ctx context.CLIContext, storeName string, cdc *
 func assetTxsHandlerFn(ctx context.CLIContext, storeName string, cdc *wire.Codec) func(http.ResponseWriter, *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		vars := mux.Vars(r)
-		info, err := queryAssetTxs(ctx, vars["id"], cdc, 0)
+		info, err := assetclient.QueryAssetTxs(ctx, vars["id"], cdc, 0)
 		if err != nil {
 			w.WriteHeader(http.StatusInternalServerError)
 			w.Write([]byte(err.Error()))
@@ -90,7 +91,7 @@ func assetTxsHandlerFn(ctx context.CLIContext, storeName string, cdc *wire.Codec
 func queryHistoryUpdatePropertiesHandlerFn(ctx context.CLIContext, cdc *wire.Codec) func(http.ResponseWriter, *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		vars := mux.Vars(r)
-		info, err := queryAssetTxs(ctx, vars["id"], cdc, 0)
+		info, err := assetclient.QueryAssetTxs(ctx, vars["id"], cdc, 0)
 		if err != nil {
 			w.WriteHeader(http.StatusInternalServerError)
 			w.Write([]byte(err.Error()))
@@ -103,7 +104,7 @@ func queryHistoryUpdatePropertiesHandlerFn(ctx context.CLIContext, cdc *wire.Cod
 func queryHistoryOwnersHandlerFn(ctx context.CLIContext, cdc *wire.Codec) func(http.ResponseWriter, *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		vars := mux.Vars(r)
-		info, err := queryAssetTxs(ctx, vars["id"], cdc, 0)
+		info, err := assetclient.QueryAssetTxs(ctx, vars["id"], cdc, 0)
 		if err != nil {
 			w.WriteHeader(http.StatusInternalServerError)
 			w.Write([]byte(err.Error()))
@@ -115,7 +116,7 @@ func queryHistoryOwnersHandlerFn(ctx context.CLIContext, cdc *wire.Codec) func(h
 func queryHistoryTransferMaterialsHandlerFn(ctx context.CLIContext, cdc *wire.Codec) func(http.ResponseWriter, *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		vars := mux.Vars(r)
-		info, err := queryAssetTxs(ctx, vars["id"], cdc, 0)
+		info, err := assetclient.QueryAssetTxs(ctx, vars["id"], cdc, 0)
 		if err != nil {
 			w.WriteHeader(http.StatusInternalServerError)
 			w.Write([]byte(err.Error()))
@@ -128,7 +129,7 @@ func queryHistoryTransferMaterialsHandlerFn(ctx context.CLIContext, cdc *wire.Co
 func queryAssetChildrensHandlerFn(ctx context.CLIContext, storeName string, cdc *wire.Codec, kb keys.Keybase) func(http.ResponseWriter, *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		vars := mux.Vars(r)
-		items, err := queryAssetChildrens(ctx, storeName, cdc, vars["id"])
+		items, err := assetclient.GetChildRecords(ctx, vars["id"], cdc)
 		if err != nil {
 			w.WriteHeader(http.StatusInternalServerError)
 			w.Write([]byte(fmt.Sprintf("Couldn't get assets. Error: %s", err.Error())))
@@ -144,16 +145,7 @@ func queryAccountAssets(ctx context.CLIContext, storeName string, cdc *wire.Code
 	if err != nil {
 		return nil, err
 	}
-	return getRecordsByAccount(ctx, address, cdc)
-}
-
-func queryAssetChildrens(ctx context.CLIContext, storeName string, cdc *wire.Codec, assetID string) (asset.RecordsOutput, error) {
-	kvs, err := ctx.QuerySubspace(asset.GetAssetChildrensKey(assetID), storeName)
-	if err != nil {
-		return nil, err
-	}
-
-	return getRecordsByKvs(ctx, kvs, cdc)
+	return assetclient.GetAccountRecords(ctx, address, cdc)
 }
 
 func queryReporterAssetsHandlerFn(ctx context.CLIContext, storeName string, cdc *wire.Codec, kb keys.Keybase) func(http.ResponseWriter, *http.Request) {
@@ -167,35 +159,20 @@ func queryReporterAssetsHandlerFn(ctx context.CLIContext, storeName string, cdc
 			return
 		}
 
-		kvs, err := ctx.QuerySubspace(asset.GetReporterAssetsKey(address), storeName)
+		records, err := assetclient.GetReporterRecords(ctx, address, cdc)
 		if err != nil {
 			w.WriteHeader(http.StatusNotFound)
-			w.Write([]byte(fmt.Sprintf("Couldn't get proposals. Error: %s", err.Error())))
-			return
-		}
-
-		records, err := getRecordsByKvs(ctx, kvs, cdc)
-		records = records.Sort()
-		if err != nil {
-			w.WriteHeader(http.StatusBadRequest)
-			w.Write([]byte(err.Error()))
+			w.Write([]byte(fmt.Sprintf("Couldn't get assets. Error: %s", err.Error())))
 			return
 		}
-		WriteJSON(w, cdc, records)
+		WriteJSON(w, cdc, records.Sort())
 	}
 }
 
 func queryProposalsHandlerFn(ctx context.CLIContext, storeName string, cdc *wire.Codec, kb keys.Keybase) func(http.ResponseWriter, *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		vars := mux.Vars(r)
-		kvs, err := ctx.QuerySubspace(asset.GetProposalsKey(vars["asset_id"]), storeName)
-		if err != nil {
-			w.WriteHeader(http.StatusNotFound)
-			w.Write([]byte(fmt.Sprintf("Couldn't get proposals. Error: %s", err.Error())))
-			return
-		}
-
-		proposals, err := getProposals(ctx, kvs, cdc)
+		proposals, err := assetclient.GetProposals(ctx, vars["id"], cdc)
 		if err != nil {
 			w.WriteHeader(http.StatusNotFound)
 			w.Write([]byte(fmt.Sprintf("Couldn't get proposals. Error: %s", err.Error())))
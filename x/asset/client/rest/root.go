@@ -23,6 +23,10 @@ func RegisterRoutes(ctx context.CLIContext, r *mux.Router, cdc *wire.Codec, kb k
 	r.HandleFunc("/assets/{id}/materials", addMaterialsHandlerFn(ctx, cdc, kb)).Methods("POST")
 	r.HandleFunc("/assets/{id}/materials/history", queryHistoryTransferMaterialsHandlerFn(ctx, cdc)).Methods("GET")
 	r.HandleFunc("/assets/{id}/finalize", finalizeHandlerFn(ctx, cdc, kb)).Methods("POST")
+	r.HandleFunc("/assets/{id}/documents", anchorDocumentHandlerFn(ctx, cdc, kb)).Methods("POST")
+	r.HandleFunc("/assets/{id}/documents", queryDocumentsHandlerFn(ctx, cdc)).Methods("GET")
+	r.HandleFunc("/documents/verify", verifyDocumentHandlerFn(ctx, cdc)).Methods("POST")
+	r.HandleFunc("/verify/{id}", verifyAssetHandlerFn(ctx, cdc)).Methods("GET")
 	r.HandleFunc("/assets/{id}/reporters/{address}/revoke", revokeReporterHandlerFn(ctx, cdc, kb)).Methods("POST")
 	r.HandleFunc("/assets/{id}/proposals", createProposalHandlerFn(ctx, cdc, kb)).Methods("POST")
 	r.HandleFunc("/assets/{id}/proposals", queryProposalsHandlerFn(ctx, storeName, cdc, kb)).Methods("GET")
@@ -19,7 +19,7 @@ func createProposalHandlerFn(ctx context.CLIContext, cdc *wire.Codec, kb keys.Ke
 		if err := validateAndGetDecodeBody(r, cdc, &m); err != nil {
 			return err
 		}
-		info, err := kb.Get(m.BaseReq.Name)
+		sender, err := m.BaseReq.sender(kb)
 		if err != nil {
 			return err
 		}
@@ -28,7 +28,7 @@ func createProposalHandlerFn(ctx context.CLIContext, cdc *wire.Codec, kb keys.Ke
 			return err
 		}
 		msg := asset.MsgCreateProposal{
-			Sender:     sdk.AccAddress(info.GetPubKey().Address()),
+			Sender:     sender,
 			Properties: m.Properties,
 			Role:       m.Role,
 			AssetID:    vars["id"],
@@ -48,7 +48,7 @@ func answerProposalHandlerFn(ctx context.CLIContext, cdc *wire.Codec, kb keys.Ke
 			return err
 		}
 
-		info, err := kb.Get(m.BaseReq.Name)
+		sender, err := m.BaseReq.sender(kb)
 		if err != nil {
 			return err
 		}
@@ -57,7 +57,7 @@ func answerProposalHandlerFn(ctx context.CLIContext, cdc *wire.Codec, kb keys.Ke
 			return err
 		}
 		msg := asset.MsgAnswerProposal{
-			Sender:    sdk.AccAddress(info.GetPubKey().Address()),
+			Sender:    sender,
 			Recipient: recipient,
 			Response:  m.Response,
 			AssetID:   vars["id"],
@@ -77,13 +77,13 @@ func addAssetQuantityHandlerFn(ctx context.CLIContext, cdc *wire.Codec, kb keys.
 			return err
 		}
 
-		info, err := kb.Get(m.BaseReq.Name)
+		sender, err := m.BaseReq.sender(kb)
 		if err != nil {
 			return err
 		}
 		// build message
 		msg := asset.MsgAddQuantity{
-			Sender:   sdk.AccAddress(info.GetPubKey().Address()),
+			Sender:   sender,
 			AssetID:  vars["id"],
 			Quantity: m.Quantity,
 		}
@@ -100,14 +100,14 @@ func addMaterialsHandlerFn(ctx context.CLIContext, cdc *wire.Codec, kb keys.Keyb
 		if err := validateAndGetDecodeBody(r, cdc, &m); err != nil {
 			return err
 		}
-		info, err := kb.Get(m.BaseReq.Name)
+		sender, err := m.BaseReq.sender(kb)
 		if err != nil {
 			return err
 		}
 
 		msg := asset.MsgAddMaterials{
 			AssetID: vars["id"],
-			Sender:  sdk.AccAddress(info.GetPubKey().Address()),
+			Sender:  sender,
 			Amount:  m.Amount,
 		}
 
@@ -127,13 +127,13 @@ func finalizeHandlerFn(ctx context.CLIContext, cdc *wire.Codec, kb keys.Keybase)
 			return err
 		}
 
-		info, err := kb.Get(m.BaseReq.Name)
+		sender, err := m.BaseReq.sender(kb)
 		if err != nil {
 			return err
 		}
 		// build message
 		msg := asset.MsgFinalize{
-			Sender:  sdk.AccAddress(info.GetPubKey().Address()),
+			Sender:  sender,
 			AssetID: vars["id"],
 		}
 		signAndBuild(ctx, cdc, w, m.BaseReq, msg)
@@ -149,7 +149,7 @@ func createAssetHandlerFn(ctx context.CLIContext, cdc *wire.Codec, kb keys.Keyba
 			return err
 		}
 
-		info, err := kb.Get(m.BaseReq.Name)
+		sender, err := m.BaseReq.sender(kb)
 		if err != nil {
 			return err
 		}
@@ -160,8 +160,9 @@ func createAssetHandlerFn(ctx context.CLIContext, cdc *wire.Codec, kb keys.Keyba
 			Name:       m.Name,
 			Parent:     m.Parent,
 			Properties: m.Properties,
-			Sender:     sdk.AccAddress(info.GetPubKey().Address()),
+			Sender:     sender,
 			Quantity:   m.Quantity,
+			Owner:      m.Owner,
 		}
 		signAndBuild(ctx, cdc, w, m.BaseReq, msg)
 		return nil
@@ -176,7 +177,7 @@ func revokeReporterHandlerFn(ctx context.CLIContext, cdc *wire.Codec, kb keys.Ke
 			return err
 		}
 
-		info, err := kb.Get(m.BaseReq.Name)
+		sender, err := m.BaseReq.sender(kb)
 		if err != nil {
 			return err
 		}
@@ -189,7 +190,7 @@ func revokeReporterHandlerFn(ctx context.CLIContext, cdc *wire.Codec, kb keys.Ke
 		// build message
 
 		msg := asset.MsgRevokeReporter{
-			Sender:   sdk.AccAddress(info.GetPubKey().Address()),
+			Sender:   sender,
 			Reporter: address,
 			AssetID:  vars["id"],
 		}
@@ -206,14 +207,14 @@ func subtractQuantityBodyHandlerFn(ctx context.CLIContext, cdc *wire.Codec, kb k
 			return err
 		}
 
-		info, err := kb.Get(m.BaseReq.Name)
+		sender, err := m.BaseReq.sender(kb)
 		if err != nil {
 			return err
 		}
 		// build message
 
 		msg := asset.MsgSubtractQuantity{
-			Sender:   sdk.AccAddress(info.GetPubKey().Address()),
+			Sender:   sender,
 			AssetID:  vars["id"],
 			Quantity: m.Quantity,
 		}
@@ -231,7 +232,7 @@ func updateAttributeHandlerFn(ctx context.CLIContext, cdc *wire.Codec, kb keys.K
 			return err
 		}
 
-		info, err := kb.Get(m.BaseReq.Name)
+		sender, err := m.BaseReq.sender(kb)
 		if err != nil {
 			return err
 		}
@@ -240,7 +241,7 @@ func updateAttributeHandlerFn(ctx context.CLIContext, cdc *wire.Codec, kb keys.K
 		msg := asset.MsgUpdateProperties{
 			AssetID:    vars["id"],
 			Properties: m.Properties,
-			Sender:     sdk.AccAddress(info.GetPubKey().Address()),
+			Sender:     sender,
 		}
 
 		signAndBuild(ctx, cdc, w, m.BaseReq, msg)
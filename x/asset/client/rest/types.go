@@ -3,7 +3,9 @@ package rest
 import (
 	"errors"
 
+	"github.com/cosmos/cosmos-sdk/crypto/keys"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/icheckteam/ichain/client/tx"
 	"github.com/icheckteam/ichain/x/asset"
 )
 
@@ -15,14 +17,31 @@ type baseBody struct {
 	AccountNumber int64  `json:"account_number"`
 	Gas           int64  `json:"gas"`
 	Memo          string `json:"memo"`
+
+	// GenerateOnly returns the unsigned tx instead of signing it with the
+	// keybase of the LCD, From is then the address of the signer
+	GenerateOnly bool   `json:"generate_only"`
+	From         string `json:"from"`
+
+	// Mode is the broadcast mode, sync, async or block by default
+	Mode string `json:"mode"`
 }
 
 func (b baseBody) Validate() error {
-	if b.Name == "" {
-		return errors.New("name required but not specified")
-	}
-	if b.Password == "" {
-		return errors.New("password required but not specified")
+	if b.GenerateOnly {
+		if b.From == "" && b.Name == "" {
+			return errors.New("from required but not specified")
+		}
+	} else {
+		if b.Name == "" {
+			return errors.New("name required but not specified")
+		}
+		if b.Password == "" {
+			return errors.New("password required but not specified")
+		}
+	}
+	if err := tx.ValidateBroadcastMode(b.Mode); err != nil {
+		return err
 	}
 	if b.Gas == 0 {
 		return errors.New("gas required but not specified")
@@ -40,6 +59,19 @@ func (b baseBody) Validate() error {
 	return nil
 }
 
+// sender is the address signing the tx, the key Name of the LCD keybase
+// or From in generate_only mode
+func (b baseBody) sender(kb keys.Keybase) (sdk.AccAddress, error) {
+	if b.GenerateOnly && b.From != "" {
+		return sdk.AccAddressFromBech32(b.From)
+	}
+	info, err := kb.Get(b.Name)
+	if err != nil {
+		return nil, err
+	}
+	return sdk.AccAddress(info.GetPubKey().Address()), nil
+}
+
 type msgCreateCreateProposalBody struct {
 	BaseReq baseBody `json:"base_req"`
 
@@ -141,6 +173,7 @@ type createAssetBody struct {
 	Parent     string           `json:"parent"`
 	Unit       string           `json:"unit"`
 	Properties asset.Properties `json:"properties"`
+	Owner      sdk.AccAddress   `json:"owner"` // optional identity owning the asset
 }
 
 func (b createAssetBody) ValidateBasic() error {
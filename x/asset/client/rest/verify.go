@@ -0,0 +1,149 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/cosmos/cosmos-sdk/wire"
+
+	assetclient "github.com/icheckteam/ichain/x/asset/client"
+)
+
+// Cache lifetimes of the passports, one pinned to a height never changes
+const (
+	latestPassportMaxAge = 5
+	pinnedPassportMaxAge = 365 * 24 * 3600
+)
+
+var passportTemplate = template.Must(template.New("passport").Funcs(template.FuncMap{
+	"date": func(t int64) string { return time.Unix(t, 0).UTC().Format("2006-01-02") },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Name}}</title>
+</head>
+<body>
+<h1>{{.Name}}</h1>
+{{if .Recall.Recalled}}<p><strong>Recalled{{if .Recall.Reason}}: {{.Recall.Reason}}{{end}}</strong></p>{{end}}
+{{if .Expiry.Expired}}<p><strong>Expired on {{date .Expiry.ExpiresAt}}</strong></p>{{else if .Expiry.ExpiresAt}}<p>Best before {{date .Expiry.ExpiresAt}}</p>{{end}}
+<p>{{if .Brand.Name}}{{.Brand.Name}}{{else}}{{.Brand.Address}}{{end}}{{if .Brand.Country}} ({{.Brand.Country}}){{end}}{{if .Brand.Website}} <a href="{{.Brand.Website}}">{{.Brand.Website}}</a>{{end}}</p>
+{{if .Barcode}}<p>Barcode {{.Barcode}}</p>{{end}}
+{{if .Properties}}<h2>Details</h2>
+<dl>{{range .Properties}}
+<dt>{{.Name}}</dt><dd>{{.Value}}</dd>{{end}}
+</dl>{{end}}
+<h2>Certificates</h2>
+<p>{{.Certificates.Valid}} valid</p>
+<ul>{{range .Certificates.Certs}}
+<li>{{.Property}}{{if .CertifierName}} by {{.CertifierName}}{{end}}: {{.Status}}</li>{{end}}
+</ul>
+<h2>Origin</h2>
+<ol>{{range .Origin}}
+<li>{{.Name}}{{if .OwnerName}}, {{.OwnerName}}{{end}}, {{date .Created}}</li>{{end}}
+</ol>
+<p><small>Block {{.Height}}, {{date .Time}}</small></p>
+</body>
+</html>
+`))
+
+// verifyAssetHandlerFn returns the passport of an asset, as JSON or as HTML
+// for the browsers following the QR codes. The passport is built at the
+// height param or at the last block, either way the response is pinned to
+// one height so it can be cached by it.
+func verifyAssetHandlerFn(ctx context.CLIContext, cdc *wire.Codec) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		format, err := passportFormat(r)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		latest, err := assetclient.LatestHeight(ctx)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		height, maxAge := latest, latestPassportMaxAge
+		if s := r.URL.Query().Get("height"); s != "" {
+			height, err = strconv.ParseInt(s, 10, 64)
+			if err != nil || height < 1 {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(fmt.Sprintf("invalid height %s", s)))
+				return
+			}
+			if height > latest {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(fmt.Sprintf("height %d is after the last block %d", height, latest)))
+				return
+			}
+			maxAge = pinnedPassportMaxAge
+		}
+
+		etag := fmt.Sprintf(`"%s-%d-%s"`, vars["id"], height, format)
+		w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", maxAge))
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Vary", "Accept")
+		w.Header().Set("X-Ichain-Height", strconv.FormatInt(height, 10))
+		w.Header().Set("Content-Location", fmt.Sprintf("/verify/%s?height=%d&format=%s", vars["id"], height, format))
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		passport, err := assetclient.GetPassport(ctx, vars["id"], cdc, height)
+		if err != nil {
+			w.Header().Del("Cache-Control")
+			w.Header().Del("ETag")
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(fmt.Sprintf("Couldn't verify asset. Error: %s", err.Error())))
+			return
+		}
+
+		var buf bytes.Buffer
+		if format == "html" {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			err = passportTemplate.Execute(&buf, passport)
+		} else {
+			w.Header().Set("Content-Type", "application/json")
+			err = json.NewEncoder(&buf).Encode(passport)
+		}
+		if err != nil {
+			w.Header().Del("Cache-Control")
+			w.Header().Del("ETag")
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		w.Write(buf.Bytes())
+	}
+}
+
+// passportFormat reads the format param, then prefers HTML when the client
+// accepts it
+func passportFormat(r *http.Request) (string, error) {
+	switch format := r.URL.Query().Get("format"); format {
+	case "json", "html":
+		return format, nil
+	case "":
+		if strings.Contains(r.Header.Get("Accept"), "text/html") {
+			return "html", nil
+		}
+		return "json", nil
+	default:
+		return "", fmt.Errorf("invalid format %s", format)
+	}
+}
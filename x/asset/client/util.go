@@ -1 +1,308 @@
 package client
+
+import (
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+
+	"github.com/icheckteam/ichain/client/tx"
+	"github.com/icheckteam/ichain/x/asset"
+	identityclient "github.com/icheckteam/ichain/x/identity/client"
+)
+
+const storeName = "asset"
+
+// GetReporters ...
+func GetReporters(ctx context.CLIContext, recordID string, cdc *wire.Codec) ([]asset.Reporter, error) {
+	reportersPrefixKey := asset.GetReportersKey(recordID)
+	kvs, err := ctx.QuerySubspace(reportersPrefixKey, storeName)
+	if err != nil {
+		return nil, err
+	}
+	reporters := make([]asset.Reporter, len(kvs))
+	for i, kv := range kvs {
+		reporter, err := asset.UnmarshalReporter(cdc, kv.Value)
+		if err != nil {
+			return nil, err
+		}
+		reporters[i] = reporter
+	}
+	return reporters, nil
+}
+
+// GetProperties ...
+func GetProperties(ctx context.CLIContext, recordID string, cdc *wire.Codec) ([]asset.Property, error) {
+	propertiesPrefixKey := asset.GetPropertiesKey(recordID)
+	kvs, err := ctx.QuerySubspace(propertiesPrefixKey, storeName)
+	if err != nil {
+		return nil, err
+	}
+	properties := make([]asset.Property, len(kvs))
+	for i, kv := range kvs {
+		property, err := asset.UnmarshalProperty(cdc, kv.Value)
+		if err != nil {
+			return nil, err
+		}
+		properties[i] = property
+	}
+	return properties, nil
+}
+
+// GetMaterials ...
+func GetMaterials(ctx context.CLIContext, recordID string, cdc *wire.Codec) ([]asset.Material, error) {
+	materialsPrefixKey := asset.GetMaterialsKey(recordID)
+	kvs, err := ctx.QuerySubspace(materialsPrefixKey, storeName)
+	if err != nil {
+		return nil, err
+	}
+	materials := make([]asset.Material, len(kvs))
+	for i, kv := range kvs {
+		material, err := asset.UnmarshalMaterial(cdc, kv.Value)
+		if err != nil {
+			return nil, err
+		}
+		materials[i] = material
+	}
+	return materials, nil
+}
+
+func withMoreRecord(ctx context.CLIContext, record asset.Asset, cdc *wire.Codec, includes ...string) (*asset.RecordOutput, error) {
+	recordOutput := asset.RecordOutput{
+		ID:       record.ID,
+		Name:     record.Name,
+		Owner:    record.Owner,
+		Parent:   record.Parent,
+		Root:     record.Root,
+		Final:    record.Final,
+		Quantity: record.Quantity,
+		Height:   record.Height,
+		Created:  record.Created,
+	}
+
+	// defaults
+	if len(includes) == 0 {
+		includes = []string{
+			"properties", "materials", "reporters",
+		}
+	}
+
+	for _, include := range includes {
+		switch include {
+		case "properties":
+			// query all properties of this record
+			properties, err := GetProperties(ctx, record.ID, cdc)
+			if err != nil {
+				return nil, err
+			}
+			recordOutput.Properties = properties
+			break
+		case "materials":
+			// query all materials of this record
+			materials, err := GetMaterials(ctx, record.ID, cdc)
+			if err != nil {
+				return nil, err
+			}
+			recordOutput.Materials = materials
+			break
+		case "reporters":
+			// query all reporters of this record
+			reporters, err := GetReporters(ctx, record.ID, cdc)
+			if err != nil {
+				return nil, err
+			}
+			recordOutput.Reporters = reporters
+			break
+		}
+	}
+
+	addrs := []sdk.AccAddress{record.Owner}
+	for _, reporter := range recordOutput.Reporters {
+		addrs = append(addrs, reporter.Addr)
+	}
+	profiles, err := identityclient.QueryProfiles(ctx, cdc, addrs...)
+	if err != nil {
+		return nil, err
+	}
+	recordOutput.Profiles = profiles
+	return &recordOutput, nil
+}
+
+// GetRecord query an asset with its properties, materials and reporters
+func GetRecord(ctx context.CLIContext, recordID string, cdc *wire.Codec, includes ...string) (*asset.RecordOutput, error) {
+	recordKey := asset.GetAssetKey(recordID)
+	res, err := ctx.QueryStore(recordKey, storeName)
+	if err != nil {
+		return nil, err
+	}
+
+	record, err := asset.UnmarshalRecord(cdc, res)
+	if err != nil {
+		return nil, err
+	}
+
+	recordOutput, err := withMoreRecord(ctx, record, cdc, includes...)
+	if err != nil {
+		return nil, err
+	}
+	// get rppot asset info
+	props := recordOutput.Properties
+	if recordOutput.Root != "" {
+		root, err := GetRecord(ctx, recordOutput.Root, cdc, "properties")
+		if err != nil {
+			return nil, err
+		}
+		props = root.Properties
+	}
+	formatRecordProperties(recordOutput, props)
+	return recordOutput, nil
+}
+
+// GetAccountRecords returns the assets owned by an address
+func GetAccountRecords(ctx context.CLIContext, addr sdk.AccAddress, cdc *wire.Codec) (asset.RecordsOutput, error) {
+	prefix := asset.GetAccountAssetsKey(addr)
+	kvs, err := ctx.QuerySubspace(prefix, storeName)
+	if err != nil {
+		return nil, err
+	}
+	return getRecordsByKvs(ctx, recordIDs(prefix, kvs), cdc)
+}
+
+// GetReporterRecords returns the assets an address reports on
+func GetReporterRecords(ctx context.CLIContext, addr sdk.AccAddress, cdc *wire.Codec) (asset.RecordsOutput, error) {
+	prefix := asset.GetReporterAssetsKey(addr)
+	kvs, err := ctx.QuerySubspace(prefix, storeName)
+	if err != nil {
+		return nil, err
+	}
+	return getRecordsByKvs(ctx, recordIDs(prefix, kvs), cdc)
+}
+
+// GetChildRecords returns the assets split from an asset
+func GetChildRecords(ctx context.CLIContext, assetID string, cdc *wire.Codec) (asset.RecordsOutput, error) {
+	prefix := asset.GetAssetChildrensKey(assetID)
+	kvs, err := ctx.QuerySubspace(prefix, storeName)
+	if err != nil {
+		return nil, err
+	}
+	return getRecordsByKvs(ctx, recordIDs(prefix, kvs), cdc)
+}
+
+// getRecordsByKvs loads the assets of an index
+func getRecordsByKvs(ctx context.CLIContext, ids []string, cdc *wire.Codec) (asset.RecordsOutput, error) {
+	records := make(asset.RecordsOutput, len(ids))
+	for i, recordID := range ids {
+		record, err := GetRecord(ctx, recordID, cdc)
+		if err != nil {
+			return nil, err
+		}
+		records[i] = *record
+	}
+	return records, nil
+}
+
+// recordIDs returns the asset ids of the keys of an index, each key is the
+// prefix of the index followed by the asset id
+func recordIDs(prefix []byte, kvs []sdk.KVPair) []string {
+	ids := make([]string, len(kvs))
+	for i, kv := range kvs {
+		ids[i] = string(kv.Key[len(prefix):])
+	}
+	return ids
+}
+
+// GetProposals returns the proposals of an asset
+func GetProposals(ctx context.CLIContext, assetID string, cdc *wire.Codec) (asset.Proposals, error) {
+	kvs, err := ctx.QuerySubspace(asset.GetProposalsKey(assetID), storeName)
+	if err != nil {
+		return nil, err
+	}
+	proposals := make(asset.Proposals, len(kvs))
+	for i, kv := range kvs {
+		proposal, err := asset.UnmarshalProposal(cdc, kv.Value)
+		if err != nil {
+			return nil, err
+		}
+		proposals[i] = proposal
+	}
+	return proposals, nil
+}
+
+func formatRecordProperties(record *asset.RecordOutput, props asset.Properties) {
+	for _, p := range props {
+		switch p.Name {
+		case "barcode":
+			record.Barcode = p.StringValue
+			break
+		case "unit":
+			record.Barcode = p.StringValue
+			break
+		case "type":
+			record.Type = p.StringValue
+			break
+		case "subtype":
+			record.SubType = p.StringValue
+			break
+		default:
+			break
+		}
+	}
+}
+
+// QueryAssetTxs query all transactions of the asset and of its parents until it was split
+func QueryAssetTxs(ctx context.CLIContext, assetID string, cdc *wire.Codec, height int64) ([]tx.TxInfo, error) {
+	record, err := GetRecord(ctx, assetID, cdc)
+	if err != nil {
+		return nil, err
+	}
+	node, err := ctx.GetNode()
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf("asset_id='%s'", record.ID)
+	page := 0
+	perPage := 500
+	prove := false
+	res, err := node.TxSearch(query, prove, page, perPage)
+	if err != nil {
+		return nil, err
+	}
+	info, err := tx.FormatTxResults(cdc, res.Txs)
+	if err != nil {
+		return nil, err
+	}
+
+	if height > 0 {
+		info = filterByHeight(info, height)
+	}
+
+	// load tx from parents ....
+	if record.Parent != "" {
+		txs, err := QueryAssetTxs(ctx, record.Parent, cdc, record.Height)
+		if err != nil {
+			return nil, err
+		}
+		info = append(info, txs...)
+	}
+
+	// get block time
+	if err := tx.SetTimes(ctx, info); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+func filterByHeight(infos []tx.TxInfo, height int64) []tx.TxInfo {
+	newInfos := make([]tx.TxInfo, len(infos))
+	var index = 0
+	for _, info := range infos {
+		if info.Height > height {
+			continue
+		}
+		newInfos[index] = info
+		index++
+	}
+	return newInfos[:index]
+}
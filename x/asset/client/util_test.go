@@ -0,0 +1,34 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/icheckteam/ichain/x/asset"
+)
+
+func TestRecordIDs(t *testing.T) {
+	addr := sdk.AccAddress([]byte("owner"))
+
+	prefix := asset.GetAccountAssetsKey(addr)
+	kvs := []sdk.KVPair{
+		{Key: asset.GetAccountAssetKey(addr, "a1")},
+		{Key: asset.GetAccountAssetKey(addr, "a2")},
+	}
+	require.Equal(t, []string{"a1", "a2"}, recordIDs(prefix, kvs))
+
+	prefix = asset.GetReporterAssetsKey(addr)
+	kvs = []sdk.KVPair{{Key: asset.GetReporterAssetKey(addr, "a1")}}
+	require.Equal(t, []string{"a1"}, recordIDs(prefix, kvs))
+
+	// the children keys hold the parent id, not an address
+	prefix = asset.GetAssetChildrensKey("parent")
+	kvs = []sdk.KVPair{
+		{Key: asset.GetAssetChildrenKey("parent", "child1")},
+		{Key: asset.GetAssetChildrenKey("parent", "c2")},
+	}
+	require.Equal(t, []string{"child1", "c2"}, recordIDs(prefix, kvs))
+}
@@ -0,0 +1,189 @@
+package asset
+
+import (
+	"encoding/base32"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+)
+
+// Size limits of the document fields
+const (
+	MaxDocumentTitleLength     = 256
+	MaxDocumentMediaTypeLength = 128
+)
+
+// multihash and CID codes of the accepted content hashes
+const (
+	multihashSHA256 = 0x12
+	cidV1           = 0x01
+	cidCodecRaw     = 0x55
+)
+
+// Document is an off-chain document anchored to an asset by its content hash
+type Document struct {
+	AssetID   string         `json:"asset_id"`
+	Hash      string         `json:"hash"`   // as submitted, hex SHA-256, multihash or CID
+	SHA256    string         `json:"sha256"` // hex SHA-256 of the content
+	MediaType string         `json:"media_type"`
+	Size      int64          `json:"size"`
+	Title     string         `json:"title"`
+	Reporter  sdk.AccAddress `json:"reporter"`
+	Created   int64          `json:"created"`
+	Height    int64          `json:"height"`
+}
+
+// ParseContentHash returns the SHA-256 digest of a content hash given as hex
+// SHA-256, as a base58 sha2-256 multihash (Qm...) or as a base32 CIDv1 of
+// raw content (b...). Other CIDs hash a DAG node instead of the file, so
+// they can't be verified against it and are rejected.
+func ParseContentHash(hash string) ([]byte, error) {
+	switch {
+	case len(hash) == 64:
+		digest, err := hex.DecodeString(hash)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hex sha256: %s", err)
+		}
+		return digest, nil
+	case strings.HasPrefix(hash, "Qm"):
+		bz, err := decodeBase58(hash)
+		if err != nil {
+			return nil, err
+		}
+		return sha256FromMultihash(bz)
+	case strings.HasPrefix(hash, "b"):
+		bz, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(strings.ToUpper(hash[1:]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid base32 cid: %s", err)
+		}
+		if len(bz) < 2 || bz[0] != cidV1 || bz[1] != cidCodecRaw {
+			return nil, fmt.Errorf("only CIDv1 of raw content are supported")
+		}
+		return sha256FromMultihash(bz[2:])
+	default:
+		return nil, fmt.Errorf("unknown content hash format")
+	}
+}
+
+func sha256FromMultihash(bz []byte) ([]byte, error) {
+	if len(bz) != 34 || bz[0] != multihashSHA256 || bz[1] != 32 {
+		return nil, fmt.Errorf("only sha2-256 multihashes are supported")
+	}
+	return bz[2:], nil
+}
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+func decodeBase58(s string) ([]byte, error) {
+	n := new(big.Int)
+	radix := big.NewInt(58)
+	for _, c := range s {
+		i := strings.IndexRune(base58Alphabet, c)
+		if i < 0 {
+			return nil, fmt.Errorf("invalid base58 character %q", c)
+		}
+		n.Mul(n, radix)
+		n.Add(n, big.NewInt(int64(i)))
+	}
+	// every leading 1 is a zero byte
+	zeros := 0
+	for zeros < len(s) && s[zeros] == '1' {
+		zeros++
+	}
+	return append(make([]byte, zeros), n.Bytes()...), nil
+}
+
+// AnchorDocument anchors a document to an asset, the owners and the
+// reporters of the asset can anchor documents
+func (k Keeper) AnchorDocument(ctx sdk.Context, msg MsgAnchorDocument) (sdk.Tags, sdk.Error) {
+	record, found := k.GetAsset(ctx, msg.AssetID)
+	if !found {
+		return nil, ErrAssetNotFound(msg.AssetID)
+	}
+	if record.Final {
+		return nil, ErrAssetAlreadyFinal(record.ID)
+	}
+	if !k.IsOwner(ctx, record, msg.Sender) {
+		if _, found := k.getReporterOf(ctx, record.ID, msg.Sender); !found {
+			return nil, sdk.ErrUnauthorized(fmt.Sprintf("%v not unauthorized to anchor", msg.Sender))
+		}
+	}
+	digest, err := ParseContentHash(msg.Hash)
+	if err != nil {
+		return nil, ErrInvalidField("hash")
+	}
+	if _, found := k.GetDocument(ctx, record.ID, digest); found {
+		return nil, ErrInvalidTransaction(fmt.Sprintf("document %s already anchored to {%s}", msg.Hash, record.ID))
+	}
+
+	doc := Document{
+		AssetID:   record.ID,
+		Hash:      msg.Hash,
+		SHA256:    hex.EncodeToString(digest),
+		MediaType: msg.MediaType,
+		Size:      msg.Size,
+		Title:     msg.Title,
+		Reporter:  msg.Sender,
+		Created:   ctx.BlockHeader().Time.Unix(),
+		Height:    ctx.BlockHeight(),
+	}
+	store := ctx.KVStore(k.storeKey)
+	store.Set(GetDocumentKey(record.ID, digest), k.cdc.MustMarshalBinary(doc))
+	store.Set(GetDocumentHashKey(digest, record.ID), []byte{})
+
+	tags := sdk.NewTags(
+		TagAsset, []byte(record.ID),
+		TagSender, []byte(msg.Sender.String()),
+	)
+	return tags, nil
+}
+
+// GetDocument ...
+func (k Keeper) GetDocument(ctx sdk.Context, assetID string, digest []byte) (doc Document, found bool) {
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(GetDocumentKey(assetID, digest))
+	if bz == nil {
+		return
+	}
+	k.cdc.MustUnmarshalBinary(bz, &doc)
+	return doc, true
+}
+
+// GetDocuments returns the documents anchored to an asset
+func (k Keeper) GetDocuments(ctx sdk.Context, assetID string) []Document {
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, GetDocumentsKey(assetID))
+	docs := []Document{}
+	for ; iterator.Valid(); iterator.Next() {
+		doc := Document{}
+		k.cdc.MustUnmarshalBinary(iterator.Value(), &doc)
+		docs = append(docs, doc)
+	}
+	iterator.Close()
+	return docs
+}
+
+// GetDocumentsByHash returns the anchors of a content on every asset
+func (k Keeper) GetDocumentsByHash(ctx sdk.Context, digest []byte) []Document {
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, GetDocumentHashesKey(digest))
+	docs := []Document{}
+	for ; iterator.Valid(); iterator.Next() {
+		doc, found := k.GetDocument(ctx, AssetIDFromDocumentHashKey(iterator.Key()), digest)
+		if found {
+			docs = append(docs, doc)
+		}
+	}
+	iterator.Close()
+	return docs
+}
+
+// UnmarshalDocument ...
+func UnmarshalDocument(cdc *wire.Codec, value []byte) (doc Document, err error) {
+	err = cdc.UnmarshalBinary(value, &doc)
+	return
+}
@@ -0,0 +1,78 @@
+package asset
+
+import (
+	"encoding/hex"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+)
+
+// sha256("hello") in the accepted formats
+const (
+	helloSHA256    = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+	helloMultihash = "QmRN6wdp1S2A5EtjW9A3M1vKSBuQQGcgvuhoMUoEz4iiT5"
+	helloCID       = "bafkreibm6jg3ux5qumhcn2b3flc3tyu6dmlb4xa7u5bf44yegnrjhc4yeq"
+)
+
+func TestParseContentHash(t *testing.T) {
+	for _, hash := range []string{helloSHA256, helloMultihash, helloCID} {
+		digest, err := ParseContentHash(hash)
+		require.Nil(t, err, hash)
+		assert.Equal(t, helloSHA256, hex.EncodeToString(digest), hash)
+	}
+
+	invalid := []string{
+		"",
+		helloSHA256[:62],
+		"zz" + helloSHA256[2:],
+		"Qm0000",
+		// CIDv1 of a dag-pb node
+		"bafybeibm6jg3ux5qumhcn2b3flc3tyu6dmlb4xa7u5bf44yegnrjhc4yeq",
+	}
+	for _, hash := range invalid {
+		_, err := ParseContentHash(hash)
+		assert.NotNil(t, err, hash)
+	}
+}
+
+func TestAnchorDocument(t *testing.T) {
+	ctx, _, keeper := createTestInput(t, false, 0)
+	ctx = ctx.WithBlockHeader(abci.Header{Time: time.Unix(100, 0)})
+	_, err := keeper.CreateAsset(ctx, MsgCreateAsset{AssetID: "lot1", Sender: addr, Name: "lot", Quantity: sdk.NewInt(1)})
+	require.Nil(t, err)
+
+	msg := MsgAnchorDocument{Sender: addr2, AssetID: "lot1", Hash: helloCID, MediaType: "application/pdf", Size: 5, Title: "lab report"}
+	require.Nil(t, msg.ValidateBasic())
+
+	// only the owner and the reporters
+	_, err = keeper.AnchorDocument(ctx, msg)
+	assert.NotNil(t, err)
+	msg.Sender = addr
+	_, err = keeper.AnchorDocument(ctx, msg)
+	require.Nil(t, err)
+
+	// the same content in another format is the same document
+	msg.Hash = helloSHA256
+	_, err = keeper.AnchorDocument(ctx, msg)
+	assert.NotNil(t, err)
+
+	docs := keeper.GetDocuments(ctx, "lot1")
+	require.Equal(t, 1, len(docs))
+	assert.Equal(t, helloCID, docs[0].Hash)
+	assert.Equal(t, helloSHA256, docs[0].SHA256)
+	assert.Equal(t, addr, docs[0].Reporter)
+	assert.Equal(t, int64(100), docs[0].Created)
+
+	digest, _ := hex.DecodeString(helloSHA256)
+	assert.Equal(t, 1, len(keeper.GetDocumentsByHash(ctx, digest)))
+	assert.Equal(t, 0, len(keeper.GetDocumentsByHash(ctx, make([]byte, 32))))
+
+	// the documents of an asset whose id is a prefix of another one
+	_, err = keeper.CreateAsset(ctx, MsgCreateAsset{AssetID: "lot", Sender: addr, Name: "lot", Quantity: sdk.NewInt(1)})
+	require.Nil(t, err)
+	assert.Empty(t, keeper.GetDocuments(ctx, "lot"))
+}
@@ -21,6 +21,7 @@ const (
 	CodeAssetAlreadyFinal     sdk.CodeType      = 509
 	CodeProposalNotFound      sdk.CodeType      = 510
 	CodeInvalidRole           sdk.CodeType      = 511
+	CodePolicyViolation       sdk.CodeType      = 512
 	DefaultCodespace          sdk.CodespaceType = 10
 )
 
@@ -69,3 +70,8 @@ func ErrInvalidTransaction(msg string) sdk.Error {
 func ErrProposalNotFound(recipient sdk.AccAddress) sdk.Error {
 	return sdk.NewError(DefaultCodespace, CodeProposalNotFound, fmt.Sprintf("proposal %s not found", recipient.String()))
 }
+
+// ErrPolicyViolation ...
+func ErrPolicyViolation(msg string) sdk.Error {
+	return sdk.NewError(DefaultCodespace, CodePolicyViolation, msg)
+}
@@ -0,0 +1,43 @@
+package asset
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// GenesisState - all asset state that must be provided at genesis
+type GenesisState struct {
+	Policies []Policy `json:"policies"`
+}
+
+// DefaultGenesisState returns a genesis without policies
+func DefaultGenesisState() GenesisState {
+	return GenesisState{
+		Policies: []Policy{},
+	}
+}
+
+// ValidateGenesis checks all policies
+func ValidateGenesis(data GenesisState) sdk.Error {
+	for _, p := range data.Policies {
+		if err := p.ValidateBasic(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InitGenesis sets the policies
+func InitGenesis(ctx sdk.Context, k Keeper, data GenesisState) sdk.Error {
+	if err := ValidateGenesis(data); err != nil {
+		return err
+	}
+	k.SetPolicies(ctx, data.Policies)
+	return nil
+}
+
+// WriteGenesis returns the policies in effect
+func WriteGenesis(ctx sdk.Context, k Keeper) GenesisState {
+	return GenesisState{
+		Policies: k.GetPolicies(ctx),
+	}
+}
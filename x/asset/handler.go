@@ -29,6 +29,8 @@ func NewHandler(k Keeper) sdk.Handler {
 			return handleCreateProposal(ctx, k, msg)
 		case MsgAnswerProposal:
 			return handleAnswerProposal(ctx, k, msg)
+		case MsgAnchorDocument:
+			return handleAnchorDocument(ctx, k, msg)
 		default:
 			errMsg := fmt.Sprintf("Unrecognized trace Msg type: %v", reflect.TypeOf(msg).Name())
 			return sdk.ErrUnknownRequest(errMsg).Result()
@@ -125,3 +127,13 @@ func handleRevokeReporter(ctx sdk.Context, k Keeper, msg MsgRevokeReporter) sdk.
 		Tags: tags,
 	}
 }
+
+func handleAnchorDocument(ctx sdk.Context, k Keeper, msg MsgAnchorDocument) sdk.Result {
+	tags, err := k.AnchorDocument(ctx, msg)
+	if err != nil {
+		return err.Result()
+	}
+	return sdk.Result{
+		Tags: tags,
+	}
+}
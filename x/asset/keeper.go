@@ -1,31 +1,54 @@
 package asset
 
 import (
+	"bytes"
 	"fmt"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/wire"
+	"github.com/cosmos/cosmos-sdk/x/params"
 )
 
 // Keeper ...
 type Keeper struct {
-	storeKey sdk.StoreKey // The (unexposed) key used to access the store from the Context.
-	cdc      *wire.Codec
+	storeKey       sdk.StoreKey // The (unexposed) key used to access the store from the Context.
+	cdc            *wire.Codec
+	ps             params.Setter  // holds the policies
+	identityKeeper IdentityKeeper // checks the certs required by the policies
 }
 
 // NewKeeper - Returns the Keeper
-func NewKeeper(key sdk.StoreKey, cdc *wire.Codec) Keeper {
+func NewKeeper(key sdk.StoreKey, cdc *wire.Codec, ps params.Setter, ik IdentityKeeper) Keeper {
 	return Keeper{
-		storeKey: key,
-		cdc:      cdc,
+		storeKey:       key,
+		cdc:            cdc,
+		ps:             ps,
+		identityKeeper: ik,
 	}
 }
 
+// IsOwner returns whether the address owns the asset, directly or as an
+// owner of the identity that owns it
+func (k Keeper) IsOwner(ctx sdk.Context, asset Asset, addr sdk.AccAddress) bool {
+	return asset.IsOwner(addr) || k.identityKeeper.HasOwner(ctx, asset.Owner, addr)
+}
+
 // CreateAsset create new an asset
 func (k Keeper) CreateAsset(ctx sdk.Context, msg MsgCreateAsset) (sdk.Tags, sdk.Error) {
 	if k.has(ctx, msg.AssetID) {
 		return nil, ErrInvalidTransaction(fmt.Sprintf("Asset {%s} already exists", msg.AssetID))
 	}
+	// the asset is owned by the sender or by an identity of the sender
+	owner := msg.Sender
+	if len(msg.Owner) > 0 && !bytes.Equal(msg.Owner, msg.Sender) {
+		if !k.identityKeeper.HasOwner(ctx, msg.Owner, msg.Sender) {
+			return nil, sdk.ErrUnauthorized(fmt.Sprintf("%v not an owner of %v", msg.Sender, msg.Owner))
+		}
+		owner = msg.Owner
+	}
+	if err := k.checkCreatePolicies(ctx, msg, owner); err != nil {
+		return nil, err
+	}
 
 	var parent Asset
 	var found bool
@@ -37,7 +60,7 @@ func (k Keeper) CreateAsset(ctx sdk.Context, msg MsgCreateAsset) (sdk.Tags, sdk.
 	newAsset := Asset{
 		ID:       msg.AssetID,
 		Name:     msg.Name,
-		Owner:    msg.Sender,
+		Owner:    owner,
 		Quantity: msg.Quantity,
 		Parent:   msg.Parent,
 		Final:    false,
@@ -55,7 +78,7 @@ func (k Keeper) CreateAsset(ctx sdk.Context, msg MsgCreateAsset) (sdk.Tags, sdk.
 			return nil, ErrAssetAlreadyFinal(parent.ID)
 		}
 
-		if !parent.IsOwner(msg.Sender) {
+		if !k.IsOwner(ctx, parent, msg.Sender) {
 			return nil, sdk.ErrUnauthorized(fmt.Sprintf("%v not unauthorized to revoke", msg.Sender))
 		}
 
@@ -151,7 +174,7 @@ func (k Keeper) AddQuantity(ctx sdk.Context, msg MsgAddQuantity) (sdk.Tags, sdk.
 		return nil, ErrAssetAlreadyFinal(asset.ID)
 	}
 
-	if asset.Root != "" || !asset.IsOwner(msg.Sender) {
+	if asset.Root != "" || !k.IsOwner(ctx, asset, msg.Sender) {
 		return nil, sdk.ErrUnauthorized(fmt.Sprintf("%v not unauthorized to add", msg.Sender))
 	}
 
@@ -179,7 +202,7 @@ func (k Keeper) SubtractQuantity(ctx sdk.Context, msg MsgSubtractQuantity) (sdk.
 		return nil, ErrAssetAlreadyFinal(asset.ID)
 	}
 
-	if !asset.IsOwner(msg.Sender) {
+	if !k.IsOwner(ctx, asset, msg.Sender) {
 		return nil, sdk.ErrUnauthorized(fmt.Sprintf("%v not unauthorized to revoke", msg.Sender))
 	}
 
@@ -201,7 +224,7 @@ func (k Keeper) Finalize(ctx sdk.Context, msg MsgFinalize) (sdk.Tags, sdk.Error)
 	if asset.Final {
 		return nil, ErrAssetAlreadyFinal(asset.ID)
 	}
-	if !asset.IsOwner(msg.Sender) {
+	if !k.IsOwner(ctx, asset, msg.Sender) {
 		return nil, sdk.ErrUnauthorized(fmt.Sprintf("%v not unauthorized to revoke", msg.Sender))
 	}
 	asset.Final = true
@@ -1,6 +1,10 @@
 package asset
 
-import sdk "github.com/cosmos/cosmos-sdk/types"
+import (
+	"encoding/binary"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
 
 // TODO remove some of these prefixes once have working multistore
 
@@ -18,6 +22,8 @@ var (
 	ReporterAssetsKey   = []byte{0x08}
 	ProposalsAccountKey = []byte{0x09}
 	MaterialsKey        = []byte{0x0A}
+	DocumentsKey        = []byte{0x0B} // prefix for each key to an asset a document
+	DocumentHashesKey   = []byte{0x0C} // prefix for each key to a content hash an asset
 )
 
 // GetAssetKey get the key for the record with address
@@ -104,3 +110,32 @@ func GetMaterialsKey(recordID string) []byte {
 func GetMaterialKey(recordID string, materialID string) []byte {
 	return append(GetMaterialsKey(recordID), []byte(materialID)...)
 }
+
+// GetDocumentsKey get the key for all documents of an asset, the id is
+// length prefixed so the documents of "lot1" are not under those of "lot"
+func GetDocumentsKey(assetID string) []byte {
+	prefix := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(prefix, uint64(len(assetID)))
+	key := append(append([]byte{}, DocumentsKey...), prefix[:n]...)
+	return append(key, []byte(assetID)...)
+}
+
+// GetDocumentKey get the key for a document of an asset by its sha256 digest
+func GetDocumentKey(assetID string, digest []byte) []byte {
+	return append(GetDocumentsKey(assetID), digest...)
+}
+
+// GetDocumentHashesKey get the key for all assets a content was anchored to
+func GetDocumentHashesKey(digest []byte) []byte {
+	return append(DocumentHashesKey, digest...)
+}
+
+// GetDocumentHashKey ...
+func GetDocumentHashKey(digest []byte, assetID string) []byte {
+	return append(GetDocumentHashesKey(digest), []byte(assetID)...)
+}
+
+// AssetIDFromDocumentHashKey returns the asset id of a GetDocumentHashKey key
+func AssetIDFromDocumentHashKey(key []byte) string {
+	return string(key[len(DocumentHashesKey)+32:])
+}
@@ -13,7 +13,7 @@ func (k Keeper) AddMaterials(ctx sdk.Context, msg MsgAddMaterials) (sdk.Tags, sd
 		return nil, ErrAssetNotFound(msg.AssetID)
 	}
 
-	if !asset.IsOwner(msg.Sender) {
+	if !k.IsOwner(ctx, asset, msg.Sender) {
 		return nil, sdk.ErrUnauthorized(fmt.Sprintf("%v not unauthorized to add", msg.Sender))
 	}
 
@@ -24,7 +24,7 @@ func (k Keeper) AddMaterials(ctx sdk.Context, msg MsgAddMaterials) (sdk.Tags, sd
 		if !found {
 			return nil, ErrAssetNotFound(amount.RecordID)
 		}
-		if !m.IsOwner(msg.Sender) {
+		if !k.IsOwner(ctx, m, msg.Sender) {
 			return nil, sdk.ErrUnauthorized(fmt.Sprintf("%v not unauthorized to add", msg.Sender))
 		}
 
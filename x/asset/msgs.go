@@ -1,6 +1,8 @@
 package asset
 
 import (
+	"strings"
+
 	sdk "github.com/cosmos/cosmos-sdk/types"
 )
 
@@ -9,6 +11,7 @@ const msgType = "asset"
 var _, _, _ sdk.Msg = &MsgCreateAsset{}, &MsgAddMaterials{}, &MsgAddQuantity{}
 var _, _, _ sdk.Msg = &MsgCreateProposal{}, &MsgRevokeReporter{}, &MsgFinalize{}
 var _, _, _ sdk.Msg = &MsgSubtractQuantity{}, &MsgAnswerProposal{}, &MsgUpdateProperties{}
+var _ sdk.Msg = &MsgAnchorDocument{}
 
 // MsgCreateAsset A really msg record create type, these fields are can be entirely arbitrary and
 // custom to your message
@@ -19,6 +22,7 @@ type MsgCreateAsset struct {
 	Quantity   sdk.Int        `json:"quantity"`
 	Parent     string         `json:"parent"` // the id of the  parent asset
 	Properties Properties     `json:"properties"`
+	Owner      sdk.AccAddress `json:"owner,omitempty"` // an identity of the sender owning the asset, the sender by default
 }
 
 // NewMsgCreateAsset new record create msg
@@ -381,3 +385,54 @@ func (msg MsgAnswerProposal) GetSignBytes() []byte {
 	}
 	return sdk.MustSortJSON(b)
 }
+
+// MsgAnchorDocument ...
+type MsgAnchorDocument struct {
+	Sender    sdk.AccAddress `json:"sender"`
+	AssetID   string         `json:"asset_id"`
+	Hash      string         `json:"hash"` // hex SHA-256, sha2-256 multihash or CIDv1 of raw content
+	MediaType string         `json:"media_type"`
+	Size      int64          `json:"size"`
+	Title     string         `json:"title"`
+}
+
+// Type ...
+func (msg MsgAnchorDocument) Type() string { return msgType }
+
+// GetSigners ...
+func (msg MsgAnchorDocument) GetSigners() []sdk.AccAddress { return []sdk.AccAddress{msg.Sender} }
+
+// ValidateBasic Validate Basic is used to quickly disqualify obviously invalid messages quickly
+func (msg MsgAnchorDocument) ValidateBasic() sdk.Error {
+	if len(msg.Sender) == 0 {
+		return sdk.ErrInvalidAddress(msg.Sender.String())
+	}
+	if len(msg.AssetID) == 0 {
+		return ErrMissingField("asset_id")
+	}
+	if len(msg.Hash) == 0 {
+		return ErrMissingField("hash")
+	}
+	if _, err := ParseContentHash(msg.Hash); err != nil {
+		return ErrInvalidField("hash")
+	}
+	if msg.Size < 0 {
+		return ErrInvalidField("size")
+	}
+	if len(msg.Title) > MaxDocumentTitleLength {
+		return ErrInvalidField("title")
+	}
+	if len(msg.MediaType) > MaxDocumentMediaTypeLength || (msg.MediaType != "" && !strings.Contains(msg.MediaType, "/")) {
+		return ErrInvalidField("media_type")
+	}
+	return nil
+}
+
+// GetSignBytes Get the bytes for the message signer to sign on
+func (msg MsgAnchorDocument) GetSignBytes() []byte {
+	b, err := msgCdc.MarshalJSON(msg)
+	if err != nil {
+		panic(err)
+	}
+	return sdk.MustSortJSON(b)
+}
@@ -0,0 +1,158 @@
+package asset
+
+import (
+	"fmt"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/icheckteam/ichain/x/identity"
+)
+
+// Actions gated by a policy
+const (
+	// PolicyCreate gates the creation of root assets of a type
+	PolicyCreate = "create"
+	// PolicyUpdateProperty gates setting properties, on creation and updates
+	PolicyUpdateProperty = "update_property"
+)
+
+// ParamStoreKeyPolicies is the key of the policies in the params store
+const ParamStoreKeyPolicies = "asset/policies"
+
+// IdentityKeeper is the part of the identity keeper the policies and the
+// identity owned assets consult
+type IdentityKeeper interface {
+	GetCert(ctx sdk.Context, addr sdk.AccAddress, property string, certifier sdk.AccAddress) (identity.Cert, bool)
+	HasOwner(ctx sdk.Context, id, owner sdk.AccAddress) bool
+}
+
+// Policy only lets the holders of a valid cert, issued by one of the
+// certifiers, perform an action. Target is the asset type for PolicyCreate
+// and the property name for PolicyUpdateProperty, a trailing * matches a
+// prefix so "lab.*" covers all lab properties.
+type Policy struct {
+	Action     string           `json:"action"`
+	Target     string           `json:"target"`
+	Cert       string           `json:"cert"`
+	Certifiers []sdk.AccAddress `json:"certifiers"`
+}
+
+// ValidateBasic quick validity check
+func (p Policy) ValidateBasic() sdk.Error {
+	if p.Action != PolicyCreate && p.Action != PolicyUpdateProperty {
+		return ErrInvalidField("policy.action")
+	}
+	if p.Target == "" {
+		return ErrMissingField("policy.target")
+	}
+	if p.Cert == "" {
+		return ErrMissingField("policy.cert")
+	}
+	if len(p.Certifiers) == 0 {
+		return ErrMissingField("policy.certifiers")
+	}
+	for _, certifier := range p.Certifiers {
+		if len(certifier) == 0 {
+			return ErrInvalidField("policy.certifiers")
+		}
+	}
+	return nil
+}
+
+func (p Policy) matches(action, target string) bool {
+	if p.Action != action {
+		return false
+	}
+	if strings.HasSuffix(p.Target, "*") {
+		return strings.HasPrefix(target, strings.TrimSuffix(p.Target, "*"))
+	}
+	return p.Target == target
+}
+
+// GetPolicies returns the policies, none when they were never set
+func (k Keeper) GetPolicies(ctx sdk.Context) []Policy {
+	var policies []Policy
+	if err := k.ps.Get(ctx, ParamStoreKeyPolicies, &policies); err != nil || policies == nil {
+		return []Policy{}
+	}
+	return policies
+}
+
+// SetPolicies replaces all policies
+func (k Keeper) SetPolicies(ctx sdk.Context, policies []Policy) {
+	if err := k.ps.Set(ctx, ParamStoreKeyPolicies, policies); err != nil {
+		panic(err)
+	}
+}
+
+// holdsCert returns whether the address holds a cert that satisfies the policy
+func (k Keeper) holdsCert(ctx sdk.Context, p Policy, addr sdk.AccAddress) bool {
+	now := ctx.BlockHeader().Time.Unix()
+	for _, certifier := range p.Certifiers {
+		cert, found := k.identityKeeper.GetCert(ctx, addr, p.Cert, certifier)
+		if found && cert.IsValidAt(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkPolicies checks the sender against every policy of the action and target
+func (k Keeper) checkPolicies(ctx sdk.Context, policies []Policy, sender sdk.AccAddress, action, target string) sdk.Error {
+	for _, p := range policies {
+		if p.matches(action, target) && !k.holdsCert(ctx, p, sender) {
+			return ErrPolicyViolation(fmt.Sprintf("%s requires a valid %s cert to %s %s", sender, p.Cert, action, target))
+		}
+	}
+	return nil
+}
+
+// checkCreatePolicies checks the owner of a new asset against the policies
+// of the asset and its properties, only root assets are gated by their type
+func (k Keeper) checkCreatePolicies(ctx sdk.Context, msg MsgCreateAsset, owner sdk.AccAddress) sdk.Error {
+	policies := k.GetPolicies(ctx)
+	if len(policies) == 0 {
+		return nil
+	}
+	if msg.Parent == "" {
+		if err := k.checkPolicies(ctx, policies, owner, PolicyCreate, assetType(msg.Properties)); err != nil {
+			return err
+		}
+	}
+	return k.checkPropertyPolicies(ctx, policies, owner, msg.Properties)
+}
+
+// checkTypePolicies checks the owner of a root asset against the create
+// policies of the type its properties set, so the type can't be given after
+// the creation to bypass them
+func (k Keeper) checkTypePolicies(ctx sdk.Context, policies []Policy, record Asset, props Properties) sdk.Error {
+	if record.Parent != "" {
+		return nil
+	}
+	for _, prop := range props {
+		if prop.Name == "type" {
+			return k.checkPolicies(ctx, policies, record.Owner, PolicyCreate, prop.StringValue)
+		}
+	}
+	return nil
+}
+
+func (k Keeper) checkPropertyPolicies(ctx sdk.Context, policies []Policy, sender sdk.AccAddress, props Properties) sdk.Error {
+	for _, prop := range props {
+		if err := k.checkPolicies(ctx, policies, sender, PolicyUpdateProperty, prop.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// assetType returns the value of the type property
+func assetType(props Properties) string {
+	for _, p := range props {
+		if p.Name == "type" {
+			return p.StringValue
+		}
+	}
+	return ""
+}
@@ -0,0 +1,125 @@
+package asset
+
+import (
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	"github.com/icheckteam/ichain/x/identity"
+)
+
+func TestPolicyValidateBasic(t *testing.T) {
+	assert.Nil(t, Policy{Action: PolicyCreate, Target: "food", Cert: "manufacturer", Certifiers: []sdk.AccAddress{addr3}}.ValidateBasic())
+	assert.NotNil(t, Policy{Action: "delete", Target: "food", Cert: "manufacturer", Certifiers: []sdk.AccAddress{addr3}}.ValidateBasic())
+	assert.NotNil(t, Policy{Action: PolicyCreate, Cert: "manufacturer", Certifiers: []sdk.AccAddress{addr3}}.ValidateBasic())
+	assert.NotNil(t, Policy{Action: PolicyCreate, Target: "food", Certifiers: []sdk.AccAddress{addr3}}.ValidateBasic())
+	assert.NotNil(t, Policy{Action: PolicyCreate, Target: "food", Cert: "manufacturer"}.ValidateBasic())
+}
+
+func TestPolicies(t *testing.T) {
+	ctx, _, keeper, identityKeeper := createTestInputWithIdentity(t, false, 0)
+	ctx = ctx.WithBlockHeader(abci.Header{Time: time.Unix(100, 0)})
+	assert.Equal(t, []Policy{}, keeper.GetPolicies(ctx))
+
+	require.Nil(t, InitGenesis(ctx, keeper, GenesisState{Policies: []Policy{
+		{Action: PolicyCreate, Target: "food", Cert: "manufacturer", Certifiers: []sdk.AccAddress{addr3}},
+		{Action: PolicyUpdateProperty, Target: "lab.*", Cert: "lab", Certifiers: []sdk.AccAddress{addr3}},
+	}}))
+	assert.Equal(t, 2, len(WriteGenesis(ctx, keeper).Policies))
+
+	food := MsgCreateAsset{
+		AssetID:    "food1",
+		Sender:     addr,
+		Name:       "food 1",
+		Quantity:   sdk.NewInt(100),
+		Properties: Properties{{Name: "type", Type: PropertyTypeString, StringValue: "food"}},
+	}
+	_, err := keeper.CreateAsset(ctx, food)
+	assert.NotNil(t, err)
+
+	// certs from other certifiers don't count
+	identityKeeper.Register(ctx, identity.MsgReg{Sender: addr4})
+	identityKeeper.AddCerts(ctx, identity.NewMsgSetCerts(addr4, addr4, []identity.CertValue{
+		{Property: "manufacturer", Owner: addr, Confidence: true},
+	}))
+	_, err = keeper.CreateAsset(ctx, food)
+	assert.NotNil(t, err)
+
+	identityKeeper.Register(ctx, identity.MsgReg{Sender: addr3})
+	_, err = identityKeeper.AddCerts(ctx, identity.NewMsgSetCerts(addr3, addr3, []identity.CertValue{
+		{Property: "manufacturer", Owner: addr, Confidence: true, ExpiresAt: 200},
+	}))
+	require.Nil(t, err)
+	_, err = keeper.CreateAsset(ctx, food)
+	require.Nil(t, err)
+
+	// other types and child assets are not gated
+	_, err = keeper.CreateAsset(ctx, MsgCreateAsset{AssetID: "toy1", Sender: addr2, Name: "toy", Quantity: sdk.NewInt(1)})
+	require.Nil(t, err)
+
+	// the type can't be set later to bypass the create policy
+	_, err = keeper.UpdateProperties(ctx, MsgUpdateProperties{Sender: addr2, AssetID: "toy1", Properties: food.Properties})
+	assert.NotNil(t, err)
+	_, err = keeper.CreateAsset(ctx, MsgCreateAsset{AssetID: "food0", Sender: addr, Name: "food 0", Quantity: sdk.NewInt(1)})
+	require.Nil(t, err)
+	_, err = keeper.UpdateProperties(ctx, MsgUpdateProperties{Sender: addr, AssetID: "food0", Properties: food.Properties})
+	require.Nil(t, err)
+
+	// expired cert
+	food.AssetID = "food2"
+	_, err = keeper.CreateAsset(ctx.WithBlockHeader(abci.Header{Time: time.Unix(200, 0)}), food)
+	assert.NotNil(t, err)
+
+	// lab properties, the owner needs the cert too
+	lab := Properties{{Name: "lab.protein", Type: PropertyTypeNumber, NumberValue: 12}}
+	_, err = keeper.UpdateProperties(ctx, MsgUpdateProperties{Sender: addr, AssetID: "food1", Properties: lab})
+	assert.NotNil(t, err)
+	_, err = keeper.UpdateProperties(ctx, MsgUpdateProperties{Sender: addr, AssetID: "food1", Properties: Properties{{Name: "color", Type: PropertyTypeString, StringValue: "red"}}})
+	require.Nil(t, err)
+
+	identityKeeper.AddCerts(ctx, identity.NewMsgSetCerts(addr3, addr3, []identity.CertValue{
+		{Property: "lab", Owner: addr, Confidence: true},
+	}))
+	_, err = keeper.UpdateProperties(ctx, MsgUpdateProperties{Sender: addr, AssetID: "food1", Properties: lab})
+	require.Nil(t, err)
+}
+
+func TestIdentityOwnedAsset(t *testing.T) {
+	ctx, _, keeper, identityKeeper := createTestInputWithIdentity(t, false, 0)
+	org := addrs[10]
+	identityKeeper.Register(ctx, identity.MsgReg{Sender: org})
+	identityKeeper.AddOwner(ctx, identity.MsgAddOwner{Ident: org, Sender: org, Owner: addr})
+	identityKeeper.DeleteOwner(ctx, identity.MsgDelOwner{Ident: org, Sender: addr, Owner: org})
+
+	// only the owners of the identity can create assets for it
+	msg := MsgCreateAsset{AssetID: "stock", Sender: addr2, Owner: org, Name: "stock", Quantity: sdk.NewInt(100)}
+	_, err := keeper.CreateAsset(ctx, msg)
+	assert.NotNil(t, err)
+	msg.Sender = addr
+	_, err = keeper.CreateAsset(ctx, msg)
+	require.Nil(t, err)
+	record, _ := keeper.GetAsset(ctx, "stock")
+	assert.Equal(t, org, record.Owner)
+	assert.True(t, keeper.IsOwner(ctx, record, addr))
+	assert.False(t, keeper.IsOwner(ctx, record, addr2))
+
+	// rotate the keys of the identity
+	_, err = identityKeeper.AddOwner(ctx, identity.MsgAddOwner{Sender: addr, Ident: org, Owner: addr2})
+	require.Nil(t, err)
+	_, err = identityKeeper.DeleteOwner(ctx, identity.MsgDelOwner{Sender: addr2, Ident: org, Owner: addr})
+	require.Nil(t, err)
+
+	props := Properties{{Name: "color", Type: PropertyTypeString, StringValue: "red"}}
+	_, err = keeper.UpdateProperties(ctx, MsgUpdateProperties{Sender: addr, AssetID: "stock", Properties: props})
+	assert.NotNil(t, err)
+	_, err = keeper.UpdateProperties(ctx, MsgUpdateProperties{Sender: addr2, AssetID: "stock", Properties: props})
+	require.Nil(t, err)
+	_, err = keeper.AddQuantity(ctx, MsgAddQuantity{Sender: addr2, AssetID: "stock", Quantity: sdk.NewInt(10)})
+	require.Nil(t, err)
+	_, err = keeper.Finalize(ctx, MsgFinalize{Sender: addr, AssetID: "stock"})
+	assert.NotNil(t, err)
+}
@@ -99,7 +99,7 @@ func (k Keeper) AddProposal(ctx sdk.Context, msg MsgCreateProposal) (sdk.Tags, s
 		return nil, ErrAssetNotFound(asset.ID)
 	}
 
-	if !asset.IsOwner(msg.Sender) {
+	if !k.IsOwner(ctx, asset, msg.Sender) {
 		return nil, sdk.ErrUnauthorized(fmt.Sprintf("%v not unauthorized to add", msg.Sender))
 	}
 
@@ -135,7 +135,7 @@ func (k Keeper) AnswerProposal(ctx sdk.Context, msg MsgAnswerProposal) (sdk.Tags
 	k.DeleteProposal(ctx, msg.AssetID, proposal.Recipient)
 	k.removeProposalAccountIndex(ctx, msg.Recipient, msg.AssetID)
 	asset, _ := k.GetAsset(ctx, msg.AssetID)
-	if !asset.IsOwner(proposal.Issuer) {
+	if !k.IsOwner(ctx, asset, proposal.Issuer) {
 		// Only delete the proposal
 		return nil, nil
 	}
@@ -25,7 +25,7 @@ func (k Keeper) RevokeReporter(ctx sdk.Context, msg MsgRevokeReporter) (sdk.Tags
 	if asset.Final {
 		return nil, ErrAssetAlreadyFinal(asset.ID)
 	}
-	if !asset.IsOwner(msg.Sender) {
+	if !k.IsOwner(ctx, asset, msg.Sender) {
 		return nil, sdk.ErrUnauthorized(fmt.Sprintf("%v not unauthorized to revoke", msg.Sender))
 	}
 
@@ -7,7 +7,6 @@ import (
 	"strconv"
 	"testing"
 
-	"github.com/icheckteam/ichain/types"
 	"github.com/stretchr/testify/require"
 
 	abci "github.com/tendermint/tendermint/abci/types"
@@ -21,6 +20,9 @@ import (
 	"github.com/cosmos/cosmos-sdk/wire"
 	"github.com/cosmos/cosmos-sdk/x/auth"
 	"github.com/cosmos/cosmos-sdk/x/bank"
+	"github.com/cosmos/cosmos-sdk/x/params"
+
+	"github.com/icheckteam/ichain/x/identity"
 )
 
 // dummy addresses used for testing
@@ -47,9 +49,9 @@ func makeTestCodec() *wire.Codec {
 	bank.RegisterWire(cdc)
 	RegisterWire(cdc)
 
-	// Register AppAccount
+	// Register BaseAccount, the app types import this package for the genesis
 	cdc.RegisterInterface((*auth.Account)(nil), nil)
-	cdc.RegisterConcrete(&types.AppAccount{}, "test/asset/Account", nil)
+	cdc.RegisterConcrete(&auth.BaseAccount{}, "test/asset/Account", nil)
 	wire.RegisterCrypto(cdc)
 
 	return cdc
@@ -57,12 +59,23 @@ func makeTestCodec() *wire.Codec {
 
 // hogpodge of all sorts of input required for testing
 func createTestInput(t *testing.T, isCheckTx bool, initCoins int64) (sdk.Context, auth.AccountMapper, Keeper) {
+	ctx, accountMapper, assetKeeper, _ := createTestInputWithIdentity(t, isCheckTx, initCoins)
+	return ctx, accountMapper, assetKeeper
+}
+
+// createTestInputWithIdentity also returns the identity keeper the asset
+// policies consult
+func createTestInputWithIdentity(t *testing.T, isCheckTx bool, initCoins int64) (sdk.Context, auth.AccountMapper, Keeper, identity.Keeper) {
 	db := dbm.NewMemDB()
 	keyAsset := sdk.NewKVStoreKey("asset")
 	keyMain := keyAsset //sdk.NewKVStoreKey("main") //TODO fix multistore
+	keyIdentity := sdk.NewKVStoreKey("identity")
+	keyParams := sdk.NewKVStoreKey("params")
 
 	ms := store.NewCommitMultiStore(db)
 	ms.MountStoreWithDB(keyAsset, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(keyIdentity, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(keyParams, sdk.StoreTypeIAVL, db)
 	err := ms.LoadLatestVersion()
 	require.Nil(t, err)
 
@@ -71,10 +84,12 @@ func createTestInput(t *testing.T, isCheckTx bool, initCoins int64) (sdk.Context
 	accountMapper := auth.NewAccountMapper(
 		cdc,                   // amino codec
 		keyMain,               // target store
-		types.ProtoAppAccount, // prototype
+		auth.ProtoBaseAccount, // prototype
 	)
-	assetKeeper := NewKeeper(keyAsset, cdc)
-	return ctx, accountMapper, assetKeeper
+	identityKeeper := identity.NewKeeper(keyIdentity, cdc)
+	paramsKeeper := params.NewKeeper(cdc, keyParams)
+	assetKeeper := NewKeeper(keyAsset, cdc, paramsKeeper.Setter(), identityKeeper)
+	return ctx, accountMapper, assetKeeper, identityKeeper
 }
 
 // NewPubKey ...
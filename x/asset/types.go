@@ -6,6 +6,8 @@ import (
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/wire"
+
+	"github.com/icheckteam/ichain/x/identity"
 )
 
 // Asset asset infomation
@@ -39,6 +41,9 @@ type RecordOutput struct {
 	Materials  []Material     `json:"materials"`
 	Reporters  []Reporter     `json:"reporters"`
 	Properties Properties     `json:"properties"`
+
+	// Profiles of the owner and the reporters that published one
+	Profiles []identity.Profile `json:"profiles"`
 }
 
 // RecordsOutput ...
@@ -109,7 +114,8 @@ func ToProposalOutput(proposal Proposal, assetID string) ProposalOutput {
 	}
 }
 
-// IsOwner check is owner of the asset
+// IsOwner check is owner of the asset, use Keeper.IsOwner to also accept
+// the owners of an identity owning the asset
 func (a Asset) IsOwner(addr sdk.AccAddress) bool {
 	return bytes.Equal(a.Owner, addr)
 }
@@ -11,13 +11,21 @@ func (k Keeper) ValidateUpdateProperties(ctx sdk.Context, record Asset, sender s
 	if record.Final {
 		return ErrAssetAlreadyFinal(record.ID)
 	}
-	if record.IsOwner(sender) {
-		return nil
+	policies := k.GetPolicies(ctx)
+	if err := k.checkTypePolicies(ctx, policies, record, properties); err != nil {
+		return err
 	}
-	reporter, found := k.GetReporter(ctx, record.ID, sender)
+	if k.IsOwner(ctx, record, sender) {
+		// the policies apply to the owner too, an identity owner acts for it
+		return k.checkPropertyPolicies(ctx, policies, record.Owner, properties)
+	}
+	reporter, found := k.getReporterOf(ctx, record.ID, sender)
 	if !found {
 		return sdk.ErrUnauthorized(fmt.Sprintf("%v not unauthorized", sender))
 	}
+	if err := k.checkPropertyPolicies(ctx, policies, reporter.Addr, properties); err != nil {
+		return err
+	}
 
 	// check role permissions
 	for _, attr := range properties {
@@ -30,6 +38,20 @@ func (k Keeper) ValidateUpdateProperties(ctx sdk.Context, record Asset, sender s
 	return nil
 }
 
+// getReporterOf returns the reporter the sender acts as, itself or an
+// identity it owns
+func (k Keeper) getReporterOf(ctx sdk.Context, recordID string, sender sdk.AccAddress) (Reporter, bool) {
+	if reporter, found := k.GetReporter(ctx, recordID, sender); found {
+		return reporter, true
+	}
+	for _, reporter := range k.GetReporters(ctx, recordID) {
+		if k.identityKeeper.HasOwner(ctx, reporter.Addr, sender) {
+			return reporter, true
+		}
+	}
+	return Reporter{}, false
+}
+
 // CheckUpdateAttributeAuthorization returns whether the address is authorized to update the attribute
 func (k Keeper) CheckUpdateAttributeAuthorization(ctx sdk.Context, record Asset, reporter Reporter, prop Property) bool {
 	attributeName := prop.Name
@@ -17,6 +17,7 @@ func RegisterWire(cdc *wire.Codec) {
 	cdc.RegisterConcrete(MsgCreateProposal{}, "asset/CreateProposal", nil)
 	cdc.RegisterConcrete(MsgAnswerProposal{}, "asset/AnswerProposal", nil)
 	cdc.RegisterConcrete(MsgRevokeReporter{}, "asset/RevokeReporter", nil)
+	cdc.RegisterConcrete(MsgAnchorDocument{}, "asset/AnchorDocument", nil)
 }
 
 func init() {
@@ -0,0 +1,221 @@
+package identity
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+)
+
+// PendingAction is a sensitive message of an identity waiting for the
+// approval of its owners, the proposer approves it implicitly
+type PendingAction struct {
+	ID        int64            `json:"id"`
+	Ident     sdk.AccAddress   `json:"ident"`
+	Msg       sdk.Msg          `json:"msg"`
+	Approvals []sdk.AccAddress `json:"approvals"`
+	CreatedAt int64            `json:"created_at"`
+}
+
+// HasApproval ...
+func (a PendingAction) HasApproval(owner sdk.AccAddress) bool {
+	for _, approval := range a.Approvals {
+		if bytes.Equal(approval, owner) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetThreshold returns the number of owner approvals required by the
+// sensitive actions of an identity, 0 if the identity never set one
+func (k Keeper) GetThreshold(ctx sdk.Context, id sdk.AccAddress) (threshold int64) {
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(KeyThreshold(id))
+	if bz == nil {
+		return 0
+	}
+	k.cdc.MustUnmarshalBinary(bz, &threshold)
+	return
+}
+
+func (k Keeper) setThreshold(ctx sdk.Context, id sdk.AccAddress, threshold int64) {
+	store := ctx.KVStore(k.storeKey)
+	store.Set(KeyThreshold(id), k.cdc.MustMarshalBinary(threshold))
+}
+
+// SetThreshold change the number of owner approvals of an identity
+func (k Keeper) SetThreshold(ctx sdk.Context, msg MsgSetThreshold) (sdk.Tags, sdk.Error) {
+	return k.authorize(ctx, msg.Ident, msg.Sender, msg)
+}
+
+// authorize executes a sensitive message of an identity, it becomes a
+// pending action when the identity requires more than one approval. The
+// message is tried on a cached context so an action that would fail is
+// rejected before it is proposed.
+func (k Keeper) authorize(ctx sdk.Context, ident, sender sdk.AccAddress, msg sdk.Msg) (sdk.Tags, sdk.Error) {
+	if !k.hasOwner(ctx, ident, sender) {
+		return nil, sdk.ErrUnauthorized(fmt.Sprintf("addr %s unauthorized", sender))
+	}
+	if k.GetThreshold(ctx, ident) <= 1 {
+		return k.execute(ctx, msg)
+	}
+	cacheCtx, _ := ctx.CacheContext()
+	if _, err := k.execute(cacheCtx, msg); err != nil {
+		return nil, err
+	}
+	action := PendingAction{
+		ID:        k.nextActionID(ctx, ident),
+		Ident:     ident,
+		Msg:       msg,
+		Approvals: []sdk.AccAddress{sender},
+		CreatedAt: ctx.BlockHeader().Time.Unix(),
+	}
+	k.setPendingAction(ctx, action)
+	return actionTags(action, ActionPending), nil
+}
+
+// execute applies a sensitive message once it is authorized
+func (k Keeper) execute(ctx sdk.Context, msg sdk.Msg) (sdk.Tags, sdk.Error) {
+	switch msg := msg.(type) {
+	case MsgAddOwner:
+		return k.addOwner(ctx, msg)
+	case MsgDelOwner:
+		return k.deleteOwner(ctx, msg)
+	case MsgSetCerts:
+		return k.addCerts(ctx, msg)
+	case MsgRevokeCerts:
+		return k.revokeCerts(ctx, msg)
+	case MsgAddService:
+		return k.addService(ctx, msg)
+	case MsgDelService:
+		return k.deleteService(ctx, msg)
+	case MsgSetProfile:
+		return k.setProfile(ctx, msg)
+	case MsgSetThreshold:
+		return k.executeSetThreshold(ctx, msg)
+	case MsgSetGuardians:
+		return k.setGuardians(ctx, msg)
+	case MsgSetCertSchema:
+		return k.setCertSchema(ctx, msg)
+	default:
+		return nil, sdk.ErrUnknownRequest(fmt.Sprintf("unsupported action %T", msg))
+	}
+}
+
+func (k Keeper) executeSetThreshold(ctx sdk.Context, msg MsgSetThreshold) (sdk.Tags, sdk.Error) {
+	if msg.Threshold > k.getOwnerCount(ctx, msg.Ident) {
+		return nil, ErrInvalidThreshold(k.codespace, msg.Threshold, k.getOwnerCount(ctx, msg.Ident))
+	}
+	k.setThreshold(ctx, msg.Ident, msg.Threshold)
+	return nil, nil
+}
+
+// ApproveAction adds the approval of an owner, the action is executed when
+// the approvals of the current owners reach the threshold
+func (k Keeper) ApproveAction(ctx sdk.Context, msg MsgApproveAction) (sdk.Tags, sdk.Error) {
+	if !k.hasOwner(ctx, msg.Ident, msg.Sender) {
+		return nil, sdk.ErrUnauthorized(fmt.Sprintf("addr %s unauthorized", msg.Sender))
+	}
+	action, found := k.GetPendingAction(ctx, msg.Ident, msg.ActionID)
+	if !found {
+		return nil, ErrActionNotFound(k.codespace, msg.Ident, msg.ActionID)
+	}
+	if action.HasApproval(msg.Sender) {
+		return nil, sdk.NewError(k.codespace, CodeInvalidInput, fmt.Sprintf("action %d already approved by %s", msg.ActionID, msg.Sender))
+	}
+	action.Approvals = append(action.Approvals, msg.Sender)
+
+	// the approvals of removed owners do not count
+	var approvals int64
+	for _, approval := range action.Approvals {
+		if k.hasOwner(ctx, msg.Ident, approval) {
+			approvals++
+		}
+	}
+	if approvals < k.GetThreshold(ctx, msg.Ident) {
+		k.setPendingAction(ctx, action)
+		return actionTags(action, ActionPending), nil
+	}
+
+	k.deletePendingAction(ctx, msg.Ident, msg.ActionID)
+	tags, err := k.execute(ctx, action.Msg)
+	if err != nil {
+		return nil, err
+	}
+	return actionTags(action, ActionExecuted).AppendTags(tags), nil
+}
+
+// CancelAction removes a pending action, any owner can cancel
+func (k Keeper) CancelAction(ctx sdk.Context, msg MsgCancelAction) (sdk.Tags, sdk.Error) {
+	if !k.hasOwner(ctx, msg.Ident, msg.Sender) {
+		return nil, sdk.ErrUnauthorized(fmt.Sprintf("addr %s unauthorized", msg.Sender))
+	}
+	if _, found := k.GetPendingAction(ctx, msg.Ident, msg.ActionID); !found {
+		return nil, ErrActionNotFound(k.codespace, msg.Ident, msg.ActionID)
+	}
+	k.deletePendingAction(ctx, msg.Ident, msg.ActionID)
+	return nil, nil
+}
+
+func actionTags(action PendingAction, outcome string) sdk.Tags {
+	return sdk.NewTags(
+		TagIdent, []byte(action.Ident.String()),
+		TagAction, []byte(outcome),
+		TagActionID, []byte(strconv.FormatInt(action.ID, 10)),
+	)
+}
+
+func (k Keeper) nextActionID(ctx sdk.Context, id sdk.AccAddress) (actionID int64) {
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(KeyActionSeq(id))
+	if bz != nil {
+		k.cdc.MustUnmarshalBinary(bz, &actionID)
+	}
+	actionID++
+	store.Set(KeyActionSeq(id), k.cdc.MustMarshalBinary(actionID))
+	return
+}
+
+func (k Keeper) setPendingAction(ctx sdk.Context, action PendingAction) {
+	store := ctx.KVStore(k.storeKey)
+	store.Set(KeyPendingAction(action.Ident, action.ID), k.cdc.MustMarshalBinary(action))
+}
+
+func (k Keeper) deletePendingAction(ctx sdk.Context, id sdk.AccAddress, actionID int64) {
+	store := ctx.KVStore(k.storeKey)
+	store.Delete(KeyPendingAction(id, actionID))
+}
+
+// GetPendingAction ...
+func (k Keeper) GetPendingAction(ctx sdk.Context, id sdk.AccAddress, actionID int64) (action PendingAction, found bool) {
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(KeyPendingAction(id, actionID))
+	if bz == nil {
+		return
+	}
+	k.cdc.MustUnmarshalBinary(bz, &action)
+	return action, true
+}
+
+// GetPendingActions returns the pending actions of an identity by id
+func (k Keeper) GetPendingActions(ctx sdk.Context, id sdk.AccAddress) []PendingAction {
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, KeyPendingActions(id))
+	actions := []PendingAction{}
+	for ; iterator.Valid(); iterator.Next() {
+		var action PendingAction
+		k.cdc.MustUnmarshalBinary(iterator.Value(), &action)
+		actions = append(actions, action)
+	}
+	iterator.Close()
+	return actions
+}
+
+// UnmarshalPendingAction ...
+func UnmarshalPendingAction(cdc *wire.Codec, value []byte) (action PendingAction, err error) {
+	err = cdc.UnmarshalBinary(value, &action)
+	return
+}
@@ -6,11 +6,13 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 )
 
-// setCert set the main record holding cert details
+// setCert set the main record holding cert details and index it by
+// certifier, certs are revoked but never deleted so the index only grows
 func (k Keeper) setCert(ctx sdk.Context, addr sdk.AccAddress, cert Cert) {
 	store := ctx.KVStore(k.storeKey)
 	bz := k.cdc.MustMarshalBinary(cert)
 	store.Set(KeyCert(addr, cert.Property, cert.Certifier), bz)
+	store.Set(KeyIssuedCert(cert.Certifier, cert.Property, addr), addr.Bytes())
 }
 
 // GetCert  set the main record holding cert details
@@ -25,22 +27,48 @@ func (k Keeper) GetCert(ctx sdk.Context, addr sdk.AccAddress, property string, c
 	return
 }
 
-// deleteCert delete a cert from the store
-func (k Keeper) deleteCert(ctx sdk.Context, addr sdk.AccAddress, property string, certifier sdk.AccAddress) {
+// setRevocation add a revocation to the revocation list
+func (k Keeper) setRevocation(ctx sdk.Context, revocation Revocation) {
 	store := ctx.KVStore(k.storeKey)
-	store.Delete(KeyCert(addr, property, certifier))
+	bz := k.cdc.MustMarshalBinary(revocation)
+	store.Set(KeyRevocation(revocation.Owner, revocation.RevokedAt, revocation.Property, revocation.Certifier), bz)
 }
 
-// AddCerts add all certs
+// revokeCert marks the cert revoked and records the revocation
+func (k Keeper) revokeCert(ctx sdk.Context, cert Cert, reason string) {
+	cert.Revoked = true
+	cert.RevokedAt = ctx.BlockHeader().Time.Unix()
+	cert.RevokeReason = reason
+	k.setCert(ctx, cert.Owner, cert)
+	k.setRevocation(ctx, Revocation{
+		Owner:         cert.Owner,
+		Property:      cert.Property,
+		Certifier:     cert.Certifier,
+		Reason:        reason,
+		RevokedAt:     cert.RevokedAt,
+		CertCreatedAt: cert.CreatedAt,
+	})
+}
+
+// AddCerts add all certs, once the owners of the issuer approved them
 func (k Keeper) AddCerts(ctx sdk.Context, msg MsgSetCerts) (sdk.Tags, sdk.Error) {
-	// check owner to add certificate
-	if !k.hasOwner(ctx, msg.Issuer, msg.Sender) {
-		return nil, sdk.ErrUnauthorized(fmt.Sprintf("addr %s unauthorized to add", msg.Sender))
-	}
+	return k.authorize(ctx, msg.Issuer, msg.Sender, msg)
+}
 
+// addCerts keeps the validity of a cert at a past time unchanged: a revoked
+// cert isn't issued again under the same key and the expiry of a cert only
+// changes while both the old and the new one are in the future
+func (k Keeper) addCerts(ctx sdk.Context, msg MsgSetCerts) (sdk.Tags, sdk.Error) {
+	now := ctx.BlockHeader().Time.Unix()
 	for _, value := range msg.Values {
+		cert, found := k.GetCert(ctx, value.Owner, value.Property, msg.Issuer)
 		if value.Confidence == true {
-			cert, found := k.GetCert(ctx, value.Owner, value.Property, msg.Issuer)
+			if err := k.validateCertData(ctx, msg.Issuer, value); err != nil {
+				return nil, err
+			}
+			if found && cert.Revoked {
+				return nil, ErrCertRevoked(k.codespace, value.Owner, value.Property)
+			}
 			if !found {
 				// new cert
 				cert = Cert{
@@ -49,23 +77,58 @@ func (k Keeper) AddCerts(ctx sdk.Context, msg MsgSetCerts) (sdk.Tags, sdk.Error)
 					Certifier: msg.Issuer,
 					Data:      value.Data,
 					CreatedAt: ctx.BlockHeader().Time.Unix(),
+					ExpiresAt: value.ExpiresAt,
 				}
 			} else {
 				// update cert
+				if value.ExpiresAt != cert.ExpiresAt && (!cert.IsValidAt(now) || (value.ExpiresAt > 0 && value.ExpiresAt <= now)) {
+					return nil, sdk.NewError(k.codespace, CodeInvalidInput, fmt.Sprintf("the expiry of cert %s of %s can't change its validity in the past", value.Property, value.Owner))
+				}
 				cert.Data = value.Data
+				cert.ExpiresAt = value.ExpiresAt
 			}
 
 			// add cert
 			k.setCert(ctx, value.Owner, cert)
 
-		} else {
-			// delete cert
-			k.deleteCert(ctx, value.Owner, value.Property, msg.Issuer)
+		} else if found && !cert.Revoked {
+			// revoke cert without reason
+			k.revokeCert(ctx, cert, "")
 		}
 	}
 	return nil, nil
 }
 
+// RevokeCerts revoke the certs with a reason
+func (k Keeper) RevokeCerts(ctx sdk.Context, msg MsgRevokeCerts) (sdk.Tags, sdk.Error) {
+	return k.authorize(ctx, msg.Issuer, msg.Sender, msg)
+}
+
+func (k Keeper) revokeCerts(ctx sdk.Context, msg MsgRevokeCerts) (sdk.Tags, sdk.Error) {
+	for _, revocation := range msg.Revocations {
+		cert, found := k.GetCert(ctx, revocation.Owner, revocation.Property, msg.Issuer)
+		if !found || cert.Revoked {
+			return nil, ErrCertNotFound(k.codespace, revocation.Owner, revocation.Property)
+		}
+		k.revokeCert(ctx, cert, revocation.Reason)
+	}
+	return nil, nil
+}
+
+// GetRevocations returns the revocation list of the certs of an address
+func (k Keeper) GetRevocations(ctx sdk.Context, addr sdk.AccAddress) []Revocation {
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, KeyRevocations(addr))
+	revocations := []Revocation{}
+	for ; iterator.Valid(); iterator.Next() {
+		revocation := Revocation{}
+		k.cdc.MustUnmarshalBinary(iterator.Value(), &revocation)
+		revocations = append(revocations, revocation)
+	}
+	iterator.Close()
+	return revocations
+}
+
 // GetCerts ...
 func (k Keeper) GetCerts(ctx sdk.Context, id sdk.AccAddress) Certs {
 	store := ctx.KVStore(k.storeKey)
@@ -81,3 +144,25 @@ func (k Keeper) GetCerts(ctx sdk.Context, id sdk.AccAddress) Certs {
 	iterator.Close()
 	return certs
 }
+
+// GetIssuedCerts returns the certs issued by a certifier, sorted by
+// property then owner, an empty property returns all of them
+func (k Keeper) GetIssuedCerts(ctx sdk.Context, certifier sdk.AccAddress, property string) Certs {
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, KeyIssuedCerts(certifier, property))
+	certs := Certs{}
+	for ; iterator.Valid(); iterator.Next() {
+		owner := sdk.AccAddress(iterator.Value())
+		certProperty := PropertyFromIssuedCertKey(certifier, owner, iterator.Key())
+		if property != "" && certProperty != property {
+			// the prefix of a longer property
+			continue
+		}
+		cert, found := k.GetCert(ctx, owner, certProperty, certifier)
+		if found {
+			certs = append(certs, cert)
+		}
+	}
+	iterator.Close()
+	return certs
+}
@@ -0,0 +1,85 @@
+package client
+
+import (
+	"github.com/cosmos/cosmos-sdk/client/context"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+
+	"github.com/icheckteam/ichain/x/identity"
+)
+
+// QueryCerts returns all the certs of an address
+func QueryCerts(ctx context.CLIContext, cdc *wire.Codec, owner sdk.AccAddress) (identity.Certs, error) {
+	kvs, err := querySubspace(ctx, identity.KeyCerts(owner))
+	if err != nil {
+		return nil, err
+	}
+	certs := identity.Certs{}
+	for _, kv := range kvs {
+		cert, err := identity.UnmarshalCert(cdc, kv.Value)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+	return certs, nil
+}
+
+// IssuedCerts is a page of the certs issued by a certifier
+type IssuedCerts struct {
+	Total int            `json:"total"`
+	Page  int            `json:"page"`
+	Limit int            `json:"limit"`
+	Certs identity.Certs `json:"certs"`
+}
+
+// QueryIssuedCerts returns a page of the certs issued by a certifier, sorted
+// by property then owner. Only the certs of the page are queried, an empty
+// property returns all of them.
+func QueryIssuedCerts(ctx context.CLIContext, cdc *wire.Codec, certifier sdk.AccAddress, property string, page, limit int) (IssuedCerts, error) {
+	result := IssuedCerts{Page: page, Limit: limit, Certs: identity.Certs{}}
+	kvs, err := ctx.QuerySubspace(identity.KeyIssuedCerts(certifier, property), storeName)
+	if err != nil {
+		return result, err
+	}
+
+	type certKey struct {
+		owner    sdk.AccAddress
+		property string
+	}
+	keys := []certKey{}
+	for _, kv := range kvs {
+		owner := sdk.AccAddress(kv.Value)
+		certProperty := identity.PropertyFromIssuedCertKey(certifier, owner, kv.Key)
+		if property != "" && certProperty != property {
+			// the prefix of a longer property
+			continue
+		}
+		keys = append(keys, certKey{owner, certProperty})
+	}
+	result.Total = len(keys)
+
+	start := (page - 1) * limit
+	if start >= len(keys) {
+		return result, nil
+	}
+	end := start + limit
+	if end > len(keys) {
+		end = len(keys)
+	}
+	for _, key := range keys[start:end] {
+		res, err := ctx.QueryStore(identity.KeyCert(key.owner, key.property, certifier), storeName)
+		if err != nil {
+			return result, err
+		}
+		if len(res) == 0 {
+			continue
+		}
+		cert, err := identity.UnmarshalCert(cdc, res)
+		if err != nil {
+			return result, err
+		}
+		result.Certs = append(result.Certs, cert)
+	}
+	return result, nil
+}
@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/cosmos/cosmos-sdk/client/keys"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+
+	"github.com/icheckteam/ichain/x/identity"
+	identityclient "github.com/icheckteam/ichain/x/identity/client"
+)
+
+const (
+	flagProperty  = "property"
+	flagCertifier = "certifier"
+	flagSign      = "sign"
+)
+
+// GetCmdCredentials prints the certs of an address as W3C Verifiable Credentials
+func GetCmdCredentials(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "credentials [address]",
+		Short: "Export the certs of an address as W3C Verifiable Credentials",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCLIContext().WithCodec(cdc)
+			owner, err := sdk.AccAddressFromBech32(args[0])
+			if err != nil {
+				return err
+			}
+			var certifier sdk.AccAddress
+			if s := viper.GetString(flagCertifier); s != "" {
+				certifier, err = sdk.AccAddressFromBech32(s)
+				if err != nil {
+					return err
+				}
+			}
+
+			vcs, err := identityclient.QueryCredentials(ctx, cdc, owner, viper.GetString(flagProperty), certifier)
+			if err != nil {
+				return err
+			}
+
+			if viper.GetBool(flagSign) {
+				vcs, err = signCredentials(ctx, ctx.FromAddressName, vcs)
+				if err != nil {
+					return err
+				}
+			}
+
+			output, err := json.MarshalIndent(vcs, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(output))
+			return nil
+		},
+	}
+	cmd.Flags().String(flagProperty, "", "Only the certs of this property")
+	cmd.Flags().String(flagCertifier, "", "Only the certs issued by this certifier")
+	cmd.Flags().Bool(flagSign, false, fmt.Sprintf("Sign the credentials issued by the identities the key --%s owns", client.FlagName))
+	cmd.Flags().String(client.FlagName, "", "Name of the key of a certifier's owner to sign with")
+	return cmd
+}
+
+// signCredentials signs the credentials issued by the identities the key
+// is an owner of, the others are left out
+func signCredentials(ctx context.CLIContext, name string, vcs []identityclient.Credential) ([]identityclient.Credential, error) {
+	kb, err := keys.GetKeyBase()
+	if err != nil {
+		return nil, err
+	}
+	info, err := kb.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	signer := sdk.AccAddress(info.GetPubKey().Address())
+
+	passphrase, err := keys.GetPassphrase(name)
+	if err != nil {
+		return nil, err
+	}
+	owners := map[string][]sdk.AccAddress{}
+	signed := []identityclient.Credential{}
+	for _, vc := range vcs {
+		if _, found := owners[vc.Issuer]; !found {
+			issuer, err := identity.ParseDID(vc.Issuer)
+			if err != nil {
+				return nil, err
+			}
+			if owners[vc.Issuer], err = identityclient.QueryOwners(ctx, issuer); err != nil {
+				return nil, err
+			}
+		}
+		if !hasAddr(owners[vc.Issuer], signer) {
+			continue
+		}
+		vc, err = identityclient.SignCredential(kb, name, passphrase, vc, owners[vc.Issuer])
+		if err != nil {
+			return nil, err
+		}
+		signed = append(signed, vc)
+	}
+	return signed, nil
+}
+
+func hasAddr(addrs []sdk.AccAddress, addr sdk.AccAddress) bool {
+	for _, a := range addrs {
+		if bytes.Equal(a, addr) {
+			return true
+		}
+	}
+	return false
+}
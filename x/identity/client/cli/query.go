@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+
+	"github.com/icheckteam/ichain/x/identity"
+	identityclient "github.com/icheckteam/ichain/x/identity/client"
+)
+
+const flagValidAt = "valid-at"
+
+// GetCmdQueryOwners ...
+func GetCmdQueryOwners(cdc *wire.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "owners [ident]",
+		Short: "Query the owners of an identity",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCLIContext().WithCodec(cdc)
+			ident, err := sdk.AccAddressFromBech32(args[0])
+			if err != nil {
+				return err
+			}
+			owners, err := identityclient.QueryOwners(ctx, ident)
+			if err != nil {
+				return err
+			}
+			return printJSON(cdc, owners)
+		},
+	}
+}
+
+// GetCmdQueryCerts ...
+func GetCmdQueryCerts(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "certs [address]",
+		Short: "Query the certs of an address",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCLIContext().WithCodec(cdc)
+			owner, err := sdk.AccAddressFromBech32(args[0])
+			if err != nil {
+				return err
+			}
+			certs, err := identityclient.QueryCerts(ctx, cdc, owner)
+			if err != nil {
+				return err
+			}
+			if validAt := viper.GetInt64(flagValidAt); validAt > 0 {
+				valid := identity.Certs{}
+				for _, cert := range certs {
+					if cert.IsValidAt(validAt) {
+						valid = append(valid, cert)
+					}
+				}
+				certs = valid
+			}
+			return printJSON(cdc, certs)
+		},
+	}
+	cmd.Flags().Int64(flagValidAt, 0, "Only the certs valid at this unix time")
+	return cmd
+}
+
+// GetCmdQueryTrusts ...
+func GetCmdQueryTrusts(cdc *wire.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "trusts [address]",
+		Short: "Query the addresses trusted by an address",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCLIContext().WithCodec(cdc)
+			trustor, err := sdk.AccAddressFromBech32(args[0])
+			if err != nil {
+				return err
+			}
+			trusting, err := identityclient.QueryTrusting(ctx, trustor)
+			if err != nil {
+				return err
+			}
+			return printJSON(cdc, trusting)
+		},
+	}
+}
+
+func printJSON(cdc *wire.Codec, o interface{}) error {
+	output, err := wire.MarshalJSONIndent(cdc, o)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(output))
+	return nil
+}
@@ -0,0 +1,187 @@
+package cli
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/cosmos/cosmos-sdk/client/utils"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+	authcmd "github.com/cosmos/cosmos-sdk/x/auth/client/cli"
+	authctx "github.com/cosmos/cosmos-sdk/x/auth/client/context"
+
+	"github.com/icheckteam/ichain/x/identity"
+)
+
+const (
+	flagFile   = "file"
+	flagIssuer = "issuer"
+)
+
+// sendTx signs the msg with the --name key and broadcasts it
+func sendTx(cdc *wire.Codec, build func(from sdk.AccAddress) (sdk.Msg, error)) error {
+	txCtx := authctx.NewTxContextFromCLI().WithCodec(cdc)
+	cliCtx := context.NewCLIContext().
+		WithCodec(cdc).
+		WithLogger(os.Stdout).
+		WithAccountDecoder(authcmd.GetAccountDecoder(cdc))
+
+	from, err := cliCtx.GetFromAddress()
+	if err != nil {
+		return err
+	}
+	msg, err := build(from)
+	if err != nil {
+		return err
+	}
+	if err := msg.ValidateBasic(); err != nil {
+		return err
+	}
+	return utils.SendTx(txCtx, cliCtx, []sdk.Msg{msg})
+}
+
+// GetCmdRegister ...
+func GetCmdRegister(cdc *wire.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "register",
+		Short: "Register the address of the --name key as an identity owned by it",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return sendTx(cdc, func(from sdk.AccAddress) (sdk.Msg, error) {
+				return identity.MsgReg{Sender: from}, nil
+			})
+		},
+	}
+}
+
+// GetCmdAddOwner ...
+func GetCmdAddOwner(cdc *wire.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "add-owner [ident] [owner]",
+		Short: "Add an owner to an identity",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return sendTx(cdc, func(from sdk.AccAddress) (sdk.Msg, error) {
+				ident, owner, err := parseAddrs(args[0], args[1])
+				if err != nil {
+					return nil, err
+				}
+				return identity.MsgAddOwner{Sender: from, Ident: ident, Owner: owner}, nil
+			})
+		},
+	}
+}
+
+// GetCmdDelOwner ...
+func GetCmdDelOwner(cdc *wire.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "del-owner [ident] [owner]",
+		Short: "Remove an owner of an identity",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return sendTx(cdc, func(from sdk.AccAddress) (sdk.Msg, error) {
+				ident, owner, err := parseAddrs(args[0], args[1])
+				if err != nil {
+					return nil, err
+				}
+				return identity.MsgDelOwner{Sender: from, Ident: ident, Owner: owner}, nil
+			})
+		},
+	}
+}
+
+// GetCmdSetTrust ...
+func GetCmdSetTrust(cdc *wire.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "set-trust [address] [true|false]",
+		Short: "Trust or stop trusting an address, only validators can trust",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return sendTx(cdc, func(from sdk.AccAddress) (sdk.Msg, error) {
+				trusting, err := sdk.AccAddressFromBech32(args[0])
+				if err != nil {
+					return nil, err
+				}
+				trust, err := strconv.ParseBool(args[1])
+				if err != nil {
+					return nil, err
+				}
+				return identity.NewMsgSetTrust(from, trusting, trust), nil
+			})
+		},
+	}
+}
+
+// GetCmdSetCerts ...
+func GetCmdSetCerts(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set-certs [owner]",
+		Short: "Certify an address with the certs of a JSON file",
+		Long: `Certify an address with the certs of a JSON file, a list of
+{"property": "organic", "data": {...}, "confidence": true, "expires_at": "0"}
+as in the REST body. Certs are issued by the --name key, or by the --issuer
+identity the key is an owner of.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return sendTx(cdc, func(from sdk.AccAddress) (sdk.Msg, error) {
+				owner, err := sdk.AccAddressFromBech32(args[0])
+				if err != nil {
+					return nil, err
+				}
+				issuer := from
+				if s := viper.GetString(flagIssuer); s != "" {
+					issuer, err = sdk.AccAddressFromBech32(s)
+					if err != nil {
+						return nil, err
+					}
+				}
+				values, err := readCertValues(cdc, viper.GetString(flagFile))
+				if err != nil {
+					return nil, err
+				}
+				for i := range values {
+					values[i].Owner = owner
+				}
+				return identity.NewMsgSetCerts(from, issuer, values), nil
+			})
+		},
+	}
+	cmd.Flags().String(flagFile, "", "JSON file of the certs")
+	cmd.Flags().String(flagIssuer, "", "Identity issuing the certs, the --name key by default")
+	return cmd
+}
+
+// readCertValues decodes the certs with the codec, the same encoding the
+// REST server accepts
+func readCertValues(cdc *wire.Codec, file string) ([]identity.CertValue, error) {
+	if file == "" {
+		return nil, fmt.Errorf("--%s is required", flagFile)
+	}
+	bz, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var values []identity.CertValue
+	if err := cdc.UnmarshalJSON(bz, &values); err != nil {
+		return nil, fmt.Errorf("invalid certs in %s: %v", file, err)
+	}
+	return values, nil
+}
+
+func parseAddrs(a, b string) (sdk.AccAddress, sdk.AccAddress, error) {
+	addrA, err := sdk.AccAddressFromBech32(a)
+	if err != nil {
+		return nil, nil, err
+	}
+	addrB, err := sdk.AccAddressFromBech32(b)
+	if err != nil {
+		return nil, nil, err
+	}
+	return addrA, addrB, nil
+}
@@ -0,0 +1,261 @@
+package client
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/cosmos/cosmos-sdk/crypto/keys"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+
+	"github.com/icheckteam/ichain/x/identity"
+)
+
+const storeName = "identity"
+
+// W3C Verifiable Credentials vocabulary
+const (
+	ContextCredentials = "https://www.w3.org/2018/credentials/v1"
+	ContextIchain      = "https://ichain.io/credentials/v1"
+
+	TypeVerifiableCredential = "VerifiableCredential"
+	TypeIchainCertificate    = "IchainCertificate"
+
+	ProofTypeChainAnchor = "IchainStoreAnchor2018"
+	ProofTypeEd25519     = "Ed25519Signature2018"
+	ProofTypeSecp256k1   = "EcdsaSecp256k1Signature2019"
+)
+
+// Credential is a W3C Verifiable Credential of a cert
+type Credential struct {
+	Context           []string          `json:"@context"`
+	ID                string            `json:"id"`
+	Type              []string          `json:"type"`
+	Issuer            string            `json:"issuer"`
+	IssuanceDate      string            `json:"issuanceDate"`
+	ExpirationDate    string            `json:"expirationDate,omitempty"`
+	CredentialSubject CredentialSubject `json:"credentialSubject"`
+	CredentialStatus  *CredentialStatus `json:"credentialStatus,omitempty"`
+	Proof             []Proof           `json:"proof,omitempty"`
+}
+
+// CredentialSubject is the owner of the cert and the certified claims
+type CredentialSubject struct {
+	ID       string          `json:"id"`
+	Property string          `json:"property"`
+	Claims   json.RawMessage `json:"claims,omitempty"`
+}
+
+// CredentialStatus is set when the cert was revoked
+type CredentialStatus struct {
+	Type         string `json:"type"`
+	Revoked      bool   `json:"revoked"`
+	RevokedAt    string `json:"revokedAt"`
+	RevokeReason string `json:"revokeReason,omitempty"`
+}
+
+// Proof is either a chain anchor or a signature of the certifier
+type Proof struct {
+	Type               string `json:"type"`
+	Created            string `json:"created"`
+	ProofPurpose       string `json:"proofPurpose"`
+	VerificationMethod string `json:"verificationMethod"`
+
+	// chain anchor
+	Height    int64  `json:"height,omitempty"`
+	StoreName string `json:"storeName,omitempty"`
+	StoreKey  string `json:"storeKey,omitempty"`  // hex
+	ValueHash string `json:"valueHash,omitempty"` // hex sha256 of the stored cert
+
+	// signature
+	PublicKey  string `json:"publicKey,omitempty"`  // base64
+	ProofValue string `json:"proofValue,omitempty"` // base64
+}
+
+// NewCredential packages a cert as a verifiable credential without proof
+func NewCredential(cert identity.Cert) Credential {
+	vc := Credential{
+		Context:      []string{ContextCredentials, ContextIchain},
+		ID:           fmt.Sprintf("%s/certs/%s/%s", identity.DID(cert.Owner), cert.Property, cert.Certifier.String()),
+		Type:         []string{TypeVerifiableCredential, TypeIchainCertificate},
+		Issuer:       identity.DID(cert.Certifier),
+		IssuanceDate: formatTime(cert.CreatedAt),
+		CredentialSubject: CredentialSubject{
+			ID:       identity.DID(cert.Owner),
+			Property: cert.Property,
+		},
+	}
+	if len(cert.Data) > 0 && json.Valid(cert.Data) {
+		vc.CredentialSubject.Claims = json.RawMessage(cert.Data)
+	}
+	if cert.ExpiresAt > 0 {
+		vc.ExpirationDate = formatTime(cert.ExpiresAt)
+	}
+	if cert.Revoked {
+		vc.CredentialStatus = &CredentialStatus{
+			Type:         "IchainRevocationList2018",
+			Revoked:      true,
+			RevokedAt:    formatTime(cert.RevokedAt),
+			RevokeReason: cert.RevokeReason,
+		}
+	}
+	return vc
+}
+
+// SignBytes returns the canonical bytes signed by the certifier, the
+// credential without its proofs
+func (vc Credential) SignBytes() ([]byte, error) {
+	vc.Proof = nil
+	bz, err := json.Marshal(vc)
+	if err != nil {
+		return nil, err
+	}
+	return sdk.SortJSON(bz)
+}
+
+// QueryCredential loads a cert and packages it as a credential anchored to
+// the height it was read at
+func QueryCredential(ctx context.CLIContext, cdc *wire.Codec, owner sdk.AccAddress, property string, certifier sdk.AccAddress) (Credential, error) {
+	key := identity.KeyCert(owner, property, certifier)
+	node, err := ctx.GetNode()
+	if err != nil {
+		return Credential{}, err
+	}
+	res, err := node.ABCIQuery(fmt.Sprintf("/store/%s/key", storeName), key)
+	if err != nil {
+		return Credential{}, err
+	}
+	if !res.Response.IsOK() {
+		return Credential{}, fmt.Errorf("query failed: (%d) %s", res.Response.Code, res.Response.Log)
+	}
+	if len(res.Response.Value) == 0 {
+		return Credential{}, fmt.Errorf("cert %s of %s by %s not found", property, owner, certifier)
+	}
+	cert, err := identity.UnmarshalCert(cdc, res.Response.Value)
+	if err != nil {
+		return Credential{}, err
+	}
+
+	vc := NewCredential(cert)
+	hash := sha256.Sum256(res.Response.Value)
+	vc.Proof = append(vc.Proof, Proof{
+		Type:               ProofTypeChainAnchor,
+		Created:            time.Now().UTC().Format(time.RFC3339),
+		ProofPurpose:       "assertionMethod",
+		VerificationMethod: vc.Issuer,
+		Height:             res.Response.Height,
+		StoreName:          storeName,
+		StoreKey:           hex.EncodeToString(key),
+		ValueHash:          hex.EncodeToString(hash[:]),
+	})
+	return vc, nil
+}
+
+// QueryCredentials packages all the certs of an address, optionally only
+// the ones of a certifier or of a property
+func QueryCredentials(ctx context.CLIContext, cdc *wire.Codec, owner sdk.AccAddress, property string, certifier sdk.AccAddress) ([]Credential, error) {
+	kvs, err := ctx.QuerySubspace(identity.KeyCerts(owner), storeName)
+	if err != nil {
+		return nil, err
+	}
+	vcs := []Credential{}
+	for _, kv := range kvs {
+		cert, err := identity.UnmarshalCert(cdc, kv.Value)
+		if err != nil {
+			return nil, err
+		}
+		if property != "" && cert.Property != property {
+			continue
+		}
+		if len(certifier) > 0 && !bytes.Equal(cert.Certifier, certifier) {
+			continue
+		}
+		vc, err := QueryCredential(ctx, cdc, cert.Owner, cert.Property, cert.Certifier)
+		if err != nil {
+			return nil, err
+		}
+		vcs = append(vcs, vc)
+	}
+	return vcs, nil
+}
+
+// SignCredential adds a signature proof made with the key of one of the
+// owners of the certifier, the proof references the verification method
+// the DID document of the certifier lists for that owner
+func SignCredential(kb keys.Keybase, name, passphrase string, vc Credential, owners []sdk.AccAddress) (Credential, error) {
+	info, err := kb.Get(name)
+	if err != nil {
+		return vc, err
+	}
+	signer := sdk.AccAddress(info.GetPubKey().Address())
+	if !isOwner(owners, signer) {
+		return vc, fmt.Errorf("key %s is not the key of an owner of the issuer %s", name, vc.Issuer)
+	}
+	msg, err := vc.SignBytes()
+	if err != nil {
+		return vc, err
+	}
+	sig, pub, err := kb.Sign(name, passphrase, msg)
+	if err != nil {
+		return vc, err
+	}
+	proofType, err := signatureType(pub)
+	if err != nil {
+		return vc, err
+	}
+	vc.Proof = append(vc.Proof, Proof{
+		Type:               proofType,
+		Created:            time.Now().UTC().Format(time.RFC3339),
+		ProofPurpose:       "assertionMethod",
+		VerificationMethod: vc.Issuer + "#" + signer.String(),
+		PublicKey:          base64.StdEncoding.EncodeToString(publicKeyBytes(pub)),
+		ProofValue:         base64.StdEncoding.EncodeToString(sig),
+	})
+	return vc, nil
+}
+
+func isOwner(owners []sdk.AccAddress, addr sdk.AccAddress) bool {
+	for _, owner := range owners {
+		if bytes.Equal(owner, addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func signatureType(pub crypto.PubKey) (string, error) {
+	switch pub.(type) {
+	case ed25519.PubKeyEd25519:
+		return ProofTypeEd25519, nil
+	case secp256k1.PubKeySecp256k1:
+		return ProofTypeSecp256k1, nil
+	default:
+		return "", fmt.Errorf("unsupported key type %T", pub)
+	}
+}
+
+// publicKeyBytes returns the raw key without its amino prefix
+func publicKeyBytes(pub crypto.PubKey) []byte {
+	switch pub := pub.(type) {
+	case ed25519.PubKeyEd25519:
+		return pub[:]
+	case secp256k1.PubKeySecp256k1:
+		return pub[:]
+	default:
+		return pub.Bytes()
+	}
+}
+
+func formatTime(t int64) string {
+	return time.Unix(t, 0).UTC().Format(time.RFC3339)
+}
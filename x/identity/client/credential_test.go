@@ -0,0 +1,74 @@
+package client
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	dbm "github.com/tendermint/tendermint/libs/db"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/icheckteam/ichain/x/identity"
+)
+
+func TestNewCredential(t *testing.T) {
+	owner := sdk.AccAddress([]byte("owner"))
+	certifier := sdk.AccAddress([]byte("certifier"))
+	vc := NewCredential(identity.Cert{
+		Property:  "organic",
+		Owner:     owner,
+		Certifier: certifier,
+		Data:      identity.Metadata(`{"standard":"EU 2018/848"}`),
+		CreatedAt: 100,
+		ExpiresAt: 200,
+		Revoked:   true,
+		RevokedAt: 150,
+	})
+	assert.Equal(t, identity.DID(certifier), vc.Issuer)
+	assert.Equal(t, identity.DID(owner), vc.CredentialSubject.ID)
+	assert.Equal(t, `{"standard":"EU 2018/848"}`, string(vc.CredentialSubject.Claims))
+	assert.Equal(t, "1970-01-01T00:03:20Z", vc.ExpirationDate)
+	require.NotNil(t, vc.CredentialStatus)
+	assert.True(t, vc.CredentialStatus.Revoked)
+}
+
+func TestSignCredential(t *testing.T) {
+	kb := keys.New(dbm.NewMemDB())
+	info, _, err := kb.CreateMnemonic("certifier", keys.English, "12345678", keys.Secp256k1)
+	require.Nil(t, err)
+	certifier := sdk.AccAddress(info.GetPubKey().Address())
+
+	vc := NewCredential(identity.Cert{
+		Property:  "organic",
+		Owner:     sdk.AccAddress([]byte("owner")),
+		Certifier: certifier,
+	})
+	signed, err := SignCredential(kb, "certifier", "12345678", vc, []sdk.AccAddress{certifier})
+	require.Nil(t, err)
+	require.Equal(t, 1, len(signed.Proof))
+	proof := signed.Proof[0]
+	assert.Equal(t, ProofTypeSecp256k1, proof.Type)
+	assert.Equal(t, identity.DID(certifier)+"#"+certifier.String(), proof.VerificationMethod)
+
+	// the proofs are not part of the signed bytes
+	msg, err := signed.SignBytes()
+	require.Nil(t, err)
+	sig, err := base64.StdEncoding.DecodeString(proof.ProofValue)
+	require.Nil(t, err)
+	assert.True(t, info.GetPubKey().VerifyBytes(msg, sig))
+
+	// an owner signs for an identity, the method is the one the DID
+	// document of the identity lists for that owner
+	org := sdk.AccAddress([]byte("org"))
+	vc.Issuer = identity.DID(org)
+	signed, err = SignCredential(kb, "certifier", "12345678", vc, []sdk.AccAddress{org, certifier})
+	require.Nil(t, err)
+	assert.Equal(t, identity.DID(org)+"#"+certifier.String(), signed.Proof[0].VerificationMethod)
+
+	// only an owner of the certifier can sign
+	_, err = SignCredential(kb, "certifier", "12345678", vc, []sdk.AccAddress{org})
+	assert.NotNil(t, err)
+}
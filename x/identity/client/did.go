@@ -0,0 +1,227 @@
+package client
+
+import (
+	"encoding/hex"
+	"errors"
+
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	authcmd "github.com/cosmos/cosmos-sdk/x/auth/client/cli"
+
+	"github.com/icheckteam/ichain/x/identity"
+)
+
+// ContextDID is the JSON-LD context of DID documents
+const ContextDID = "https://w3id.org/did/v1"
+
+// Verification method types of the owner keys
+const (
+	VerificationKeyEd25519   = "Ed25519VerificationKey2018"
+	VerificationKeySecp256k1 = "Secp256k1VerificationKey2018"
+	// VerificationAccount is used for owners whose public key is not on chain
+	// yet, they never signed a transaction
+	VerificationAccount = "IchainAccountAddress2018"
+)
+
+const accStoreName = "acc"
+
+// ErrDIDNotFound is returned when no identity is registered for a DID
+var ErrDIDNotFound = errors.New("DID not found")
+
+// DIDDocument is the W3C DID document of an identity
+type DIDDocument struct {
+	Context            []string             `json:"@context"`
+	ID                 string               `json:"id"`
+	Controller         []string             `json:"controller"`
+	VerificationMethod []VerificationMethod `json:"verificationMethod"`
+	Authentication     []string             `json:"authentication"`
+	AssertionMethod    []string             `json:"assertionMethod"`
+	Service            []DIDService         `json:"service"`
+	Certs              []DIDCert            `json:"certs"`
+}
+
+// VerificationMethod is the key of an owner of the identity
+type VerificationMethod struct {
+	ID                  string `json:"id"`
+	Type                string `json:"type"`
+	Controller          string `json:"controller"`
+	PublicKeyHex        string `json:"publicKeyHex,omitempty"`
+	BlockchainAccountID string `json:"blockchainAccountId"`
+}
+
+// DIDService is a service endpoint of the identity
+type DIDService struct {
+	ID              string `json:"id"`
+	Type            string `json:"type"`
+	ServiceEndpoint string `json:"serviceEndpoint"`
+}
+
+// DIDCert summarizes a cert of the identity, the full credential is
+// exported by QueryCredential
+type DIDCert struct {
+	ID       string `json:"id"`
+	Property string `json:"property"`
+	Issuer   string `json:"issuer"`
+	Status   string `json:"status"`
+}
+
+// ResolveDID builds the DID document of a did:ichain identifier, the certs
+// report their status at the unix time now
+func ResolveDID(ctx context.CLIContext, cdc *wire.Codec, did string, now int64) (DIDDocument, error) {
+	ident, err := identity.ParseDID(did)
+	if err != nil {
+		return DIDDocument{}, err
+	}
+	owners, err := QueryOwners(ctx, ident)
+	if err != nil {
+		return DIDDocument{}, err
+	}
+	if len(owners) == 0 {
+		return DIDDocument{}, ErrDIDNotFound
+	}
+	id := identity.DID(ident)
+	doc := DIDDocument{
+		Context:            []string{ContextDID},
+		ID:                 id,
+		Controller:         []string{},
+		VerificationMethod: []VerificationMethod{},
+		Authentication:     []string{},
+		AssertionMethod:    []string{},
+		Service:            []DIDService{},
+		Certs:              []DIDCert{},
+	}
+
+	for _, owner := range owners {
+		method, err := queryVerificationMethod(ctx, cdc, id, owner)
+		if err != nil {
+			return DIDDocument{}, err
+		}
+		doc.Controller = append(doc.Controller, identity.DID(owner))
+		doc.VerificationMethod = append(doc.VerificationMethod, method)
+		doc.Authentication = append(doc.Authentication, method.ID)
+		doc.AssertionMethod = append(doc.AssertionMethod, method.ID)
+	}
+
+	services, err := QueryServices(ctx, cdc, ident)
+	if err != nil {
+		return DIDDocument{}, err
+	}
+	for _, service := range services {
+		doc.Service = append(doc.Service, DIDService{
+			ID:              id + "#" + service.ID,
+			Type:            service.Type,
+			ServiceEndpoint: service.Endpoint,
+		})
+	}
+
+	kvs, err := ctx.QuerySubspace(identity.KeyCerts(ident), storeName)
+	if err != nil {
+		return DIDDocument{}, err
+	}
+	for _, kv := range kvs {
+		cert, err := identity.UnmarshalCert(cdc, kv.Value)
+		if err != nil {
+			return DIDDocument{}, err
+		}
+		doc.Certs = append(doc.Certs, DIDCert{
+			ID:       NewCredential(cert).ID,
+			Property: cert.Property,
+			Issuer:   identity.DID(cert.Certifier),
+			Status:   cert.StatusAt(now),
+		})
+	}
+	return doc, nil
+}
+
+// QueryOwners returns the owners of an identity
+func QueryOwners(ctx context.CLIContext, ident sdk.AccAddress) ([]sdk.AccAddress, error) {
+	kvs, err := querySubspace(ctx, identity.KeyOwners(ident))
+	if err != nil {
+		return nil, err
+	}
+	owners := make([]sdk.AccAddress, len(kvs))
+	for i, kv := range kvs {
+		owners[i] = sdk.AccAddress(kv.Key[1+sdk.AddrLen:])
+	}
+	return owners, nil
+}
+
+// QueryRegistered returns whether the identity was registered by the key of
+// its address
+func QueryRegistered(ctx context.CLIContext, ident sdk.AccAddress) (bool, error) {
+	res, err := ctx.QueryStore(identity.KeyRegistration(ident), storeName)
+	if err != nil {
+		return false, err
+	}
+	return len(res) > 0, nil
+}
+
+// QueryOwnedIdents returns the identities an address is an owner of
+func QueryOwnedIdents(ctx context.CLIContext, owner sdk.AccAddress) ([]sdk.AccAddress, error) {
+	kvs, err := querySubspace(ctx, identity.KeyOwnedIdents(owner))
+	if err != nil {
+		return nil, err
+	}
+	idents := make([]sdk.AccAddress, len(kvs))
+	for i, kv := range kvs {
+		idents[i] = sdk.AccAddress(kv.Key[len(identity.OwnedIdentsKey)+len(owner):])
+	}
+	return idents, nil
+}
+
+// QueryServices returns the service endpoints of an identity
+func QueryServices(ctx context.CLIContext, cdc *wire.Codec, ident sdk.AccAddress) ([]identity.Service, error) {
+	kvs, err := ctx.QuerySubspace(identity.KeyServices(ident), storeName)
+	if err != nil {
+		return nil, err
+	}
+	services := make([]identity.Service, len(kvs))
+	for i, kv := range kvs {
+		if err := cdc.UnmarshalBinary(kv.Value, &services[i]); err != nil {
+			return nil, err
+		}
+	}
+	return services, nil
+}
+
+// queryVerificationMethod takes the public key of an owner from its account
+func queryVerificationMethod(ctx context.CLIContext, cdc *wire.Codec, did string, owner sdk.AccAddress) (VerificationMethod, error) {
+	method := VerificationMethod{
+		ID:                  did + "#" + owner.String(),
+		Type:                VerificationAccount,
+		Controller:          identity.DID(owner),
+		BlockchainAccountID: owner.String(),
+	}
+	res, err := ctx.QueryStore(auth.AddressStoreKey(owner), accStoreName)
+	if err != nil {
+		return method, err
+	}
+	if len(res) == 0 {
+		return method, nil
+	}
+	account, err := authcmd.GetAccountDecoder(cdc)(res)
+	if err != nil {
+		return method, err
+	}
+	if pub := account.GetPubKey(); pub != nil {
+		setVerificationKey(&method, pub)
+	}
+	return method, nil
+}
+
+func setVerificationKey(method *VerificationMethod, pub crypto.PubKey) {
+	switch pub := pub.(type) {
+	case ed25519.PubKeyEd25519:
+		method.Type = VerificationKeyEd25519
+		method.PublicKeyHex = hex.EncodeToString(pub[:])
+	case secp256k1.PubKeySecp256k1:
+		method.Type = VerificationKeySecp256k1
+		method.PublicKeyHex = hex.EncodeToString(pub[:])
+	}
+}
@@ -0,0 +1,51 @@
+package client
+
+import (
+	"bytes"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+
+	"github.com/icheckteam/ichain/x/identity"
+)
+
+// QueryProfile returns the profile of an identity
+func QueryProfile(ctx context.CLIContext, cdc *wire.Codec, addr sdk.AccAddress) (profile identity.Profile, found bool, err error) {
+	res, err := ctx.QueryStore(identity.KeyProfile(addr), storeName)
+	if err != nil || len(res) == 0 {
+		return
+	}
+	profile, err = identity.UnmarshalProfile(cdc, res)
+	return profile, err == nil, err
+}
+
+// QueryProfiles returns the profiles of the addresses that have one, each
+// address is queried once
+func QueryProfiles(ctx context.CLIContext, cdc *wire.Codec, addrs ...sdk.AccAddress) ([]identity.Profile, error) {
+	profiles := []identity.Profile{}
+	seen := [][]byte{}
+	for _, addr := range addrs {
+		if len(addr) == 0 || containsBytes(seen, addr) {
+			continue
+		}
+		seen = append(seen, addr)
+		profile, found, err := QueryProfile(ctx, cdc, addr)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			profiles = append(profiles, profile)
+		}
+	}
+	return profiles, nil
+}
+
+func containsBytes(list [][]byte, bz []byte) bool {
+	for _, b := range list {
+		if bytes.Equal(b, bz) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,30 @@
+package client
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// querySubspace returns the pairs under a prefix. Subspace queries carry no
+// proof, so when the node is not trusted each pair is read again with a
+// proof checked key query: the node can still leave pairs out but cannot
+// make them up.
+func querySubspace(ctx context.CLIContext, prefix []byte) ([]sdk.KVPair, error) {
+	kvs, err := ctx.QuerySubspace(prefix, storeName)
+	if err != nil || ctx.TrustNode {
+		return kvs, err
+	}
+	for _, kv := range kvs {
+		res, err := ctx.QueryStore(kv.Key, storeName)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(res, kv.Value) {
+			return nil, fmt.Errorf("the node returned an unproven value for key %X", kv.Key)
+		}
+	}
+	return kvs, nil
+}
@@ -0,0 +1,146 @@
+package rest
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/cosmos/cosmos-sdk/crypto/keys"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+
+	"github.com/icheckteam/ichain/x/identity"
+)
+
+type msgSetThresholdBody struct {
+	BaseReq   baseBody `json:"base_req"`
+	Threshold int64    `json:"threshold"`
+}
+
+type msgActionBody struct {
+	BaseReq baseBody `json:"base_req"`
+}
+
+type thresholdOutput struct {
+	Threshold  int64 `json:"threshold"`
+	OwnerCount int64 `json:"owner_count"`
+}
+
+func getThresholdHandlerFn(ctx context.CLIContext, cdc *wire.Codec) http.HandlerFunc {
+	return withErr(func(w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		ident, err := sdk.AccAddressFromBech32(vars[RestAccount])
+		if err != nil {
+			return err
+		}
+		var output thresholdOutput
+		if err := queryInt64(ctx, cdc, identity.KeyThreshold(ident), &output.Threshold); err != nil {
+			return err
+		}
+		if err := queryInt64(ctx, cdc, identity.KeyOwnerCount(ident), &output.OwnerCount); err != nil {
+			return err
+		}
+		WriteJSON(w, cdc, output)
+		return nil
+	})
+}
+
+func queryInt64(ctx context.CLIContext, cdc *wire.Codec, key []byte, n *int64) error {
+	res, err := ctx.QueryStore(key, storeName)
+	if err != nil || len(res) == 0 {
+		return err
+	}
+	return cdc.UnmarshalBinary(res, n)
+}
+
+func getPendingActionsHandlerFn(ctx context.CLIContext, cdc *wire.Codec) http.HandlerFunc {
+	return withErr(func(w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		ident, err := sdk.AccAddressFromBech32(vars[RestAccount])
+		if err != nil {
+			return err
+		}
+		kvs, err := ctx.QuerySubspace(identity.KeyPendingActions(ident), storeName)
+		if err != nil {
+			return err
+		}
+		actions := make([]identity.PendingAction, len(kvs))
+		for i, kv := range kvs {
+			actions[i], err = identity.UnmarshalPendingAction(cdc, kv.Value)
+			if err != nil {
+				return err
+			}
+		}
+		WriteJSON(w, cdc, actions)
+		return nil
+	})
+}
+
+func setThresholdHandlerFn(ctx context.CLIContext, cdc *wire.Codec, kb keys.Keybase) http.HandlerFunc {
+	return withErr(func(w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		var m msgSetThresholdBody
+		body, err := ioutil.ReadAll(r.Body)
+		err = cdc.UnmarshalJSON(body, &m)
+		if err != nil {
+			return err
+		}
+		err = m.BaseReq.Validate()
+		if err != nil {
+			return err
+		}
+		sender, err := m.BaseReq.sender(kb)
+		if err != nil {
+			return err
+		}
+		ident, err := sdk.AccAddressFromBech32(vars[RestAccount])
+		if err != nil {
+			return err
+		}
+		msg := identity.NewMsgSetThreshold(sender, ident, m.Threshold)
+		if err := msg.ValidateBasic(); err != nil {
+			return err
+		}
+		signAndBuild(ctx, cdc, w, m.BaseReq, msg)
+		return nil
+	})
+}
+
+// actionHandlerFn signs an approval or a cancellation of a pending action
+func actionHandlerFn(ctx context.CLIContext, cdc *wire.Codec, kb keys.Keybase, approve bool) http.HandlerFunc {
+	return withErr(func(w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		var m msgActionBody
+		body, err := ioutil.ReadAll(r.Body)
+		err = cdc.UnmarshalJSON(body, &m)
+		if err != nil {
+			return err
+		}
+		err = m.BaseReq.Validate()
+		if err != nil {
+			return err
+		}
+		sender, err := m.BaseReq.sender(kb)
+		if err != nil {
+			return err
+		}
+		ident, err := sdk.AccAddressFromBech32(vars[RestAccount])
+		if err != nil {
+			return err
+		}
+		actionID, err := strconv.ParseInt(vars["id"], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid action id %s", vars["id"])
+		}
+		var msg sdk.Msg = identity.NewMsgCancelAction(sender, ident, actionID)
+		if approve {
+			msg = identity.NewMsgApproveAction(sender, ident, actionID)
+		}
+		signAndBuild(ctx, cdc, w, m.BaseReq, msg)
+		return nil
+	})
+}
@@ -0,0 +1,104 @@
+package rest
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/cosmos/cosmos-sdk/crypto/keys"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+
+	identityclient "github.com/icheckteam/ichain/x/identity/client"
+)
+
+type signCredentialBody struct {
+	Name      string `json:"name"`
+	Password  string `json:"password"`
+	Property  string `json:"property"`
+	Certifier string `json:"certifier"`
+}
+
+// queryCredentialsHandlerFn returns the certs of an address as verifiable
+// credentials anchored to the chain, filtered by the property and
+// certifier query parameters
+func queryCredentialsHandlerFn(ctx context.CLIContext, cdc *wire.Codec) http.HandlerFunc {
+	return withErr(func(w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		owner, err := sdk.AccAddressFromBech32(vars[RestAccount])
+		if err != nil {
+			return err
+		}
+		var certifier sdk.AccAddress
+		if s := r.URL.Query().Get("certifier"); s != "" {
+			certifier, err = sdk.AccAddressFromBech32(s)
+			if err != nil {
+				return err
+			}
+		}
+		vcs, err := identityclient.QueryCredentials(ctx, cdc, owner, r.URL.Query().Get("property"), certifier)
+		if err != nil {
+			return err
+		}
+		return writeCredentials(w, vcs)
+	})
+}
+
+// signCredentialHandlerFn returns the credential of a cert signed by the key
+// of an owner of the certifier, the certifier is the key's own address
+// unless set
+func signCredentialHandlerFn(ctx context.CLIContext, cdc *wire.Codec, kb keys.Keybase) http.HandlerFunc {
+	return withErr(func(w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		var m signCredentialBody
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return err
+		}
+		if err = json.Unmarshal(body, &m); err != nil {
+			return err
+		}
+		owner, err := sdk.AccAddressFromBech32(vars[RestAccount])
+		if err != nil {
+			return err
+		}
+		info, err := kb.Get(m.Name)
+		if err != nil {
+			return err
+		}
+		certifier := sdk.AccAddress(info.GetPubKey().Address())
+		if m.Certifier != "" {
+			certifier, err = sdk.AccAddressFromBech32(m.Certifier)
+			if err != nil {
+				return err
+			}
+		}
+		vc, err := identityclient.QueryCredential(ctx, cdc, owner, m.Property, certifier)
+		if err != nil {
+			return err
+		}
+		owners, err := identityclient.QueryOwners(ctx, certifier)
+		if err != nil {
+			return err
+		}
+		vc, err = identityclient.SignCredential(kb, m.Name, m.Password, vc, owners)
+		if err != nil {
+			return err
+		}
+		return writeCredentials(w, vc)
+	})
+}
+
+// writeCredentials uses encoding/json, the claims are raw JSON
+func writeCredentials(w http.ResponseWriter, data interface{}) error {
+	output, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/ld+json")
+	w.Write(output)
+	return nil
+}
@@ -0,0 +1,131 @@
+package rest
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/cosmos/cosmos-sdk/crypto/keys"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+
+	"github.com/icheckteam/ichain/client/errors"
+	"github.com/icheckteam/ichain/x/identity"
+	identityclient "github.com/icheckteam/ichain/x/identity/client"
+)
+
+type msgAddServiceBody struct {
+	BaseReq  baseBody `json:"base_req"`
+	ID       string   `json:"id"`
+	Type     string   `json:"type"`
+	Endpoint string   `json:"endpoint"`
+}
+
+type msgDelServiceBody struct {
+	BaseReq baseBody `json:"base_req"`
+}
+
+// resolveDIDHandlerFn returns the DID document of a did:ichain identifier
+func resolveDIDHandlerFn(ctx context.CLIContext, cdc *wire.Codec) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		doc, err := identityclient.ResolveDID(ctx, cdc, vars["did"], time.Now().Unix())
+		if err == identityclient.ErrDIDNotFound {
+			w.WriteHeader(http.StatusNotFound)
+			errors.WriteError(w, err)
+			return
+		}
+		if err != nil {
+			errors.WriteError(w, err)
+			return
+		}
+		output, err := json.Marshal(doc)
+		if err != nil {
+			errors.WriteError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/did+ld+json")
+		w.Write(output)
+	}
+}
+
+func queryServicesHandlerFn(ctx context.CLIContext, cdc *wire.Codec) http.HandlerFunc {
+	return withErr(func(w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		ident, err := sdk.AccAddressFromBech32(vars[RestAccount])
+		if err != nil {
+			return err
+		}
+		services, err := identityclient.QueryServices(ctx, cdc, ident)
+		if err != nil {
+			return err
+		}
+		WriteJSON(w, cdc, services)
+		return nil
+	})
+}
+
+func addServiceHandlerFn(ctx context.CLIContext, cdc *wire.Codec, kb keys.Keybase) http.HandlerFunc {
+	return withErr(func(w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		var m msgAddServiceBody
+		body, err := ioutil.ReadAll(r.Body)
+		err = cdc.UnmarshalJSON(body, &m)
+		if err != nil {
+			return err
+		}
+		err = m.BaseReq.Validate()
+		if err != nil {
+			return err
+		}
+		sender, err := m.BaseReq.sender(kb)
+		if err != nil {
+			return err
+		}
+		ident, err := sdk.AccAddressFromBech32(vars[RestAccount])
+		if err != nil {
+			return err
+		}
+		msg := identity.NewMsgAddService(sender, ident, identity.Service{
+			ID:       m.ID,
+			Type:     m.Type,
+			Endpoint: m.Endpoint,
+		})
+		if err := msg.ValidateBasic(); err != nil {
+			return err
+		}
+		signAndBuild(ctx, cdc, w, m.BaseReq, msg)
+		return nil
+	})
+}
+
+func delServiceHandlerFn(ctx context.CLIContext, cdc *wire.Codec, kb keys.Keybase) http.HandlerFunc {
+	return withErr(func(w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		var m msgDelServiceBody
+		body, err := ioutil.ReadAll(r.Body)
+		err = cdc.UnmarshalJSON(body, &m)
+		if err != nil {
+			return err
+		}
+		err = m.BaseReq.Validate()
+		if err != nil {
+			return err
+		}
+		sender, err := m.BaseReq.sender(kb)
+		if err != nil {
+			return err
+		}
+		ident, err := sdk.AccAddressFromBech32(vars[RestAccount])
+		if err != nil {
+			return err
+		}
+		msg := identity.NewMsgDelService(sender, ident, vars["id"])
+		signAndBuild(ctx, cdc, w, m.BaseReq, msg)
+		return nil
+	})
+}
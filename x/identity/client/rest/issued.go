@@ -0,0 +1,67 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+
+	identityclient "github.com/icheckteam/ichain/x/identity/client"
+)
+
+const (
+	defaultIssuedCertsLimit = 100
+	maxIssuedCertsLimit     = 500
+)
+
+func pageParams(r *http.Request) (page, limit int, err error) {
+	page, limit = 1, defaultIssuedCertsLimit
+	query := r.URL.Query()
+	if s := query.Get("page"); s != "" {
+		page, err = strconv.Atoi(s)
+		if err != nil {
+			return
+		}
+		if page < 1 {
+			err = fmt.Errorf("page must be at least 1")
+			return
+		}
+	}
+	if s := query.Get("limit"); s != "" {
+		limit, err = strconv.Atoi(s)
+		if err != nil {
+			return
+		}
+		if limit < 1 || limit > maxIssuedCertsLimit {
+			err = fmt.Errorf("limit must be between 1 and %d", maxIssuedCertsLimit)
+		}
+	}
+	return
+}
+
+// issuedCertsHandlerFn returns a page of the certs issued by the address,
+// optionally only those of a property
+func issuedCertsHandlerFn(ctx context.CLIContext, cdc *wire.Codec) http.HandlerFunc {
+	return withErr(func(w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		certifier, err := sdk.AccAddressFromBech32(vars[RestAccount])
+		if err != nil {
+			return err
+		}
+		page, limit, err := pageParams(r)
+		if err != nil {
+			return err
+		}
+		issued, err := identityclient.QueryIssuedCerts(ctx, cdc, certifier, r.URL.Query().Get("property"), page, limit)
+		if err != nil {
+			return err
+		}
+		WriteJSON(w, cdc, issued)
+		return nil
+	})
+}
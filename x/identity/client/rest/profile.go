@@ -0,0 +1,87 @@
+package rest
+
+import (
+	"io/ioutil"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/cosmos/cosmos-sdk/crypto/keys"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+
+	"github.com/icheckteam/ichain/x/identity"
+	identityclient "github.com/icheckteam/ichain/x/identity/client"
+)
+
+type msgSetProfileBody struct {
+	BaseReq baseBody         `json:"base_req"`
+	Profile identity.Profile `json:"profile"`
+}
+
+type identityOutput struct {
+	Address sdk.AccAddress    `json:"address"`
+	DID     string            `json:"did"`
+	Owners  []sdk.AccAddress  `json:"owners"`
+	Profile *identity.Profile `json:"profile"`
+}
+
+// getIdentityHandlerFn returns the owners and the profile of an identity
+func getIdentityHandlerFn(ctx context.CLIContext, cdc *wire.Codec) http.HandlerFunc {
+	return withErr(func(w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		ident, err := sdk.AccAddressFromBech32(vars[RestAccount])
+		if err != nil {
+			return err
+		}
+		owners, err := getOwners(ctx, ident, cdc)
+		if err != nil {
+			return err
+		}
+		output := identityOutput{
+			Address: ident,
+			DID:     identity.DID(ident),
+			Owners:  owners,
+		}
+		profile, found, err := identityclient.QueryProfile(ctx, cdc, ident)
+		if err != nil {
+			return err
+		}
+		if found {
+			output.Profile = &profile
+		}
+		WriteJSON(w, cdc, output)
+		return nil
+	})
+}
+
+func setProfileHandlerFn(ctx context.CLIContext, cdc *wire.Codec, kb keys.Keybase) http.HandlerFunc {
+	return withErr(func(w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		var m msgSetProfileBody
+		body, err := ioutil.ReadAll(r.Body)
+		err = cdc.UnmarshalJSON(body, &m)
+		if err != nil {
+			return err
+		}
+		err = m.BaseReq.Validate()
+		if err != nil {
+			return err
+		}
+		sender, err := m.BaseReq.sender(kb)
+		if err != nil {
+			return err
+		}
+		ident, err := sdk.AccAddressFromBech32(vars[RestAccount])
+		if err != nil {
+			return err
+		}
+		msg := identity.NewMsgSetProfile(sender, ident, m.Profile)
+		if err := msg.ValidateBasic(); err != nil {
+			return err
+		}
+		signAndBuild(ctx, cdc, w, m.BaseReq, msg)
+		return nil
+	})
+}
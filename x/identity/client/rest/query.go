@@ -3,6 +3,8 @@ package rest
 import (
 	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/cosmos/cosmos-sdk/client/context"
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -50,10 +52,73 @@ func queryCertsHandlerFn(ctx context.CLIContext, cdc *wire.Codec) http.HandlerFu
 			w.Write([]byte(fmt.Sprintf("couldn't query certs. Error: %s", err.Error())))
 			return
 		}
+
+		// only the certs valid at the given time
+		if validAt := r.URL.Query().Get("valid_at"); validAt != "" {
+			at, err := strconv.ParseInt(validAt, 10, 64)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(fmt.Sprintf("invalid valid_at. Error: %s", err.Error())))
+				return
+			}
+			certs = filterValidCerts(certs, at)
+		}
 		WriteJSON(w, cdc, certs)
 	}
 }
 
+func queryRevocationsHandlerFn(ctx context.CLIContext, cdc *wire.Codec) http.HandlerFunc {
+	return withErr(func(w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		address, err := sdk.AccAddressFromBech32(vars[RestAccount])
+		if err != nil {
+			return err
+		}
+		revocations, err := getRevocations(ctx, address, cdc)
+		if err != nil {
+			return err
+		}
+		WriteJSON(w, cdc, revocations)
+		return nil
+	})
+}
+
+// certValidityHandlerFn checks the status of a cert at the time given by
+// the at query parameter, now by default
+func certValidityHandlerFn(ctx context.CLIContext, cdc *wire.Codec) http.HandlerFunc {
+	return withErr(func(w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		address, err := sdk.AccAddressFromBech32(vars[RestAccount])
+		if err != nil {
+			return err
+		}
+		certifier, err := sdk.AccAddressFromBech32(vars["certifier"])
+		if err != nil {
+			return err
+		}
+		at := time.Now().Unix()
+		if s := r.URL.Query().Get("at"); s != "" {
+			at, err = strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				return err
+			}
+		}
+
+		output := certValidityOutput{Status: identity.CertStatusNotFound, At: at}
+		cert, found, err := getCert(ctx, address, vars["property"], certifier, cdc)
+		if err != nil {
+			return err
+		}
+		if found {
+			output.Cert = &cert
+			output.Status = cert.StatusAt(at)
+			output.Valid = cert.IsValidAt(at)
+		}
+		WriteJSON(w, cdc, output)
+		return nil
+	})
+}
+
 func trustsHandlerFn(ctx context.CLIContext, cdc *wire.Codec) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		vars := mux.Vars(r)
@@ -0,0 +1,163 @@
+package rest
+
+import (
+	"io/ioutil"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/cosmos/cosmos-sdk/crypto/keys"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+
+	"github.com/icheckteam/ichain/x/identity"
+)
+
+type msgSetGuardiansBody struct {
+	BaseReq      baseBody         `json:"base_req"`
+	Guardians    []sdk.AccAddress `json:"guardians"`
+	Quorum       int64            `json:"quorum"`
+	Delay        int64            `json:"delay"`
+	Threshold    int64            `json:"threshold"`
+	RemoveOwners bool             `json:"remove_owners"`
+}
+
+type msgRecoverBody struct {
+	BaseReq  baseBody       `json:"base_req"`
+	NewOwner sdk.AccAddress `json:"new_owner"`
+}
+
+type recoveryOutput struct {
+	Config   identity.RecoveryConfig `json:"config"`
+	Recovery *identity.Recovery      `json:"recovery"`
+}
+
+func getRecoveryHandlerFn(ctx context.CLIContext, cdc *wire.Codec) http.HandlerFunc {
+	return withErr(func(w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		ident, err := sdk.AccAddressFromBech32(vars[RestAccount])
+		if err != nil {
+			return err
+		}
+		var output recoveryOutput
+		res, err := ctx.QueryStore(identity.KeyRecoveryConfig(ident), storeName)
+		if err != nil {
+			return err
+		}
+		if len(res) > 0 {
+			if err := cdc.UnmarshalBinary(res, &output.Config); err != nil {
+				return err
+			}
+		}
+		res, err = ctx.QueryStore(identity.KeyRecovery(ident), storeName)
+		if err != nil {
+			return err
+		}
+		if len(res) > 0 {
+			output.Recovery = &identity.Recovery{}
+			if err := cdc.UnmarshalBinary(res, output.Recovery); err != nil {
+				return err
+			}
+		}
+		WriteJSON(w, cdc, output)
+		return nil
+	})
+}
+
+func setGuardiansHandlerFn(ctx context.CLIContext, cdc *wire.Codec, kb keys.Keybase) http.HandlerFunc {
+	return withErr(func(w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		var m msgSetGuardiansBody
+		body, err := ioutil.ReadAll(r.Body)
+		err = cdc.UnmarshalJSON(body, &m)
+		if err != nil {
+			return err
+		}
+		err = m.BaseReq.Validate()
+		if err != nil {
+			return err
+		}
+		sender, err := m.BaseReq.sender(kb)
+		if err != nil {
+			return err
+		}
+		ident, err := sdk.AccAddressFromBech32(vars[RestAccount])
+		if err != nil {
+			return err
+		}
+		msg := identity.NewMsgSetGuardians(sender, ident, identity.RecoveryConfig{
+			Guardians:    m.Guardians,
+			Quorum:       m.Quorum,
+			Delay:        m.Delay,
+			Threshold:    m.Threshold,
+			RemoveOwners: m.RemoveOwners,
+		})
+		if err := msg.ValidateBasic(); err != nil {
+			return err
+		}
+		signAndBuild(ctx, cdc, w, m.BaseReq, msg)
+		return nil
+	})
+}
+
+func recoverHandlerFn(ctx context.CLIContext, cdc *wire.Codec, kb keys.Keybase) http.HandlerFunc {
+	return withErr(func(w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		var m msgRecoverBody
+		body, err := ioutil.ReadAll(r.Body)
+		err = cdc.UnmarshalJSON(body, &m)
+		if err != nil {
+			return err
+		}
+		err = m.BaseReq.Validate()
+		if err != nil {
+			return err
+		}
+		sender, err := m.BaseReq.sender(kb)
+		if err != nil {
+			return err
+		}
+		ident, err := sdk.AccAddressFromBech32(vars[RestAccount])
+		if err != nil {
+			return err
+		}
+		msg := identity.NewMsgRecover(sender, ident, m.NewOwner)
+		if err := msg.ValidateBasic(); err != nil {
+			return err
+		}
+		signAndBuild(ctx, cdc, w, m.BaseReq, msg)
+		return nil
+	})
+}
+
+// recoveryActionHandlerFn signs a veto or the finalization of a recovery
+func recoveryActionHandlerFn(ctx context.CLIContext, cdc *wire.Codec, kb keys.Keybase, finalize bool) http.HandlerFunc {
+	return withErr(func(w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		var m msgActionBody
+		body, err := ioutil.ReadAll(r.Body)
+		err = cdc.UnmarshalJSON(body, &m)
+		if err != nil {
+			return err
+		}
+		err = m.BaseReq.Validate()
+		if err != nil {
+			return err
+		}
+		sender, err := m.BaseReq.sender(kb)
+		if err != nil {
+			return err
+		}
+		ident, err := sdk.AccAddressFromBech32(vars[RestAccount])
+		if err != nil {
+			return err
+		}
+		var msg sdk.Msg = identity.NewMsgVetoRecovery(sender, ident)
+		if finalize {
+			msg = identity.NewMsgFinalizeRecovery(sender, ident)
+		}
+		signAndBuild(ctx, cdc, w, m.BaseReq, msg)
+		return nil
+	})
+}
@@ -18,12 +18,39 @@ const (
 
 // RegisterRoutes REST routes
 func RegisterRoutes(ctx context.CLIContext, r *mux.Router, cdc *wire.Codec, kb keys.Keybase, storeName string) {
+	r.HandleFunc(fmt.Sprintf("/idents/{%s}", RestAccount), getIdentityHandlerFn(ctx, cdc)).Methods("GET")
+	r.HandleFunc(fmt.Sprintf("/idents/{%s}/profile", RestAccount), setProfileHandlerFn(ctx, cdc, kb)).Methods("POST")
 	r.HandleFunc(fmt.Sprintf("/idents/{%s}/trusts", RestAccount), trustsHandlerFn(ctx, cdc)).Methods("GET")
 	r.HandleFunc(fmt.Sprintf("/idents/{%s}/trusts", RestAccount), SetTrustHandlerFn(ctx, cdc, kb)).Methods("POST")
 	r.HandleFunc(fmt.Sprintf("/idents/{%s}/certs", RestAccount), queryCertsHandlerFn(ctx, cdc)).Methods("GET")
+	r.HandleFunc(fmt.Sprintf("/idents/{%s}/trusts/{trusting}/score", RestAccount), trustScoreHandlerFn(ctx, cdc)).Methods("GET")
 	r.HandleFunc(fmt.Sprintf("/idents/{%s}/certs", RestAccount), SetCertsHandlerFn(ctx, cdc, kb)).Methods("POST")
+	r.HandleFunc(fmt.Sprintf("/idents/{%s}/certs/trusted", RestAccount), trustedCertsHandlerFn(ctx, cdc)).Methods("GET")
+	r.HandleFunc(fmt.Sprintf("/idents/{%s}/issued-certs", RestAccount), issuedCertsHandlerFn(ctx, cdc)).Methods("GET")
+	r.HandleFunc(fmt.Sprintf("/idents/{%s}/certs/revoke", RestAccount), revokeCertsHandlerFn(ctx, cdc, kb)).Methods("POST")
+	r.HandleFunc(fmt.Sprintf("/idents/{%s}/certs/{property}/{certifier}/validity", RestAccount), certValidityHandlerFn(ctx, cdc)).Methods("GET")
+	r.HandleFunc(fmt.Sprintf("/idents/{%s}/schemas", RestAccount), queryCertSchemasHandlerFn(ctx, cdc)).Methods("GET")
+	r.HandleFunc(fmt.Sprintf("/idents/{%s}/schemas", RestAccount), setCertSchemaHandlerFn(ctx, cdc, kb)).Methods("POST")
+	r.HandleFunc(fmt.Sprintf("/idents/{%s}/schemas/{property}", RestAccount), queryCertSchemaHandlerFn(ctx, cdc)).Methods("GET")
+	r.HandleFunc(fmt.Sprintf("/idents/{%s}/credentials", RestAccount), queryCredentialsHandlerFn(ctx, cdc)).Methods("GET")
+	r.HandleFunc(fmt.Sprintf("/idents/{%s}/credentials/sign", RestAccount), signCredentialHandlerFn(ctx, cdc, kb)).Methods("POST")
+	r.HandleFunc(fmt.Sprintf("/idents/{%s}/revocations", RestAccount), queryRevocationsHandlerFn(ctx, cdc)).Methods("GET")
 	r.HandleFunc(fmt.Sprintf("/idents/{%s}/register", RestAccount), registerHandlerFn(ctx, cdc, kb)).Methods("POST")
 	r.HandleFunc(fmt.Sprintf("/idents/{%s}/owners", RestAccount), getOwnersHandlerFn(ctx, cdc)).Methods("GET")
 	r.HandleFunc(fmt.Sprintf("/idents/{%s}/owners", RestAccount), addOwnerHandlerFn(ctx, cdc, kb)).Methods("POST")
 	r.HandleFunc(fmt.Sprintf("/idents/{%s}/owners/{owner}", RestAccount), delOwnerHandlerFn(ctx, cdc, kb)).Methods("DELETE")
+	r.HandleFunc(fmt.Sprintf("/idents/{%s}/services", RestAccount), queryServicesHandlerFn(ctx, cdc)).Methods("GET")
+	r.HandleFunc(fmt.Sprintf("/idents/{%s}/services", RestAccount), addServiceHandlerFn(ctx, cdc, kb)).Methods("POST")
+	r.HandleFunc(fmt.Sprintf("/idents/{%s}/services/{id}", RestAccount), delServiceHandlerFn(ctx, cdc, kb)).Methods("DELETE")
+	r.HandleFunc(fmt.Sprintf("/idents/{%s}/threshold", RestAccount), getThresholdHandlerFn(ctx, cdc)).Methods("GET")
+	r.HandleFunc(fmt.Sprintf("/idents/{%s}/threshold", RestAccount), setThresholdHandlerFn(ctx, cdc, kb)).Methods("POST")
+	r.HandleFunc(fmt.Sprintf("/idents/{%s}/actions", RestAccount), getPendingActionsHandlerFn(ctx, cdc)).Methods("GET")
+	r.HandleFunc(fmt.Sprintf("/idents/{%s}/actions/{id}/approve", RestAccount), actionHandlerFn(ctx, cdc, kb, true)).Methods("POST")
+	r.HandleFunc(fmt.Sprintf("/idents/{%s}/actions/{id}/cancel", RestAccount), actionHandlerFn(ctx, cdc, kb, false)).Methods("POST")
+	r.HandleFunc(fmt.Sprintf("/idents/{%s}/guardians", RestAccount), setGuardiansHandlerFn(ctx, cdc, kb)).Methods("POST")
+	r.HandleFunc(fmt.Sprintf("/idents/{%s}/recovery", RestAccount), getRecoveryHandlerFn(ctx, cdc)).Methods("GET")
+	r.HandleFunc(fmt.Sprintf("/idents/{%s}/recovery", RestAccount), recoverHandlerFn(ctx, cdc, kb)).Methods("POST")
+	r.HandleFunc(fmt.Sprintf("/idents/{%s}/recovery/veto", RestAccount), recoveryActionHandlerFn(ctx, cdc, kb, false)).Methods("POST")
+	r.HandleFunc(fmt.Sprintf("/idents/{%s}/recovery/finalize", RestAccount), recoveryActionHandlerFn(ctx, cdc, kb, true)).Methods("POST")
+	r.HandleFunc("/dids/{did}", resolveDIDHandlerFn(ctx, cdc)).Methods("GET")
 }
@@ -0,0 +1,93 @@
+package rest
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/cosmos/cosmos-sdk/crypto/keys"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+
+	"github.com/icheckteam/ichain/x/identity"
+	identityclient "github.com/icheckteam/ichain/x/identity/client"
+)
+
+type msgSetCertSchemaBody struct {
+	BaseReq  baseBody          `json:"base_req"`
+	Property string            `json:"property"`
+	Schema   identity.Metadata `json:"schema"`
+}
+
+// queryCertSchemasHandlerFn returns all cert schemas of a certifier
+func queryCertSchemasHandlerFn(ctx context.CLIContext, cdc *wire.Codec) http.HandlerFunc {
+	return withErr(func(w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		certifier, err := sdk.AccAddressFromBech32(vars[RestAccount])
+		if err != nil {
+			return err
+		}
+		schemas, err := identityclient.QueryCertSchemas(ctx, cdc, certifier)
+		if err != nil {
+			return err
+		}
+		WriteJSON(w, cdc, schemas)
+		return nil
+	})
+}
+
+// queryCertSchemaHandlerFn returns the bare JSON schema of a property so
+// wallets can use it to render the cert data
+func queryCertSchemaHandlerFn(ctx context.CLIContext, cdc *wire.Codec) http.HandlerFunc {
+	return withErr(func(w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		certifier, err := sdk.AccAddressFromBech32(vars[RestAccount])
+		if err != nil {
+			return err
+		}
+		schema, found, err := identityclient.QueryCertSchema(ctx, cdc, certifier, vars["property"])
+		if err != nil {
+			return err
+		}
+		if !found {
+			w.WriteHeader(http.StatusNotFound)
+			return fmt.Errorf("no schema for %s", vars["property"])
+		}
+		w.Header().Set("Content-Type", "application/schema+json")
+		w.Write(schema.Schema)
+		return nil
+	})
+}
+
+func setCertSchemaHandlerFn(ctx context.CLIContext, cdc *wire.Codec, kb keys.Keybase) http.HandlerFunc {
+	return withErr(func(w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		var m msgSetCertSchemaBody
+		body, err := ioutil.ReadAll(r.Body)
+		err = cdc.UnmarshalJSON(body, &m)
+		if err != nil {
+			return err
+		}
+		err = m.BaseReq.Validate()
+		if err != nil {
+			return err
+		}
+		sender, err := m.BaseReq.sender(kb)
+		if err != nil {
+			return err
+		}
+		issuer, err := sdk.AccAddressFromBech32(vars[RestAccount])
+		if err != nil {
+			return err
+		}
+		msg := identity.NewMsgSetCertSchema(sender, issuer, m.Property, m.Schema)
+		if err := msg.ValidateBasic(); err != nil {
+			return err
+		}
+		signAndBuild(ctx, cdc, w, m.BaseReq, msg)
+		return nil
+	})
+}
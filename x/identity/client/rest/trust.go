@@ -0,0 +1,122 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+
+	"github.com/icheckteam/ichain/x/identity"
+	identityclient "github.com/icheckteam/ichain/x/identity/client"
+)
+
+const (
+	defaultTrustHops  = 3
+	maxTrustHops      = 6
+	defaultTrustDecay = 0.5
+)
+
+// trustParams reads the hops, decay and min_score query parameters
+func trustParams(r *http.Request) (hops int, decay, minScore float64, err error) {
+	hops, decay = defaultTrustHops, defaultTrustDecay
+	query := r.URL.Query()
+	if s := query.Get("hops"); s != "" {
+		hops, err = strconv.Atoi(s)
+		if err != nil {
+			return
+		}
+		if hops < 1 || hops > maxTrustHops {
+			err = fmt.Errorf("hops must be between 1 and %d", maxTrustHops)
+			return
+		}
+	}
+	if s := query.Get("decay"); s != "" {
+		decay, err = strconv.ParseFloat(s, 64)
+		if err != nil {
+			return
+		}
+		if decay <= 0 || decay > 1 {
+			err = fmt.Errorf("decay must be in (0, 1]")
+			return
+		}
+	}
+	if s := query.Get("min_score"); s != "" {
+		minScore, err = strconv.ParseFloat(s, 64)
+		if err != nil {
+			return
+		}
+		if minScore < 0 || minScore > 1 {
+			err = fmt.Errorf("min_score must be in [0, 1]")
+			return
+		}
+	}
+	return
+}
+
+// trustScoreHandlerFn tells whether the address trusts the trusting
+// account through paths of up to hops edges
+func trustScoreHandlerFn(ctx context.CLIContext, cdc *wire.Codec) http.HandlerFunc {
+	return withErr(func(w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		trustor, err := sdk.AccAddressFromBech32(vars[RestAccount])
+		if err != nil {
+			return err
+		}
+		trusting, err := sdk.AccAddressFromBech32(vars["trusting"])
+		if err != nil {
+			return err
+		}
+		hops, decay, _, err := trustParams(r)
+		if err != nil {
+			return err
+		}
+		result, err := identity.ComputeTrust(identityclient.QueryTrustGraph(ctx), trustor, trusting, hops, decay)
+		if err != nil {
+			return err
+		}
+		return writeFloatJSON(w, result)
+	})
+}
+
+// trustedCertsHandlerFn returns the certs of the address issued by
+// certifiers the viewer trusts transitively
+func trustedCertsHandlerFn(ctx context.CLIContext, cdc *wire.Codec) http.HandlerFunc {
+	return withErr(func(w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		owner, err := sdk.AccAddressFromBech32(vars[RestAccount])
+		if err != nil {
+			return err
+		}
+		viewer, err := sdk.AccAddressFromBech32(r.URL.Query().Get("viewer"))
+		if err != nil {
+			return err
+		}
+		hops, decay, minScore, err := trustParams(r)
+		if err != nil {
+			return err
+		}
+		certs, err := identityclient.QueryTrustedCerts(ctx, cdc, owner, viewer, hops, decay, minScore, time.Now().Unix())
+		if err != nil {
+			return err
+		}
+		return writeFloatJSON(w, certs)
+	})
+}
+
+// writeFloatJSON uses encoding/json, amino does not encode the scores
+func writeFloatJSON(w http.ResponseWriter, data interface{}) error {
+	output, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(output)
+	return nil
+}
@@ -1,6 +1,8 @@
 package rest
 
 import (
+	"bytes"
+	"fmt"
 	"io/ioutil"
 	"net/http"
 
@@ -34,7 +36,7 @@ func SetTrustHandlerFn(ctx context.CLIContext, cdc *wire.Codec, kb keys.Keybase)
 			return
 		}
 
-		info, err := kb.Get(m.BaseReq.Name)
+		sender, err := m.BaseReq.sender(kb)
 		if err != nil {
 			w.WriteHeader(http.StatusUnauthorized)
 			w.Write([]byte(err.Error()))
@@ -51,7 +53,7 @@ func SetTrustHandlerFn(ctx context.CLIContext, cdc *wire.Codec, kb keys.Keybase)
 		msg := identity.MsgSetTrust{
 			Trust:    m.Trust,
 			Trusting: trusting,
-			Trustor:  sdk.AccAddress(info.GetPubKey().Address()),
+			Trustor:  sender,
 		}
 
 		signAndBuild(ctx, cdc, w, m.BaseReq, msg)
@@ -79,7 +81,7 @@ func SetCertsHandlerFn(ctx context.CLIContext, cdc *wire.Codec, kb keys.Keybase)
 			return
 		}
 
-		info, err := kb.Get(m.BaseReq.Name)
+		sender, err := m.BaseReq.sender(kb)
 		if err != nil {
 			w.WriteHeader(http.StatusUnauthorized)
 			w.Write([]byte(err.Error()))
@@ -97,10 +99,9 @@ func SetCertsHandlerFn(ctx context.CLIContext, cdc *wire.Codec, kb keys.Keybase)
 		for i := range m.Values {
 			m.Values[i].Owner = address
 		}
-		certifier := sdk.AccAddress(info.GetPubKey().Address())
 		msg := identity.MsgSetCerts{
-			Sender: certifier,
-			Issuer: certifier,
+			Sender: sender,
+			Issuer: sender,
 			Values: m.Values,
 		}
 		signAndBuild(ctx, cdc, w, m.BaseReq, msg)
@@ -120,7 +121,7 @@ func registerHandlerFn(ctx context.CLIContext, cdc *wire.Codec, kb keys.Keybase)
 		if err != nil {
 			return err
 		}
-		info, err := kb.Get(m.BaseReq.Name)
+		sender, err := m.BaseReq.sender(kb)
 		if err != nil {
 			return err
 		}
@@ -128,9 +129,12 @@ func registerHandlerFn(ctx context.CLIContext, cdc *wire.Codec, kb keys.Keybase)
 		if err != nil {
 			return err
 		}
+		// an account only registers its own address
+		if !bytes.Equal(ident, sender) {
+			return fmt.Errorf("%s can only be registered by its own key, not %s", ident, sender)
+		}
 		msg := identity.MsgReg{
-			Sender: sdk.AccAddress(info.GetPubKey().Address()),
-			Ident:  ident,
+			Sender: sender,
 		}
 		signAndBuild(ctx, cdc, w, m.BaseReq, msg)
 		return nil
@@ -150,7 +154,7 @@ func addOwnerHandlerFn(ctx context.CLIContext, cdc *wire.Codec, kb keys.Keybase)
 		if err != nil {
 			return err
 		}
-		info, err := kb.Get(m.BaseReq.Name)
+		sender, err := m.BaseReq.sender(kb)
 		if err != nil {
 			return err
 		}
@@ -159,7 +163,7 @@ func addOwnerHandlerFn(ctx context.CLIContext, cdc *wire.Codec, kb keys.Keybase)
 			return err
 		}
 		msg := identity.MsgAddOwner{
-			Sender: sdk.AccAddress(info.GetPubKey().Address()),
+			Sender: sender,
 			Ident:  ident,
 			Owner:  m.Owner,
 		}
@@ -181,7 +185,7 @@ func delOwnerHandlerFn(ctx context.CLIContext, cdc *wire.Codec, kb keys.Keybase)
 		if err != nil {
 			return err
 		}
-		info, err := kb.Get(m.BaseReq.Name)
+		sender, err := m.BaseReq.sender(kb)
 		if err != nil {
 			return err
 		}
@@ -194,7 +198,7 @@ func delOwnerHandlerFn(ctx context.CLIContext, cdc *wire.Codec, kb keys.Keybase)
 			return err
 		}
 		msg := identity.MsgDelOwner{
-			Sender: sdk.AccAddress(info.GetPubKey().Address()),
+			Sender: sender,
 			Ident:  ident,
 			Owner:  owner,
 		}
@@ -202,3 +206,39 @@ func delOwnerHandlerFn(ctx context.CLIContext, cdc *wire.Codec, kb keys.Keybase)
 		return nil
 	})
 }
+
+func revokeCertsHandlerFn(ctx context.CLIContext, cdc *wire.Codec, kb keys.Keybase) http.HandlerFunc {
+	return withErr(func(w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		var m msgRevokeCertsBody
+		body, err := ioutil.ReadAll(r.Body)
+		err = cdc.UnmarshalJSON(body, &m)
+		if err != nil {
+			return err
+		}
+		err = m.BaseReq.Validate()
+		if err != nil {
+			return err
+		}
+		sender, err := m.BaseReq.sender(kb)
+		if err != nil {
+			return err
+		}
+		owner, err := sdk.AccAddressFromBech32(vars[RestAccount])
+		if err != nil {
+			return err
+		}
+		revocations := make([]identity.CertRevocation, len(m.Revocations))
+		for i, revocation := range m.Revocations {
+			revocations[i] = identity.CertRevocation{
+				Owner:    owner,
+				Property: revocation.Property,
+				Reason:   revocation.Reason,
+			}
+		}
+		certifier := sender
+		msg := identity.NewMsgRevokeCerts(certifier, certifier, revocations)
+		signAndBuild(ctx, cdc, w, m.BaseReq, msg)
+		return nil
+	})
+}
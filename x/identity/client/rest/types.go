@@ -5,9 +5,11 @@ import (
 	"net/http"
 
 	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/cosmos/cosmos-sdk/crypto/keys"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/wire"
 	authctx "github.com/cosmos/cosmos-sdk/x/auth/client/context"
+	"github.com/icheckteam/ichain/client/tx"
 	"github.com/icheckteam/ichain/x/identity"
 )
 
@@ -19,14 +21,31 @@ type baseBody struct {
 	AccountNumber int64  `json:"account_number"`
 	Gas           int64  `json:"gas"`
 	Memo          string `json:"memo"`
+
+	// GenerateOnly returns the unsigned tx instead of signing it with the
+	// keybase of the LCD, From is then the address of the signer
+	GenerateOnly bool   `json:"generate_only"`
+	From         string `json:"from"`
+
+	// Mode is the broadcast mode, sync, async or block by default
+	Mode string `json:"mode"`
 }
 
 func (b baseBody) Validate() error {
-	if b.Name == "" {
-		return errors.New("name required but not specified")
+	if b.GenerateOnly {
+		if b.From == "" && b.Name == "" {
+			return errors.New("from required but not specified")
+		}
+	} else {
+		if b.Name == "" {
+			return errors.New("name required but not specified")
+		}
+		if b.Password == "" {
+			return errors.New("password required but not specified")
+		}
 	}
-	if b.Password == "" {
-		return errors.New("password required but not specified")
+	if err := tx.ValidateBroadcastMode(b.Mode); err != nil {
+		return err
 	}
 	if b.Gas == 0 {
 		return errors.New("gas required but not specified")
@@ -44,6 +63,19 @@ func (b baseBody) Validate() error {
 	return nil
 }
 
+// sender is the address signing the tx, the key Name of the LCD keybase
+// or From in generate_only mode
+func (b baseBody) sender(kb keys.Keybase) (sdk.AccAddress, error) {
+	if b.GenerateOnly && b.From != "" {
+		return sdk.AccAddressFromBech32(b.From)
+	}
+	info, err := kb.Get(b.Name)
+	if err != nil {
+		return nil, err
+	}
+	return sdk.AccAddress(info.GetPubKey().Address()), nil
+}
+
 type msgRegBody struct {
 	BaseReq baseBody `json:"base_req"`
 }
@@ -68,6 +100,10 @@ func signAndBuild(ctx context.CLIContext, cdc *wire.Codec, w http.ResponseWriter
 		Sequence:      m.Sequence,
 		Memo:          m.Memo,
 	}
+	if m.GenerateOnly {
+		tx.WriteGenerateOnlyResponse(w, cdc, txCtx, []sdk.Msg{msg})
+		return
+	}
 
 	txBytes, err := txCtx.BuildAndSign(m.Name, m.Password, []sdk.Msg{msg})
 	if err != nil {
@@ -77,21 +113,7 @@ func signAndBuild(ctx context.CLIContext, cdc *wire.Codec, w http.ResponseWriter
 	}
 
 	// send
-	res, err := ctx.BroadcastTx(txBytes)
-	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		w.Write([]byte("BroadcastTx:" + err.Error()))
-		return
-	}
-
-	output, err := wire.MarshalJSONIndent(cdc, res)
-	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		w.Write([]byte(err.Error()))
-		return
-	}
-
-	w.Write(output)
+	tx.WriteBroadcastResponse(w, cdc, ctx, txBytes, m.Mode)
 }
 
 type msgSetTrustBody struct {
@@ -108,3 +130,20 @@ type msgSetCertsBody struct {
 	BaseReq baseBody             `json:"base_req"`
 	Values  []identity.CertValue `json:"values"`
 }
+
+type msgRevokeCertsBody struct {
+	BaseReq     baseBody             `json:"base_req"`
+	Revocations []certRevocationBody `json:"revocations"`
+}
+
+type certRevocationBody struct {
+	Property string `json:"property"`
+	Reason   string `json:"reason"`
+}
+
+type certValidityOutput struct {
+	Status string         `json:"status"`
+	Valid  bool           `json:"valid"`
+	At     int64          `json:"at"`
+	Cert   *identity.Cert `json:"cert"`
+}
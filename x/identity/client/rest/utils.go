@@ -8,6 +8,7 @@ import (
 	"github.com/cosmos/cosmos-sdk/wire"
 	"github.com/icheckteam/ichain/client/errors"
 	"github.com/icheckteam/ichain/x/identity"
+	identityclient "github.com/icheckteam/ichain/x/identity/client"
 )
 
 // WriteJSON ...
@@ -62,17 +63,40 @@ func getCerts(ctx context.CLIContext, ident sdk.AccAddress, cdc *wire.Codec) (id
 	return certs, nil
 }
 
-func getTrusts(ctx context.CLIContext, ident sdk.AccAddress, cdc *wire.Codec) ([]sdk.AccAddress, error) {
-	prefix := identity.KeyTrusts(ident)
-	kvs, err := ctx.QuerySubspace(prefix, storeName)
+func getCert(ctx context.CLIContext, ident sdk.AccAddress, property string, certifier sdk.AccAddress, cdc *wire.Codec) (cert identity.Cert, found bool, err error) {
+	res, err := ctx.QueryStore(identity.KeyCert(ident, property, certifier), storeName)
+	if err != nil || len(res) == 0 {
+		return
+	}
+	cert, err = identity.UnmarshalCert(cdc, res)
+	return cert, err == nil, err
+}
+
+func filterValidCerts(certs identity.Certs, at int64) identity.Certs {
+	valid := identity.Certs{}
+	for _, cert := range certs {
+		if cert.IsValidAt(at) {
+			valid = append(valid, cert)
+		}
+	}
+	return valid
+}
+
+func getRevocations(ctx context.CLIContext, ident sdk.AccAddress, cdc *wire.Codec) ([]identity.Revocation, error) {
+	kvs, err := ctx.QuerySubspace(identity.KeyRevocations(ident), storeName)
 	if err != nil {
 		return nil, err
 	}
-	accounts := make([]sdk.AccAddress, len(kvs))
-	var index = 0
-	for _, kv := range kvs {
-		accounts[index] = sdk.AccAddress(kv.Key[1+sdk.AddrLen:])
-		index++
+	revocations := make([]identity.Revocation, len(kvs))
+	for i, kv := range kvs {
+		revocations[i], err = identity.UnmarshalRevocation(cdc, kv.Value)
+		if err != nil {
+			return nil, err
+		}
 	}
-	return accounts[:index], nil
+	return revocations, nil
+}
+
+func getTrusts(ctx context.CLIContext, ident sdk.AccAddress, cdc *wire.Codec) ([]sdk.AccAddress, error) {
+	return identityclient.QueryTrusting(ctx, ident)
 }
@@ -0,0 +1,34 @@
+package client
+
+import (
+	"github.com/cosmos/cosmos-sdk/client/context"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+
+	"github.com/icheckteam/ichain/x/identity"
+)
+
+// QueryCertSchema returns the schema a certifier registered for a property
+func QueryCertSchema(ctx context.CLIContext, cdc *wire.Codec, certifier sdk.AccAddress, property string) (schema identity.CertSchema, found bool, err error) {
+	res, err := ctx.QueryStore(identity.KeyCertSchema(certifier, property), storeName)
+	if err != nil || len(res) == 0 {
+		return
+	}
+	schema, err = identity.UnmarshalCertSchema(cdc, res)
+	return schema, err == nil, err
+}
+
+// QueryCertSchemas returns all schemas registered by a certifier
+func QueryCertSchemas(ctx context.CLIContext, cdc *wire.Codec, certifier sdk.AccAddress) ([]identity.CertSchema, error) {
+	kvs, err := ctx.QuerySubspace(identity.KeyCertSchemas(certifier), storeName)
+	if err != nil {
+		return nil, err
+	}
+	schemas := make([]identity.CertSchema, len(kvs))
+	for i, kv := range kvs {
+		if schemas[i], err = identity.UnmarshalCertSchema(cdc, kv.Value); err != nil {
+			return nil, err
+		}
+	}
+	return schemas, nil
+}
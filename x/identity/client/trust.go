@@ -0,0 +1,66 @@
+package client
+
+import (
+	"github.com/cosmos/cosmos-sdk/client/context"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+
+	"github.com/icheckteam/ichain/x/identity"
+)
+
+// TrustedCert is a cert with the trust of the viewer in its certifier
+type TrustedCert struct {
+	identity.Cert
+	Trust identity.TrustResult `json:"trust"`
+}
+
+// QueryTrusting returns the accounts directly trusted by trustor
+func QueryTrusting(ctx context.CLIContext, trustor sdk.AccAddress) ([]sdk.AccAddress, error) {
+	kvs, err := querySubspace(ctx, identity.KeyTrusts(trustor))
+	if err != nil {
+		return nil, err
+	}
+	trusting := make([]sdk.AccAddress, len(kvs))
+	for i, kv := range kvs {
+		trusting[i] = identity.TrustingFromKey(trustor, kv.Key)
+	}
+	return trusting, nil
+}
+
+// QueryTrustGraph returns the trust graph of the node, each account is
+// queried once
+func QueryTrustGraph(ctx context.CLIContext) identity.TrustGraph {
+	return identity.CachedTrustGraph(func(trustor sdk.AccAddress) ([]sdk.AccAddress, error) {
+		return QueryTrusting(ctx, trustor)
+	})
+}
+
+// QueryTrustedCerts returns the certs of owner as seen by viewer, only the
+// certs valid at now whose certifier is trusted with at least minScore
+// within maxHops
+func QueryTrustedCerts(ctx context.CLIContext, cdc *wire.Codec, owner, viewer sdk.AccAddress, maxHops int, decay, minScore float64, now int64) ([]TrustedCert, error) {
+	kvs, err := ctx.QuerySubspace(identity.KeyCerts(owner), storeName)
+	if err != nil {
+		return nil, err
+	}
+	graph := QueryTrustGraph(ctx)
+	certs := []TrustedCert{}
+	for _, kv := range kvs {
+		cert, err := identity.UnmarshalCert(cdc, kv.Value)
+		if err != nil {
+			return nil, err
+		}
+		if !cert.IsValidAt(now) {
+			continue
+		}
+		trust, err := identity.ComputeTrust(graph, viewer, cert.Certifier, maxHops, decay)
+		if err != nil {
+			return nil, err
+		}
+		if !trust.Trusted || trust.Score < minScore {
+			continue
+		}
+		certs = append(certs, TrustedCert{Cert: cert, Trust: trust})
+	}
+	return certs, nil
+}
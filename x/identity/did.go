@@ -0,0 +1,32 @@
+package identity
+
+import (
+	"fmt"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// DID method of the ichain identities, did:ichain:<bech32 address>
+const (
+	DIDMethod = "ichain"
+	DIDPrefix = "did:" + DIDMethod + ":"
+)
+
+// DID returns the decentralized identifier of an identity
+func DID(addr sdk.AccAddress) string {
+	return DIDPrefix + addr.String()
+}
+
+// ParseDID returns the identity address of a did:ichain identifier,
+// fragments and query of a DID URL are ignored
+func ParseDID(did string) (sdk.AccAddress, error) {
+	if !strings.HasPrefix(did, DIDPrefix) {
+		return nil, fmt.Errorf("%s is not a did:%s identifier", did, DIDMethod)
+	}
+	id := strings.TrimPrefix(did, DIDPrefix)
+	if i := strings.IndexAny(id, "#?/;"); i >= 0 {
+		id = id[:i]
+	}
+	return sdk.AccAddressFromBech32(id)
+}
@@ -20,6 +20,28 @@ var (
 	CodeInvalidTrusting sdk.CodeType = 4
 	// CodeInvalidInput ...
 	CodeInvalidInput sdk.CodeType = 5
+	// CodeCertNotFound ...
+	CodeCertNotFound sdk.CodeType = 6
+	// CodeServiceNotFound ...
+	CodeServiceNotFound sdk.CodeType = 7
+	// CodeActionNotFound ...
+	CodeActionNotFound sdk.CodeType = 8
+	// CodeInvalidThreshold ...
+	CodeInvalidThreshold sdk.CodeType = 9
+	// CodeLastOwner ...
+	CodeLastOwner sdk.CodeType = 10
+	// CodeInvalidRecovery ...
+	CodeInvalidRecovery sdk.CodeType = 11
+	// CodeRecoveryNotFound ...
+	CodeRecoveryNotFound sdk.CodeType = 12
+	// CodeInvalidProfile ...
+	CodeInvalidProfile sdk.CodeType = 13
+	// CodeInvalidSchema ...
+	CodeInvalidSchema sdk.CodeType = 14
+	// CodeInvalidCertData ...
+	CodeInvalidCertData sdk.CodeType = 15
+	// CodeCertRevoked ...
+	CodeCertRevoked sdk.CodeType = 16
 )
 
 //----------------------------------------
@@ -39,3 +61,53 @@ func ErrNilTrustorAddr(codespace sdk.CodespaceType) sdk.Error {
 func ErrNilTrustingAddr(codespace sdk.CodespaceType) sdk.Error {
 	return sdk.NewError(codespace, CodeInvalidTrusting, "trusting address is nil")
 }
+
+// ErrCertNotFound ...
+func ErrCertNotFound(codespace sdk.CodespaceType, owner sdk.AccAddress, property string) sdk.Error {
+	return sdk.NewError(codespace, CodeCertNotFound, fmt.Sprintf("cert %s of %s not found or already revoked", property, owner))
+}
+
+// ErrServiceNotFound ...
+func ErrServiceNotFound(codespace sdk.CodespaceType, id sdk.AccAddress, serviceID string) sdk.Error {
+	return sdk.NewError(codespace, CodeServiceNotFound, fmt.Sprintf("service %s of %s not found", serviceID, id))
+}
+
+// ErrActionNotFound ...
+func ErrActionNotFound(codespace sdk.CodespaceType, id sdk.AccAddress, actionID int64) sdk.Error {
+	return sdk.NewError(codespace, CodeActionNotFound, fmt.Sprintf("action %d of %s not found", actionID, id))
+}
+
+// ErrInvalidThreshold ...
+func ErrInvalidThreshold(codespace sdk.CodespaceType, threshold, ownerCount int64) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidThreshold, fmt.Sprintf("threshold %d exceeds the %d owners", threshold, ownerCount))
+}
+
+// ErrLastOwner ...
+func ErrLastOwner(codespace sdk.CodespaceType, id sdk.AccAddress) sdk.Error {
+	return sdk.NewError(codespace, CodeLastOwner, fmt.Sprintf("can't delete the last owner of %s", id))
+}
+
+// ErrRecoveryNotFound ...
+func ErrRecoveryNotFound(codespace sdk.CodespaceType, id sdk.AccAddress) sdk.Error {
+	return sdk.NewError(codespace, CodeRecoveryNotFound, fmt.Sprintf("no recovery of %s in progress", id))
+}
+
+// ErrInvalidProfile ...
+func ErrInvalidProfile(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidProfile, "invalid profile: "+msg)
+}
+
+// ErrInvalidSchema ...
+func ErrInvalidSchema(codespace sdk.CodespaceType, property, msg string) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidSchema, fmt.Sprintf("invalid schema of %s: %s", property, msg))
+}
+
+// ErrInvalidCertData ...
+func ErrInvalidCertData(codespace sdk.CodespaceType, property, msg string) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidCertData, fmt.Sprintf("data of %s doesn't match its schema: %s", property, msg))
+}
+
+// ErrCertRevoked ...
+func ErrCertRevoked(codespace sdk.CodespaceType, owner sdk.AccAddress, property string) sdk.Error {
+	return sdk.NewError(codespace, CodeCertRevoked, fmt.Sprintf("cert %s of %s was revoked and can't be issued again", property, owner))
+}
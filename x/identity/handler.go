@@ -13,6 +13,8 @@ func NewHandler(k Keeper) sdk.Handler {
 		switch msg := msg.(type) {
 		case MsgSetCerts:
 			return handleSetCerts(ctx, k, msg)
+		case MsgRevokeCerts:
+			return handleRevokeCerts(ctx, k, msg)
 		case MsgSetTrust:
 			return handleSetTrust(ctx, k, msg)
 		case MsgReg:
@@ -21,6 +23,28 @@ func NewHandler(k Keeper) sdk.Handler {
 			return handleAddOwner(ctx, k, msg)
 		case MsgDelOwner:
 			return handleDelOwner(ctx, k, msg)
+		case MsgAddService:
+			return handleAddService(ctx, k, msg)
+		case MsgDelService:
+			return handleDelService(ctx, k, msg)
+		case MsgSetThreshold:
+			return handleSetThreshold(ctx, k, msg)
+		case MsgApproveAction:
+			return handleApproveAction(ctx, k, msg)
+		case MsgCancelAction:
+			return handleCancelAction(ctx, k, msg)
+		case MsgSetGuardians:
+			return handleSetGuardians(ctx, k, msg)
+		case MsgRecover:
+			return handleRecover(ctx, k, msg)
+		case MsgVetoRecovery:
+			return handleVetoRecovery(ctx, k, msg)
+		case MsgFinalizeRecovery:
+			return handleFinalizeRecovery(ctx, k, msg)
+		case MsgSetProfile:
+			return handleSetProfile(ctx, k, msg)
+		case MsgSetCertSchema:
+			return handleSetCertSchema(ctx, k, msg)
 		default:
 			errMsg := fmt.Sprintf("Unrecognized trace Msg type: %v", reflect.TypeOf(msg).Name())
 			return sdk.ErrUnknownRequest(errMsg).Result()
@@ -50,6 +74,12 @@ func handleSetCerts(ctx sdk.Context, k Keeper, msg MsgSetCerts) sdk.Result {
 	})
 }
 
+func handleRevokeCerts(ctx sdk.Context, k Keeper, msg MsgRevokeCerts) sdk.Result {
+	return mapKeeperToHandler(func() (sdk.Tags, sdk.Error) {
+		return k.RevokeCerts(ctx, msg)
+	})
+}
+
 func handleRegister(ctx sdk.Context, k Keeper, msg MsgReg) sdk.Result {
 	return mapKeeperToHandler(func() (sdk.Tags, sdk.Error) {
 		return k.Register(ctx, msg)
@@ -67,3 +97,69 @@ func handleDelOwner(ctx sdk.Context, k Keeper, msg MsgDelOwner) sdk.Result {
 		return k.DeleteOwner(ctx, msg)
 	})
 }
+
+func handleAddService(ctx sdk.Context, k Keeper, msg MsgAddService) sdk.Result {
+	return mapKeeperToHandler(func() (sdk.Tags, sdk.Error) {
+		return k.AddService(ctx, msg)
+	})
+}
+
+func handleDelService(ctx sdk.Context, k Keeper, msg MsgDelService) sdk.Result {
+	return mapKeeperToHandler(func() (sdk.Tags, sdk.Error) {
+		return k.DeleteService(ctx, msg)
+	})
+}
+
+func handleSetThreshold(ctx sdk.Context, k Keeper, msg MsgSetThreshold) sdk.Result {
+	return mapKeeperToHandler(func() (sdk.Tags, sdk.Error) {
+		return k.SetThreshold(ctx, msg)
+	})
+}
+
+func handleApproveAction(ctx sdk.Context, k Keeper, msg MsgApproveAction) sdk.Result {
+	return mapKeeperToHandler(func() (sdk.Tags, sdk.Error) {
+		return k.ApproveAction(ctx, msg)
+	})
+}
+
+func handleCancelAction(ctx sdk.Context, k Keeper, msg MsgCancelAction) sdk.Result {
+	return mapKeeperToHandler(func() (sdk.Tags, sdk.Error) {
+		return k.CancelAction(ctx, msg)
+	})
+}
+
+func handleSetGuardians(ctx sdk.Context, k Keeper, msg MsgSetGuardians) sdk.Result {
+	return mapKeeperToHandler(func() (sdk.Tags, sdk.Error) {
+		return k.SetGuardians(ctx, msg)
+	})
+}
+
+func handleRecover(ctx sdk.Context, k Keeper, msg MsgRecover) sdk.Result {
+	return mapKeeperToHandler(func() (sdk.Tags, sdk.Error) {
+		return k.Recover(ctx, msg)
+	})
+}
+
+func handleVetoRecovery(ctx sdk.Context, k Keeper, msg MsgVetoRecovery) sdk.Result {
+	return mapKeeperToHandler(func() (sdk.Tags, sdk.Error) {
+		return k.VetoRecovery(ctx, msg)
+	})
+}
+
+func handleFinalizeRecovery(ctx sdk.Context, k Keeper, msg MsgFinalizeRecovery) sdk.Result {
+	return mapKeeperToHandler(func() (sdk.Tags, sdk.Error) {
+		return k.FinalizeRecovery(ctx, msg)
+	})
+}
+
+func handleSetProfile(ctx sdk.Context, k Keeper, msg MsgSetProfile) sdk.Result {
+	return mapKeeperToHandler(func() (sdk.Tags, sdk.Error) {
+		return k.SetProfile(ctx, msg)
+	})
+}
+
+func handleSetCertSchema(ctx sdk.Context, k Keeper, msg MsgSetCertSchema) sdk.Result {
+	return mapKeeperToHandler(func() (sdk.Tags, sdk.Error) {
+		return k.SetCertSchema(ctx, msg)
+	})
+}
@@ -6,24 +6,39 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 )
 
-// Register register an identity
+// Register register the address of the sender as an identity, the sender
+// is its first owner and can add others
 func (k Keeper) Register(ctx sdk.Context, msg MsgReg) (sdk.Tags, sdk.Error) {
-	ownerCount := k.getOwnerCount(ctx, msg.Ident)
+	ownerCount := k.getOwnerCount(ctx, msg.Sender)
 
 	if ownerCount > 0 {
-		return nil, ErrIDAlreadyExists(DefaultCodespace, msg.Ident)
+		return nil, ErrIDAlreadyExists(DefaultCodespace, msg.Sender)
 	}
 
 	// store data
-	k.setOwnerCount(ctx, msg.Ident, 1)
-	k.setOwner(ctx, msg.Ident, msg.Sender)
+	k.setOwnerCount(ctx, msg.Sender, 1)
+	k.setOwner(ctx, msg.Sender, msg.Sender)
+	store := ctx.KVStore(k.storeKey)
+	store.Set(KeyRegistration(msg.Sender), k.cdc.MustMarshalBinary(ctx.BlockHeight()))
 	return nil, nil
 }
 
+// IsRegistered returns whether the identity was registered by the key of
+// its address. The identities registered for another address before the
+// registration had to be signed by it aren't.
+func (k Keeper) IsRegistered(ctx sdk.Context, id sdk.AccAddress) bool {
+	store := ctx.KVStore(k.storeKey)
+	return store.Has(KeyRegistration(id))
+}
+
 // AddOwner add an account to identity
 func (k Keeper) AddOwner(ctx sdk.Context, msg MsgAddOwner) (sdk.Tags, sdk.Error) {
-	if !k.hasOwner(ctx, msg.Ident, msg.Sender) {
-		return nil, sdk.ErrUnauthorized(fmt.Sprintf("addr %s unauthorized", msg.Sender))
+	return k.authorize(ctx, msg.Ident, msg.Sender, msg)
+}
+
+func (k Keeper) addOwner(ctx sdk.Context, msg MsgAddOwner) (sdk.Tags, sdk.Error) {
+	if k.hasOwner(ctx, msg.Ident, msg.Owner) {
+		return nil, sdk.NewError(k.codespace, CodeInvalidInput, fmt.Sprintf("addr %s is already an owner", msg.Owner))
 	}
 	ownerCount := k.getOwnerCount(ctx, msg.Ident)
 	k.setOwnerCount(ctx, msg.Ident, ownerCount+1)
@@ -33,15 +48,31 @@ func (k Keeper) AddOwner(ctx sdk.Context, msg MsgAddOwner) (sdk.Tags, sdk.Error)
 
 // DeleteOwner delete an account of identity
 func (k Keeper) DeleteOwner(ctx sdk.Context, msg MsgDelOwner) (sdk.Tags, sdk.Error) {
-	if !k.hasOwner(ctx, msg.Ident, msg.Sender) {
-		return nil, sdk.ErrUnauthorized(fmt.Sprintf("addr %s unauthorized", msg.Sender))
+	return k.authorize(ctx, msg.Ident, msg.Sender, msg)
+}
+
+func (k Keeper) deleteOwner(ctx sdk.Context, msg MsgDelOwner) (sdk.Tags, sdk.Error) {
+	if !k.hasOwner(ctx, msg.Ident, msg.Owner) {
+		return nil, sdk.NewError(k.codespace, CodeInvalidInput, fmt.Sprintf("addr %s is not an owner", msg.Owner))
 	}
 	ownerCount := k.getOwnerCount(ctx, msg.Ident)
+	if ownerCount <= 1 {
+		return nil, ErrLastOwner(k.codespace, msg.Ident)
+	}
+	if threshold := k.GetThreshold(ctx, msg.Ident); ownerCount-1 < threshold {
+		return nil, ErrInvalidThreshold(k.codespace, threshold, ownerCount-1)
+	}
 	k.setOwnerCount(ctx, msg.Ident, ownerCount-1)
 	k.delOwner(ctx, msg.Ident, msg.Owner)
 	return nil, nil
 }
 
+// HasOwner check owner of the identity, other modules use it to let the
+// current owners act for an identity
+func (k Keeper) HasOwner(ctx sdk.Context, id sdk.AccAddress, owner sdk.AccAddress) bool {
+	return k.hasOwner(ctx, id, owner)
+}
+
 // hasOwner check owner of the identity
 func (k Keeper) hasOwner(ctx sdk.Context, id sdk.AccAddress, owner sdk.AccAddress) bool {
 	store := ctx.KVStore(k.storeKey)
@@ -52,12 +83,27 @@ func (k Keeper) hasOwner(ctx sdk.Context, id sdk.AccAddress, owner sdk.AccAddres
 func (k Keeper) setOwner(ctx sdk.Context, id sdk.AccAddress, owner sdk.AccAddress) {
 	store := ctx.KVStore(k.storeKey)
 	store.Set(KeyOwner(id, owner), []byte{})
+	store.Set(KeyOwnedIdent(owner, id), []byte{})
 }
 
 // delOwner ...
 func (k Keeper) delOwner(ctx sdk.Context, id sdk.AccAddress, owner sdk.AccAddress) {
 	store := ctx.KVStore(k.storeKey)
 	store.Delete(KeyOwner(id, owner))
+	store.Delete(KeyOwnedIdent(owner, id))
+}
+
+// GetOwnedIdents returns the identities an address is an owner of
+func (k Keeper) GetOwnedIdents(ctx sdk.Context, owner sdk.AccAddress) []sdk.AccAddress {
+	store := ctx.KVStore(k.storeKey)
+	prefix := KeyOwnedIdents(owner)
+	iter := sdk.KVStorePrefixIterator(store, prefix)
+	defer iter.Close()
+	idents := []sdk.AccAddress{}
+	for ; iter.Valid(); iter.Next() {
+		idents = append(idents, sdk.AccAddress(iter.Key()[len(prefix):]))
+	}
+	return idents
 }
 
 // getOwnerCount ...
@@ -0,0 +1,248 @@
+package identity
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"unicode/utf8"
+)
+
+// JSONSchema is the subset of JSON Schema that the certs are validated
+// against on chain. Keywords that need network access or are not
+// supported ($ref, oneOf, ...) are rejected when the schema is parsed so a
+// schema never validates less than its author expects.
+type JSONSchema struct {
+	Types                []string
+	Properties           map[string]*JSONSchema
+	Required             []string
+	AdditionalProperties *bool
+	Items                *JSONSchema
+	Enum                 []interface{}
+	MinLength            *int
+	MaxLength            *int
+	Pattern              *regexp.Regexp
+	Minimum              *float64
+	Maximum              *float64
+	MinItems             *int
+	MaxItems             *int
+}
+
+var schemaKeywords = map[string]bool{
+	"$schema": true, "$id": true, "title": true, "description": true, "examples": true,
+	"type": true, "properties": true, "required": true, "additionalProperties": true,
+	"items": true, "enum": true, "minLength": true, "maxLength": true, "pattern": true,
+	"minimum": true, "maximum": true, "minItems": true, "maxItems": true,
+}
+
+var schemaTypes = map[string]bool{
+	"object": true, "array": true, "string": true, "number": true,
+	"integer": true, "boolean": true, "null": true,
+}
+
+// ParseJSONSchema ...
+func ParseJSONSchema(bz []byte) (*JSONSchema, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(bz, &raw); err != nil {
+		return nil, fmt.Errorf("schema must be a JSON object: %s", err)
+	}
+	keys := make([]string, 0, len(raw))
+	for key := range raw {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	s := &JSONSchema{}
+	for _, key := range keys {
+		value := raw[key]
+		if !schemaKeywords[key] {
+			return nil, fmt.Errorf("unsupported schema keyword %s", key)
+		}
+		var err error
+		switch key {
+		case "type":
+			err = parseSchemaTypes(value, s)
+		case "properties":
+			var props map[string]json.RawMessage
+			if err = json.Unmarshal(value, &props); err != nil {
+				break
+			}
+			s.Properties = map[string]*JSONSchema{}
+			for name, prop := range props {
+				if s.Properties[name], err = ParseJSONSchema(prop); err != nil {
+					return nil, fmt.Errorf("properties.%s: %s", name, err)
+				}
+			}
+		case "required":
+			err = json.Unmarshal(value, &s.Required)
+		case "additionalProperties":
+			err = json.Unmarshal(value, &s.AdditionalProperties)
+		case "items":
+			if s.Items, err = ParseJSONSchema(value); err != nil {
+				return nil, fmt.Errorf("items: %s", err)
+			}
+		case "enum":
+			err = json.Unmarshal(value, &s.Enum)
+		case "minLength":
+			err = json.Unmarshal(value, &s.MinLength)
+		case "maxLength":
+			err = json.Unmarshal(value, &s.MaxLength)
+		case "pattern":
+			var pattern string
+			if err = json.Unmarshal(value, &pattern); err == nil {
+				s.Pattern, err = regexp.Compile(pattern)
+			}
+		case "minimum":
+			err = json.Unmarshal(value, &s.Minimum)
+		case "maximum":
+			err = json.Unmarshal(value, &s.Maximum)
+		case "minItems":
+			err = json.Unmarshal(value, &s.MinItems)
+		case "maxItems":
+			err = json.Unmarshal(value, &s.MaxItems)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %s", key, err)
+		}
+	}
+	return s, nil
+}
+
+func parseSchemaTypes(value json.RawMessage, s *JSONSchema) error {
+	var single string
+	if err := json.Unmarshal(value, &single); err == nil {
+		s.Types = []string{single}
+	} else if err := json.Unmarshal(value, &s.Types); err != nil {
+		return err
+	}
+	for _, t := range s.Types {
+		if !schemaTypes[t] {
+			return fmt.Errorf("unknown type %s", t)
+		}
+	}
+	return nil
+}
+
+// ValidateJSON checks a JSON document, an empty document is null
+func (s *JSONSchema) ValidateJSON(bz []byte) error {
+	var value interface{}
+	if len(bytes.TrimSpace(bz)) > 0 {
+		if err := json.Unmarshal(bz, &value); err != nil {
+			return fmt.Errorf("invalid JSON: %s", err)
+		}
+	}
+	return s.validate(value, "$")
+}
+
+func (s *JSONSchema) validate(value interface{}, path string) error {
+	if len(s.Types) > 0 && !s.matchesType(value) {
+		return fmt.Errorf("%s: expected %v", path, s.Types)
+	}
+	if len(s.Enum) > 0 {
+		found := false
+		for _, e := range s.Enum {
+			if reflect.DeepEqual(e, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%s: not one of the enum values", path)
+		}
+	}
+
+	switch v := value.(type) {
+	case string:
+		n := utf8.RuneCountInString(v)
+		if s.MinLength != nil && n < *s.MinLength {
+			return fmt.Errorf("%s: shorter than %d", path, *s.MinLength)
+		}
+		if s.MaxLength != nil && n > *s.MaxLength {
+			return fmt.Errorf("%s: longer than %d", path, *s.MaxLength)
+		}
+		if s.Pattern != nil && !s.Pattern.MatchString(v) {
+			return fmt.Errorf("%s: does not match %s", path, s.Pattern)
+		}
+	case float64:
+		if s.Minimum != nil && v < *s.Minimum {
+			return fmt.Errorf("%s: less than %v", path, *s.Minimum)
+		}
+		if s.Maximum != nil && v > *s.Maximum {
+			return fmt.Errorf("%s: greater than %v", path, *s.Maximum)
+		}
+	case []interface{}:
+		if s.MinItems != nil && len(v) < *s.MinItems {
+			return fmt.Errorf("%s: fewer than %d items", path, *s.MinItems)
+		}
+		if s.MaxItems != nil && len(v) > *s.MaxItems {
+			return fmt.Errorf("%s: more than %d items", path, *s.MaxItems)
+		}
+		if s.Items != nil {
+			for i, item := range v {
+				if err := s.Items.validate(item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	case map[string]interface{}:
+		for _, name := range s.Required {
+			if _, ok := v[name]; !ok {
+				return fmt.Errorf("%s: missing %s", path, name)
+			}
+		}
+		// sorted so the first error is the same on every node
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			prop, ok := s.Properties[name]
+			if !ok {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					return fmt.Errorf("%s: unexpected property %s", path, name)
+				}
+				continue
+			}
+			if err := prop.validate(v[name], path+"."+name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *JSONSchema) matchesType(value interface{}) bool {
+	for _, t := range s.Types {
+		switch v := value.(type) {
+		case nil:
+			if t == "null" {
+				return true
+			}
+		case bool:
+			if t == "boolean" {
+				return true
+			}
+		case string:
+			if t == "string" {
+				return true
+			}
+		case float64:
+			if t == "number" || (t == "integer" && v == math.Trunc(v)) {
+				return true
+			}
+		case []interface{}:
+			if t == "array" {
+				return true
+			}
+		case map[string]interface{}:
+			if t == "object" {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,67 @@
+package identity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const organicSchema = `{
+	"type": "object",
+	"required": ["standard", "score"],
+	"additionalProperties": false,
+	"properties": {
+		"standard": {"type": "string", "enum": ["EU", "USDA"]},
+		"score": {"type": "integer", "minimum": 0, "maximum": 100},
+		"auditor": {"type": "string", "minLength": 2, "pattern": "^[A-Z]"},
+		"lots": {"type": "array", "maxItems": 2, "items": {"type": "string"}},
+		"note": {"type": ["string", "null"]}
+	}
+}`
+
+func TestParseJSONSchema(t *testing.T) {
+	_, err := ParseJSONSchema([]byte(organicSchema))
+	require.Nil(t, err)
+
+	invalid := []string{
+		`[]`,
+		`{"type": "date"}`,
+		`{"$ref": "http://example.com/schema.json"}`,
+		`{"oneOf": [{"type": "string"}]}`,
+		`{"pattern": "("}`,
+		`{"properties": {"a": {"minimum": "1"}}}`,
+	}
+	for _, schema := range invalid {
+		_, err := ParseJSONSchema([]byte(schema))
+		assert.NotNil(t, err, schema)
+	}
+}
+
+func TestValidateJSON(t *testing.T) {
+	s, err := ParseJSONSchema([]byte(organicSchema))
+	require.Nil(t, err)
+
+	cases := []struct {
+		data  string
+		valid bool
+	}{
+		{`{"standard": "EU", "score": 90}`, true},
+		{`{"standard": "EU", "score": 90, "auditor": "Bureau", "lots": ["a", "b"], "note": null}`, true},
+		{``, false},
+		{`"EU"`, false},
+		{`{"standard": "EU"}`, false},
+		{`{"standard": "JAS", "score": 90}`, false},
+		{`{"standard": "EU", "score": 90.5}`, false},
+		{`{"standard": "EU", "score": 101}`, false},
+		{`{"standard": "EU", "score": 90, "auditor": "bureau"}`, false},
+		{`{"standard": "EU", "score": 90, "lots": ["a", "b", "c"]}`, false},
+		{`{"standard": "EU", "score": 90, "lots": [1]}`, false},
+		{`{"standard": "EU", "score": 90, "extra": true}`, false},
+		{`{"standard": "EU", "score": 90, "note": 1}`, false},
+	}
+	for _, c := range cases {
+		err := s.ValidateJSON([]byte(c.data))
+		assert.Equal(t, c.valid, err == nil, "%s: %v", c.data, err)
+	}
+}
@@ -1,6 +1,8 @@
 package identity
 
 import (
+	"encoding/binary"
+
 	sdk "github.com/cosmos/cosmos-sdk/types"
 )
 
@@ -13,6 +15,30 @@ var (
 	TrustsKey = []byte{0x03}
 	// OwnerCountKey ...
 	OwnerCountKey = []byte{0x04}
+	// RevocationsKey ...
+	RevocationsKey = []byte{0x05}
+	// ServicesKey ...
+	ServicesKey = []byte{0x06}
+	// ThresholdKey ...
+	ThresholdKey = []byte{0x07}
+	// ActionSeqKey ...
+	ActionSeqKey = []byte{0x08}
+	// PendingActionsKey ...
+	PendingActionsKey = []byte{0x09}
+	// RecoveryConfigKey ...
+	RecoveryConfigKey = []byte{0x0A}
+	// RecoveryKey ...
+	RecoveryKey = []byte{0x0B}
+	// ProfileKey ...
+	ProfileKey = []byte{0x0C}
+	// CertSchemasKey ...
+	CertSchemasKey = []byte{0x0D}
+	// IssuedCertsKey ...
+	IssuedCertsKey = []byte{0x0E}
+	// OwnedIdentsKey ...
+	OwnedIdentsKey = []byte{0x0F}
+	// RegistrationsKey ...
+	RegistrationsKey = []byte{0x10}
 )
 
 // KeyTrust Key for getting all trusting from the store
@@ -23,6 +49,11 @@ func KeyTrust(trustor, trusting sdk.AccAddress) []byte {
 	)
 }
 
+// TrustingFromKey returns the trusting address of a KeyTrust key
+func TrustingFromKey(trustor sdk.AccAddress, key []byte) sdk.AccAddress {
+	return sdk.AccAddress(key[len(TrustsKey)+2*len(trustor):])
+}
+
 // KeyTrusts ...
 func KeyTrusts(trustor sdk.AccAddress) []byte {
 	return append(TrustsKey, trustor.Bytes()...)
@@ -55,3 +86,107 @@ func KeyOwner(id, owner sdk.AccAddress) []byte {
 func KeyOwnerCount(id sdk.AccAddress) []byte {
 	return append(OwnerCountKey, id.Bytes()...)
 }
+
+// KeyRevocations Key for getting all revocations of the certs of an address
+func KeyRevocations(addr sdk.AccAddress) []byte {
+	return append(RevocationsKey, addr.Bytes()...)
+}
+
+// KeyRevocation Key for a revocation, sorted by revocation time
+func KeyRevocation(addr sdk.AccAddress, revokedAt int64, property string, certifier sdk.AccAddress) []byte {
+	bz := make([]byte, 8)
+	binary.BigEndian.PutUint64(bz, uint64(revokedAt))
+	return append(
+		append(append(KeyRevocations(addr), bz...), []byte(property)...),
+		certifier.Bytes()...,
+	)
+}
+
+// KeyServices Key for getting all service endpoints of an identity
+func KeyServices(id sdk.AccAddress) []byte {
+	return append(ServicesKey, id.Bytes()...)
+}
+
+// KeyService Key for a service endpoint
+func KeyService(id sdk.AccAddress, serviceID string) []byte {
+	return append(KeyServices(id), []byte(serviceID)...)
+}
+
+// KeyThreshold Key for the approval threshold of an identity
+func KeyThreshold(id sdk.AccAddress) []byte {
+	return append(ThresholdKey, id.Bytes()...)
+}
+
+// KeyActionSeq Key for the last action id of an identity
+func KeyActionSeq(id sdk.AccAddress) []byte {
+	return append(ActionSeqKey, id.Bytes()...)
+}
+
+// KeyPendingActions Key for getting all pending actions of an identity
+func KeyPendingActions(id sdk.AccAddress) []byte {
+	return append(PendingActionsKey, id.Bytes()...)
+}
+
+// KeyPendingAction Key for a pending action, sorted by id
+func KeyPendingAction(id sdk.AccAddress, actionID int64) []byte {
+	bz := make([]byte, 8)
+	binary.BigEndian.PutUint64(bz, uint64(actionID))
+	return append(KeyPendingActions(id), bz...)
+}
+
+// KeyRecoveryConfig Key for the guardians of an identity
+func KeyRecoveryConfig(id sdk.AccAddress) []byte {
+	return append(RecoveryConfigKey, id.Bytes()...)
+}
+
+// KeyRecovery Key for the recovery in progress of an identity
+func KeyRecovery(id sdk.AccAddress) []byte {
+	return append(RecoveryKey, id.Bytes()...)
+}
+
+// KeyProfile Key for the profile of an identity
+func KeyProfile(id sdk.AccAddress) []byte {
+	return append(ProfileKey, id.Bytes()...)
+}
+
+// KeyCertSchemas Key for getting all cert schemas of a certifier
+func KeyCertSchemas(certifier sdk.AccAddress) []byte {
+	return append(CertSchemasKey, certifier.Bytes()...)
+}
+
+// KeyCertSchema Key for the schema of a property
+func KeyCertSchema(certifier sdk.AccAddress, property string) []byte {
+	return append(KeyCertSchemas(certifier), []byte(property)...)
+}
+
+// KeyIssuedCerts Key for getting all certs issued by a certifier, an empty
+// property matches all of them
+func KeyIssuedCerts(certifier sdk.AccAddress, property string) []byte {
+	return append(append(IssuedCertsKey, certifier.Bytes()...), []byte(property)...)
+}
+
+// KeyIssuedCert Key for the certifier index of a cert
+func KeyIssuedCert(certifier sdk.AccAddress, property string, owner sdk.AccAddress) []byte {
+	return append(KeyIssuedCerts(certifier, property), owner.Bytes()...)
+}
+
+// PropertyFromIssuedCertKey returns the property of a KeyIssuedCert key
+func PropertyFromIssuedCertKey(certifier, owner sdk.AccAddress, key []byte) string {
+	return string(key[len(IssuedCertsKey)+len(certifier) : len(key)-len(owner)])
+}
+
+// KeyOwnedIdents Key for getting all identities owned by an address
+func KeyOwnedIdents(owner sdk.AccAddress) []byte {
+	return append(OwnedIdentsKey, owner.Bytes()...)
+}
+
+// KeyOwnedIdent Key for the owner index of an identity
+func KeyOwnedIdent(owner, id sdk.AccAddress) []byte {
+	return append(KeyOwnedIdents(owner), id.Bytes()...)
+}
+
+// KeyRegistration Key for the height an identity was registered by its own
+// key at
+func KeyRegistration(id sdk.AccAddress) []byte {
+	return append(RegistrationsKey, id.Bytes()...)
+}
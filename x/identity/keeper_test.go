@@ -3,8 +3,12 @@ package identity
 import (
 	"bytes"
 	"testing"
+	"time"
 
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
 )
 
 func TestKeeper(t *testing.T) {
@@ -14,26 +18,30 @@ func TestKeeper(t *testing.T) {
 	// ----------------------------------------------------
 
 	msgRegister := MsgReg{
-		Ident:  addrs[1],
-		Sender: addrs[2],
+		Sender: addrs[1],
 	}
 	keeper.Register(ctx, msgRegister)
 	owners := keeper.GetOwners(ctx, addrs[1])
 	assert.True(t, len(owners) == 1)
+	assert.True(t, keeper.IsRegistered(ctx, addrs[1]))
+	assert.False(t, keeper.IsRegistered(ctx, addrs[3]))
 
 	msgRegister = MsgReg{
-		Ident:  addrs[2],
 		Sender: addrs[2],
 	}
 	keeper.Register(ctx, msgRegister)
 
 	// Invalid id already exists
 	_, err := keeper.Register(ctx, MsgReg{
-		Ident:  addrs[1],
-		Sender: addrs[2],
+		Sender: addrs[1],
 	})
 	assert.True(t, err != nil)
 
+	// the key of the identity hands it over to another owner
+	keeper.AddOwner(ctx, MsgAddOwner{Ident: addrs[1], Sender: addrs[1], Owner: addrs[2]})
+	keeper.DeleteOwner(ctx, MsgDelOwner{Ident: addrs[1], Sender: addrs[2], Owner: addrs[1]})
+	assert.Equal(t, []sdk.AccAddress{addrs[2]}, keeper.GetOwners(ctx, addrs[1]))
+
 	// add owner
 
 	// invalid sender
@@ -111,3 +119,383 @@ func TestKeeper(t *testing.T) {
 	assert.True(t, len(certs) == 0)
 
 }
+
+func TestRevokeCerts(t *testing.T) {
+	ctx, _, keeper := createTestInput(t, false, 0)
+	ctx = ctx.WithBlockHeader(abci.Header{Time: time.Unix(100, 0)})
+	keeper.Register(ctx, MsgReg{Sender: addrs[1]})
+
+	_, err := keeper.AddCerts(ctx, NewMsgSetCerts(addrs[1], addrs[1], []CertValue{
+		{Property: "organic", Owner: addrs[2], Confidence: true, ExpiresAt: 200},
+		{Property: "iso", Owner: addrs[2], Confidence: true},
+	}))
+	require.Nil(t, err)
+	cert, _ := keeper.GetCert(ctx, addrs[2], "organic", addrs[1])
+	assert.Equal(t, int64(200), cert.ExpiresAt)
+	assert.True(t, cert.IsValidAt(150))
+	assert.Equal(t, CertStatusExpired, cert.StatusAt(200))
+
+	// invalid sender
+	_, err = keeper.RevokeCerts(ctx, NewMsgRevokeCerts(addrs[3], addrs[1], []CertRevocation{{Owner: addrs[2], Property: "organic"}}))
+	assert.NotNil(t, err)
+
+	// unknown cert
+	_, err = keeper.RevokeCerts(ctx, NewMsgRevokeCerts(addrs[1], addrs[1], []CertRevocation{{Owner: addrs[2], Property: "halal"}}))
+	assert.NotNil(t, err)
+
+	ctx = ctx.WithBlockHeader(abci.Header{Time: time.Unix(120, 0)})
+	_, err = keeper.RevokeCerts(ctx, NewMsgRevokeCerts(addrs[1], addrs[1], []CertRevocation{{Owner: addrs[2], Property: "organic", Reason: "failed audit"}}))
+	require.Nil(t, err)
+
+	// the revoked cert stays visible
+	cert, found := keeper.GetCert(ctx, addrs[2], "organic", addrs[1])
+	require.True(t, found)
+	assert.True(t, cert.Revoked)
+	assert.Equal(t, "failed audit", cert.RevokeReason)
+	assert.True(t, cert.IsValidAt(110))
+	assert.Equal(t, CertStatusRevoked, cert.StatusAt(120))
+
+	// already revoked
+	_, err = keeper.RevokeCerts(ctx, NewMsgRevokeCerts(addrs[1], addrs[1], []CertRevocation{{Owner: addrs[2], Property: "organic"}}))
+	assert.NotNil(t, err)
+
+	// confidence=false revokes without reason
+	_, err = keeper.AddCerts(ctx, NewMsgSetCerts(addrs[1], addrs[1], []CertValue{{Property: "iso", Owner: addrs[2], Confidence: false}}))
+	require.Nil(t, err)
+	assert.Equal(t, 2, len(keeper.GetCerts(ctx, addrs[2])))
+
+	// a revoked cert isn't issued again, its validity in the past stays
+	ctx = ctx.WithBlockHeader(abci.Header{Time: time.Unix(130, 0)})
+	_, err = keeper.AddCerts(ctx, NewMsgSetCerts(addrs[1], addrs[1], []CertValue{{Property: "organic", Owner: addrs[2], Confidence: true}}))
+	assert.NotNil(t, err)
+	cert, _ = keeper.GetCert(ctx, addrs[2], "organic", addrs[1])
+	assert.True(t, cert.Revoked)
+	assert.Equal(t, int64(100), cert.CreatedAt)
+	assert.True(t, cert.IsValidAt(110))
+
+	revocations := keeper.GetRevocations(ctx, addrs[2])
+	require.Equal(t, 2, len(revocations))
+	assert.Equal(t, "failed audit", revocations[0].Reason)
+	assert.Equal(t, int64(100), revocations[0].CertCreatedAt)
+	assert.Equal(t, "iso", revocations[1].Property)
+}
+
+func TestUpdateCertExpiry(t *testing.T) {
+	ctx, _, keeper := createTestInput(t, false, 0)
+	ctx = ctx.WithBlockHeader(abci.Header{Time: time.Unix(100, 0)})
+	keeper.Register(ctx, MsgReg{Sender: addrs[1]})
+	setExpiry := func(property string, expiresAt int64) sdk.Error {
+		_, err := keeper.AddCerts(ctx, NewMsgSetCerts(addrs[1], addrs[1], []CertValue{{Property: property, Owner: addrs[2], Confidence: true, ExpiresAt: expiresAt}}))
+		return err
+	}
+	require.Nil(t, setExpiry("organic", 200))
+	require.Nil(t, setExpiry("iso", 120))
+
+	ctx = ctx.WithBlockHeader(abci.Header{Time: time.Unix(150, 0)})
+	// the expiry moves while both are in the future
+	require.Nil(t, setExpiry("organic", 300))
+	require.Nil(t, setExpiry("organic", 180))
+	// but it can't end the cert before now
+	assert.NotNil(t, setExpiry("organic", 140))
+	// nor renew an expired cert, it was invalid since 120
+	assert.NotNil(t, setExpiry("iso", 300))
+	cert, _ := keeper.GetCert(ctx, addrs[2], "iso", addrs[1])
+	assert.Equal(t, CertStatusExpired, cert.StatusAt(130))
+	// its data can still change
+	require.Nil(t, setExpiry("iso", 120))
+
+	require.Nil(t, setExpiry("organic", 0))
+	cert, _ = keeper.GetCert(ctx, addrs[2], "organic", addrs[1])
+	assert.True(t, cert.IsValidAt(1000))
+}
+
+func TestServices(t *testing.T) {
+	ctx, _, keeper := createTestInput(t, false, 0)
+	keeper.Register(ctx, MsgReg{Sender: addrs[1]})
+
+	service := Service{ID: "hub", Type: "IdentityHub", Endpoint: "https://hub.example.com"}
+	_, err := keeper.AddService(ctx, NewMsgAddService(addrs[2], addrs[1], service))
+	assert.NotNil(t, err)
+
+	_, err = keeper.AddService(ctx, NewMsgAddService(addrs[1], addrs[1], service))
+	require.Nil(t, err)
+	service.Endpoint = "https://hub2.example.com"
+	keeper.AddService(ctx, NewMsgAddService(addrs[1], addrs[1], service))
+	keeper.AddService(ctx, NewMsgAddService(addrs[1], addrs[1], Service{ID: "agent", Type: "DIDCommMessaging", Endpoint: "https://agent.example.com"}))
+
+	services := keeper.GetServices(ctx, addrs[1])
+	require.Equal(t, 2, len(services))
+	assert.Equal(t, "agent", services[0].ID)
+	assert.Equal(t, "https://hub2.example.com", services[1].Endpoint)
+
+	_, err = keeper.DeleteService(ctx, NewMsgDelService(addrs[1], addrs[1], "unknown"))
+	assert.NotNil(t, err)
+	_, err = keeper.DeleteService(ctx, NewMsgDelService(addrs[1], addrs[1], "hub"))
+	require.Nil(t, err)
+	assert.Equal(t, 1, len(keeper.GetServices(ctx, addrs[1])))
+}
+
+func TestThreshold(t *testing.T) {
+	ctx, _, keeper := createTestInput(t, false, 0)
+	keeper.Register(ctx, MsgReg{Sender: addrs[1]})
+	keeper.AddOwner(ctx, MsgAddOwner{Ident: addrs[1], Sender: addrs[1], Owner: addrs[2]})
+	keeper.AddOwner(ctx, MsgAddOwner{Ident: addrs[1], Sender: addrs[1], Owner: addrs[3]})
+
+	// already an owner
+	_, err := keeper.AddOwner(ctx, MsgAddOwner{Ident: addrs[1], Sender: addrs[1], Owner: addrs[3]})
+	assert.NotNil(t, err)
+
+	// more approvals than owners
+	_, err = keeper.SetThreshold(ctx, NewMsgSetThreshold(addrs[1], addrs[1], 4))
+	assert.NotNil(t, err)
+	_, err = keeper.SetThreshold(ctx, NewMsgSetThreshold(addrs[1], addrs[1], 2))
+	require.Nil(t, err)
+	assert.Equal(t, int64(2), keeper.GetThreshold(ctx, addrs[1]))
+
+	// a single owner can only propose
+	_, err = keeper.AddCerts(ctx, NewMsgSetCerts(addrs[2], addrs[1], []CertValue{{Property: "organic", Owner: addrs[4], Confidence: true}}))
+	require.Nil(t, err)
+	_, found := keeper.GetCert(ctx, addrs[4], "organic", addrs[1])
+	assert.False(t, found)
+	actions := keeper.GetPendingActions(ctx, addrs[1])
+	require.Equal(t, 1, len(actions))
+	assert.Equal(t, int64(1), actions[0].ID)
+
+	// the proposer already approved
+	_, err = keeper.ApproveAction(ctx, NewMsgApproveAction(addrs[2], addrs[1], 1))
+	assert.NotNil(t, err)
+	_, err = keeper.ApproveAction(ctx, NewMsgApproveAction(addrs[4], addrs[1], 1))
+	assert.NotNil(t, err)
+
+	tags, err := keeper.ApproveAction(ctx, NewMsgApproveAction(addrs[3], addrs[1], 1))
+	require.Nil(t, err)
+	assert.Contains(t, tags, sdk.MakeTag(TagAction, []byte(ActionExecuted)))
+	_, found = keeper.GetCert(ctx, addrs[4], "organic", addrs[1])
+	assert.True(t, found)
+	assert.Equal(t, 0, len(keeper.GetPendingActions(ctx, addrs[1])))
+
+	// an action that would fail is not proposed
+	_, err = keeper.DeleteOwner(ctx, MsgDelOwner{Ident: addrs[1], Sender: addrs[1], Owner: addrs[5]})
+	assert.NotNil(t, err)
+
+	// removing an owner keeps enough owners for the threshold
+	keeper.DeleteOwner(ctx, MsgDelOwner{Ident: addrs[1], Sender: addrs[1], Owner: addrs[3]})
+	keeper.ApproveAction(ctx, NewMsgApproveAction(addrs[2], addrs[1], 2))
+	assert.Equal(t, 2, len(keeper.GetOwners(ctx, addrs[1])))
+	_, err = keeper.DeleteOwner(ctx, MsgDelOwner{Ident: addrs[1], Sender: addrs[1], Owner: addrs[2]})
+	assert.NotNil(t, err)
+
+	// cancel
+	keeper.AddOwner(ctx, MsgAddOwner{Ident: addrs[1], Sender: addrs[1], Owner: addrs[6]})
+	_, err = keeper.CancelAction(ctx, NewMsgCancelAction(addrs[2], addrs[1], 3))
+	require.Nil(t, err)
+	_, err = keeper.ApproveAction(ctx, NewMsgApproveAction(addrs[2], addrs[1], 3))
+	assert.NotNil(t, err)
+
+	// revocations, profiles and services need the approvals too
+	_, err = keeper.RevokeCerts(ctx, NewMsgRevokeCerts(addrs[2], addrs[1], []CertRevocation{{Owner: addrs[4], Property: "organic"}}))
+	require.Nil(t, err)
+	cert, _ := keeper.GetCert(ctx, addrs[4], "organic", addrs[1])
+	assert.False(t, cert.Revoked)
+	_, err = keeper.SetProfile(ctx, MsgSetProfile{Ident: addrs[1], Sender: addrs[2], Profile: Profile{Name: "Brand"}})
+	require.Nil(t, err)
+	_, found = keeper.GetProfile(ctx, addrs[1])
+	assert.False(t, found)
+	_, err = keeper.AddService(ctx, NewMsgAddService(addrs[2], addrs[1], Service{ID: "hub", Type: "IdentityHub", Endpoint: "https://hub.example.com"}))
+	require.Nil(t, err)
+	assert.Empty(t, keeper.GetServices(ctx, addrs[1]))
+	assert.Equal(t, 3, len(keeper.GetPendingActions(ctx, addrs[1])))
+
+	keeper.ApproveAction(ctx, NewMsgApproveAction(addrs[1], addrs[1], 4))
+	cert, _ = keeper.GetCert(ctx, addrs[4], "organic", addrs[1])
+	assert.True(t, cert.Revoked)
+}
+
+func TestDeleteLastOwner(t *testing.T) {
+	ctx, _, keeper := createTestInput(t, false, 0)
+	keeper.Register(ctx, MsgReg{Sender: addrs[1]})
+	_, err := keeper.DeleteOwner(ctx, MsgDelOwner{Ident: addrs[1], Sender: addrs[1], Owner: addrs[1]})
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, len(keeper.GetOwners(ctx, addrs[1])))
+}
+
+func TestRecovery(t *testing.T) {
+	ctx, _, keeper := createTestInput(t, false, 0)
+	ctx = ctx.WithBlockHeader(abci.Header{Time: time.Unix(100, 0)})
+	keeper.Register(ctx, MsgReg{Sender: addrs[1]})
+	config := RecoveryConfig{Guardians: []sdk.AccAddress{addrs[2], addrs[3], addrs[4]}, Quorum: 2, Delay: 60}
+	_, err := keeper.SetGuardians(ctx, NewMsgSetGuardians(addrs[1], addrs[1], config))
+	require.Nil(t, err)
+
+	// only guardians can recover
+	_, err = keeper.Recover(ctx, NewMsgRecover(addrs[5], addrs[1], addrs[5]))
+	assert.NotNil(t, err)
+
+	_, err = keeper.Recover(ctx, NewMsgRecover(addrs[2], addrs[1], addrs[5]))
+	require.Nil(t, err)
+	// one recovery at a time
+	_, err = keeper.Recover(ctx, NewMsgRecover(addrs[3], addrs[1], addrs[6]))
+	assert.NotNil(t, err)
+	// quorum not reached
+	_, err = keeper.FinalizeRecovery(ctx, NewMsgFinalizeRecovery(addrs[2], addrs[1]))
+	assert.NotNil(t, err)
+
+	// the owner vetoes
+	_, err = keeper.VetoRecovery(ctx, NewMsgVetoRecovery(addrs[1], addrs[1]))
+	require.Nil(t, err)
+	_, found := keeper.GetRecovery(ctx, addrs[1])
+	assert.False(t, found)
+
+	keeper.Recover(ctx, NewMsgRecover(addrs[2], addrs[1], addrs[5]))
+	ctx = ctx.WithBlockHeader(abci.Header{Time: time.Unix(110, 0)})
+	keeper.Recover(ctx, NewMsgRecover(addrs[3], addrs[1], addrs[5]))
+	recovery, _ := keeper.GetRecovery(ctx, addrs[1])
+	assert.Equal(t, int64(170), recovery.ExecutableAt)
+
+	// the delay did not elapse
+	_, err = keeper.FinalizeRecovery(ctx, NewMsgFinalizeRecovery(addrs[5], addrs[1]))
+	assert.NotNil(t, err)
+
+	ctx = ctx.WithBlockHeader(abci.Header{Time: time.Unix(170, 0)})
+	_, err = keeper.FinalizeRecovery(ctx, NewMsgFinalizeRecovery(addrs[5], addrs[1]))
+	require.Nil(t, err)
+	assert.True(t, keeper.hasOwner(ctx, addrs[1], addrs[5]))
+
+	// the recovered owner can remove the lost one
+	_, err = keeper.DeleteOwner(ctx, MsgDelOwner{Ident: addrs[1], Sender: addrs[5], Owner: addrs[1]})
+	require.Nil(t, err)
+	assert.Equal(t, 1, len(keeper.GetOwners(ctx, addrs[1])))
+}
+
+func TestRecoveryThreshold(t *testing.T) {
+	ctx, _, keeper := createTestInput(t, false, 0)
+	ctx = ctx.WithBlockHeader(abci.Header{Time: time.Unix(100, 0)})
+	keeper.Register(ctx, MsgReg{Sender: addrs[1]})
+	keeper.AddOwner(ctx, MsgAddOwner{Ident: addrs[1], Sender: addrs[1], Owner: addrs[2]})
+	keeper.SetThreshold(ctx, NewMsgSetThreshold(addrs[1], addrs[1], 2))
+	config := RecoveryConfig{Guardians: []sdk.AccAddress{addrs[3]}, Quorum: 1, RemoveOwners: true}
+	keeper.SetGuardians(ctx, NewMsgSetGuardians(addrs[1], addrs[1], config))
+	keeper.ApproveAction(ctx, NewMsgApproveAction(addrs[2], addrs[1], 1))
+	require.Equal(t, config, keeper.GetRecoveryConfig(ctx, addrs[1]))
+
+	// both owners lost their keys, the new owner alone controls the identity
+	_, err := keeper.Recover(ctx, NewMsgRecover(addrs[3], addrs[1], addrs[5]))
+	require.Nil(t, err)
+	_, err = keeper.FinalizeRecovery(ctx, NewMsgFinalizeRecovery(addrs[5], addrs[1]))
+	require.Nil(t, err)
+	assert.Equal(t, []sdk.AccAddress{addrs[5]}, keeper.GetOwners(ctx, addrs[1]))
+	assert.Equal(t, int64(1), keeper.GetThreshold(ctx, addrs[1]))
+
+	_, err = keeper.AddOwner(ctx, MsgAddOwner{Ident: addrs[1], Sender: addrs[5], Owner: addrs[6]})
+	require.Nil(t, err)
+	assert.True(t, keeper.hasOwner(ctx, addrs[1], addrs[6]))
+}
+
+func TestSetProfile(t *testing.T) {
+	ctx, _, keeper := createTestInput(t, false, 0)
+	ctx = ctx.WithBlockHeader(abci.Header{Time: time.Unix(100, 0)})
+	keeper.Register(ctx, MsgReg{Sender: addrs[1]})
+
+	_, err := keeper.SetProfile(ctx, NewMsgSetProfile(addrs[2], addrs[1], Profile{Name: "Acme"}))
+	assert.NotNil(t, err)
+
+	_, err = keeper.SetProfile(ctx, NewMsgSetProfile(addrs[1], addrs[1], Profile{Name: "Acme", Country: "VN"}))
+	require.Nil(t, err)
+	profile, found := keeper.GetProfile(ctx, addrs[1])
+	require.True(t, found)
+	assert.Equal(t, "Acme", profile.Name)
+	assert.Equal(t, addrs[1], profile.Address)
+	assert.Equal(t, int64(100), profile.UpdatedAt)
+
+	keeper.SetProfile(ctx, NewMsgSetProfile(addrs[1], addrs[1], Profile{}))
+	_, found = keeper.GetProfile(ctx, addrs[1])
+	assert.False(t, found)
+}
+
+func TestCertSchema(t *testing.T) {
+	ctx, _, keeper := createTestInput(t, false, 0)
+	ctx = ctx.WithBlockHeader(abci.Header{Time: time.Unix(100, 0)})
+	keeper.Register(ctx, MsgReg{Sender: addrs[1]})
+	schema := []byte(`{"type": "object", "required": ["score"], "properties": {"score": {"type": "integer"}}}`)
+
+	// invalid sender
+	_, err := keeper.SetCertSchema(ctx, NewMsgSetCertSchema(addrs[2], addrs[1], "organic", schema))
+	assert.NotNil(t, err)
+
+	_, err = keeper.SetCertSchema(ctx, NewMsgSetCertSchema(addrs[1], addrs[1], "organic", schema))
+	require.Nil(t, err)
+	saved, found := keeper.GetCertSchema(ctx, addrs[1], "organic")
+	require.True(t, found)
+	assert.Equal(t, int64(100), saved.UpdatedAt)
+	assert.Equal(t, 1, len(keeper.GetCertSchemas(ctx, addrs[1])))
+
+	// data doesn't match the schema
+	_, err = keeper.AddCerts(ctx, NewMsgSetCerts(addrs[1], addrs[1], []CertValue{
+		{Property: "organic", Owner: addrs[2], Confidence: true, Data: Metadata(`{"score": "high"}`)},
+	}))
+	assert.NotNil(t, err)
+
+	// properties without a schema accept any data
+	_, err = keeper.AddCerts(ctx, NewMsgSetCerts(addrs[1], addrs[1], []CertValue{
+		{Property: "organic", Owner: addrs[2], Confidence: true, Data: Metadata(`{"score": 90}`)},
+		{Property: "iso", Owner: addrs[2], Confidence: true, Data: Metadata(`"anything"`)},
+	}))
+	require.Nil(t, err)
+
+	// the schema of another certifier doesn't apply
+	keeper.Register(ctx, MsgReg{Sender: addrs[3]})
+	_, err = keeper.AddCerts(ctx, NewMsgSetCerts(addrs[3], addrs[3], []CertValue{
+		{Property: "organic", Owner: addrs[2], Confidence: true},
+	}))
+	require.Nil(t, err)
+
+	keeper.SetCertSchema(ctx, NewMsgSetCertSchema(addrs[1], addrs[1], "organic", nil))
+	_, found = keeper.GetCertSchema(ctx, addrs[1], "organic")
+	assert.False(t, found)
+}
+
+func TestIssuedCerts(t *testing.T) {
+	ctx, _, keeper := createTestInput(t, false, 0)
+	keeper.Register(ctx, MsgReg{Sender: addrs[1]})
+
+	_, err := keeper.AddCerts(ctx, NewMsgSetCerts(addrs[1], addrs[1], []CertValue{
+		{Property: "organic", Owner: addrs[2], Confidence: true},
+		{Property: "organic", Owner: addrs[3], Confidence: true},
+		{Property: "organic-eu", Owner: addrs[2], Confidence: true},
+		{Property: "haccp", Owner: addrs[3], Confidence: true},
+	}))
+	require.Nil(t, err)
+
+	assert.Equal(t, 4, len(keeper.GetIssuedCerts(ctx, addrs[1], "")))
+	certs := keeper.GetIssuedCerts(ctx, addrs[1], "organic")
+	require.Equal(t, 2, len(certs))
+	for _, cert := range certs {
+		assert.Equal(t, "organic", cert.Property)
+		assert.Equal(t, addrs[1], cert.Certifier)
+	}
+	assert.Equal(t, 0, len(keeper.GetIssuedCerts(ctx, addrs[2], "")))
+
+	// revoked certs stay in the index
+	_, err = keeper.RevokeCerts(ctx, NewMsgRevokeCerts(addrs[1], addrs[1], []CertRevocation{{Owner: addrs[3], Property: "haccp"}}))
+	require.Nil(t, err)
+	certs = keeper.GetIssuedCerts(ctx, addrs[1], "haccp")
+	require.Equal(t, 1, len(certs))
+	assert.True(t, certs[0].Revoked)
+}
+
+func TestOwnedIdents(t *testing.T) {
+	ctx, _, keeper := createTestInput(t, false, 0)
+	keeper.Register(ctx, MsgReg{Sender: addrs[1]})
+	keeper.Register(ctx, MsgReg{Sender: addrs[2]})
+	keeper.AddOwner(ctx, MsgAddOwner{Ident: addrs[2], Sender: addrs[2], Owner: addrs[1]})
+	keeper.DeleteOwner(ctx, MsgDelOwner{Ident: addrs[2], Sender: addrs[1], Owner: addrs[2]})
+	assert.ElementsMatch(t, []sdk.AccAddress{addrs[1], addrs[2]}, keeper.GetOwnedIdents(ctx, addrs[1]))
+
+	_, err := keeper.AddOwner(ctx, MsgAddOwner{Ident: addrs[2], Sender: addrs[1], Owner: addrs[3]})
+	require.Nil(t, err)
+	assert.Equal(t, []sdk.AccAddress{addrs[2]}, keeper.GetOwnedIdents(ctx, addrs[3]))
+
+	_, err = keeper.DeleteOwner(ctx, MsgDelOwner{Ident: addrs[2], Sender: addrs[1], Owner: addrs[1]})
+	require.Nil(t, err)
+	assert.Equal(t, []sdk.AccAddress{addrs[1]}, keeper.GetOwnedIdents(ctx, addrs[1]))
+}
@@ -7,7 +7,10 @@ import (
 // MsgType name to idetify transaction types
 const MsgType = "identity"
 
-var _, _, _, _, _ sdk.Msg = &MsgSetTrust{}, &MsgSetCerts{}, &MsgAddOwner{}, &MsgDelOwner{}, &MsgReg{}
+var _, _, _, _, _, _, _, _ sdk.Msg = &MsgSetTrust{}, &MsgSetCerts{}, &MsgRevokeCerts{}, &MsgAddOwner{}, &MsgDelOwner{}, &MsgReg{}, &MsgAddService{}, &MsgDelService{}
+var _, _, _ sdk.Msg = &MsgSetThreshold{}, &MsgApproveAction{}, &MsgCancelAction{}
+var _, _, _, _, _ sdk.Msg = &MsgSetGuardians{}, &MsgRecover{}, &MsgVetoRecovery{}, &MsgFinalizeRecovery{}, &MsgSetProfile{}
+var _ sdk.Msg = &MsgSetCertSchema{}
 
 // MsgSetTrust struct for set trust
 type MsgSetTrust struct {
@@ -103,17 +106,69 @@ func (msg MsgSetCerts) ValidateBasic() sdk.Error {
 	return nil
 }
 
+// MsgRevokeCerts struct for revoke certs, the certs stay visibly revoked
+type MsgRevokeCerts struct {
+	Sender      sdk.AccAddress   `json:"sender"`
+	Issuer      sdk.AccAddress   `json:"issuer"`
+	Revocations []CertRevocation `json:"revocations"`
+}
+
+// NewMsgRevokeCerts ...
+func NewMsgRevokeCerts(sender, issuer sdk.AccAddress, revocations []CertRevocation) MsgRevokeCerts {
+	return MsgRevokeCerts{
+		Sender:      sender,
+		Issuer:      issuer,
+		Revocations: revocations,
+	}
+}
+
+// Type ...
+func (msg MsgRevokeCerts) Type() string { return MsgType }
+
+// GetSigners ...
+func (msg MsgRevokeCerts) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Sender}
+}
+
+// GetSignBytes get the bytes for the message signer to sign on
+func (msg MsgRevokeCerts) GetSignBytes() []byte {
+	b, err := MsgCdc.MarshalJSON(msg)
+	if err != nil {
+		panic(err)
+	}
+	return sdk.MustSortJSON(b)
+}
+
+// ValidateBasic quick validity check
+func (msg MsgRevokeCerts) ValidateBasic() sdk.Error {
+	if msg.Sender == nil {
+		return sdk.NewError(DefaultCodespace, CodeInvalidInput, "nil sender address")
+	}
+	if msg.Issuer == nil {
+		return sdk.NewError(DefaultCodespace, CodeInvalidInput, "nil issuer address")
+	}
+	if len(msg.Revocations) == 0 {
+		return sdk.NewError(DefaultCodespace, CodeInvalidInput, "no revocations")
+	}
+	for _, revocation := range msg.Revocations {
+		if err := revocation.ValidateBasic(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Identity
 // -----------------------------------------------
 // MsgReg
 // MsgAddKey
 // MsgDelKey
 
-// MsgReg ....
+// MsgReg registers the address of the sender as an identity owned by it,
+// the signature proves the identity is controlled by its first owner
 // .......................................................
 type MsgReg struct {
 	Sender sdk.AccAddress `json:"sender"`
-	Ident  sdk.AccAddress `json:"ident"`
 }
 
 // Type ...
@@ -138,9 +193,6 @@ func (msg MsgReg) ValidateBasic() sdk.Error {
 	if len(msg.Sender) == 0 {
 		return sdk.NewError(DefaultCodespace, CodeInvalidInput, "nil sender address")
 	}
-	if msg.Ident == nil {
-		return sdk.NewError(DefaultCodespace, CodeInvalidInput, "nil address  address")
-	}
 	return nil
 }
 
@@ -221,3 +273,491 @@ func (msg MsgDelOwner) ValidateBasic() sdk.Error {
 	}
 	return nil
 }
+
+// MsgAddService add or replace a service endpoint of an identity
+// .......................................................
+type MsgAddService struct {
+	Sender  sdk.AccAddress `json:"sender"`
+	Ident   sdk.AccAddress `json:"ident"`
+	Service Service        `json:"service"`
+}
+
+// NewMsgAddService ...
+func NewMsgAddService(sender, ident sdk.AccAddress, service Service) MsgAddService {
+	return MsgAddService{
+		Sender:  sender,
+		Ident:   ident,
+		Service: service,
+	}
+}
+
+// Type ...
+func (msg MsgAddService) Type() string { return MsgType }
+
+// GetSigners ...
+func (msg MsgAddService) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Sender}
+}
+
+// GetSignBytes get the bytes for the message signer to sign on
+func (msg MsgAddService) GetSignBytes() []byte {
+	b, err := MsgCdc.MarshalJSON(msg)
+	if err != nil {
+		panic(err)
+	}
+	return sdk.MustSortJSON(b)
+}
+
+// ValidateBasic quick validity check
+func (msg MsgAddService) ValidateBasic() sdk.Error {
+	if len(msg.Sender) == 0 {
+		return sdk.NewError(DefaultCodespace, CodeInvalidInput, "nil sender address")
+	}
+	if msg.Ident == nil {
+		return sdk.NewError(DefaultCodespace, CodeInvalidInput, "nil ident address")
+	}
+	return msg.Service.ValidateBasic()
+}
+
+// MsgDelService delete a service endpoint of an identity
+// .......................................................
+type MsgDelService struct {
+	Sender    sdk.AccAddress `json:"sender"`
+	Ident     sdk.AccAddress `json:"ident"`
+	ServiceID string         `json:"service_id"`
+}
+
+// NewMsgDelService ...
+func NewMsgDelService(sender, ident sdk.AccAddress, serviceID string) MsgDelService {
+	return MsgDelService{
+		Sender:    sender,
+		Ident:     ident,
+		ServiceID: serviceID,
+	}
+}
+
+// Type ...
+func (msg MsgDelService) Type() string { return MsgType }
+
+// GetSigners ...
+func (msg MsgDelService) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Sender}
+}
+
+// GetSignBytes get the bytes for the message signer to sign on
+func (msg MsgDelService) GetSignBytes() []byte {
+	b, err := MsgCdc.MarshalJSON(msg)
+	if err != nil {
+		panic(err)
+	}
+	return sdk.MustSortJSON(b)
+}
+
+// ValidateBasic quick validity check
+func (msg MsgDelService) ValidateBasic() sdk.Error {
+	if len(msg.Sender) == 0 {
+		return sdk.NewError(DefaultCodespace, CodeInvalidInput, "nil sender address")
+	}
+	if msg.Ident == nil {
+		return sdk.NewError(DefaultCodespace, CodeInvalidInput, "nil ident address")
+	}
+	if len(msg.ServiceID) == 0 {
+		return sdk.NewError(DefaultCodespace, CodeInvalidInput, "empty service id")
+	}
+	return nil
+}
+
+// MsgSetThreshold set the number of owner approvals required by the
+// sensitive actions of an identity
+// .......................................................
+type MsgSetThreshold struct {
+	Sender    sdk.AccAddress `json:"sender"`
+	Ident     sdk.AccAddress `json:"ident"`
+	Threshold int64          `json:"threshold"`
+}
+
+// NewMsgSetThreshold ...
+func NewMsgSetThreshold(sender, ident sdk.AccAddress, threshold int64) MsgSetThreshold {
+	return MsgSetThreshold{
+		Sender:    sender,
+		Ident:     ident,
+		Threshold: threshold,
+	}
+}
+
+// Type ...
+func (msg MsgSetThreshold) Type() string { return MsgType }
+
+// GetSigners ...
+func (msg MsgSetThreshold) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Sender}
+}
+
+// GetSignBytes get the bytes for the message signer to sign on
+func (msg MsgSetThreshold) GetSignBytes() []byte {
+	b, err := MsgCdc.MarshalJSON(msg)
+	if err != nil {
+		panic(err)
+	}
+	return sdk.MustSortJSON(b)
+}
+
+// ValidateBasic quick validity check
+func (msg MsgSetThreshold) ValidateBasic() sdk.Error {
+	if len(msg.Sender) == 0 {
+		return sdk.NewError(DefaultCodespace, CodeInvalidInput, "nil sender address")
+	}
+	if msg.Ident == nil {
+		return sdk.NewError(DefaultCodespace, CodeInvalidInput, "nil ident address")
+	}
+	if msg.Threshold < 1 {
+		return sdk.NewError(DefaultCodespace, CodeInvalidThreshold, "threshold must be at least 1")
+	}
+	return nil
+}
+
+// MsgApproveAction approve a pending action of an identity
+// .......................................................
+type MsgApproveAction struct {
+	Sender   sdk.AccAddress `json:"sender"`
+	Ident    sdk.AccAddress `json:"ident"`
+	ActionID int64          `json:"action_id"`
+}
+
+// NewMsgApproveAction ...
+func NewMsgApproveAction(sender, ident sdk.AccAddress, actionID int64) MsgApproveAction {
+	return MsgApproveAction{
+		Sender:   sender,
+		Ident:    ident,
+		ActionID: actionID,
+	}
+}
+
+// Type ...
+func (msg MsgApproveAction) Type() string { return MsgType }
+
+// GetSigners ...
+func (msg MsgApproveAction) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Sender}
+}
+
+// GetSignBytes get the bytes for the message signer to sign on
+func (msg MsgApproveAction) GetSignBytes() []byte {
+	b, err := MsgCdc.MarshalJSON(msg)
+	if err != nil {
+		panic(err)
+	}
+	return sdk.MustSortJSON(b)
+}
+
+// ValidateBasic quick validity check
+func (msg MsgApproveAction) ValidateBasic() sdk.Error {
+	return validateActionMsg(msg.Sender, msg.Ident, msg.ActionID)
+}
+
+// MsgCancelAction cancel a pending action of an identity
+// .......................................................
+type MsgCancelAction struct {
+	Sender   sdk.AccAddress `json:"sender"`
+	Ident    sdk.AccAddress `json:"ident"`
+	ActionID int64          `json:"action_id"`
+}
+
+// NewMsgCancelAction ...
+func NewMsgCancelAction(sender, ident sdk.AccAddress, actionID int64) MsgCancelAction {
+	return MsgCancelAction{
+		Sender:   sender,
+		Ident:    ident,
+		ActionID: actionID,
+	}
+}
+
+// Type ...
+func (msg MsgCancelAction) Type() string { return MsgType }
+
+// GetSigners ...
+func (msg MsgCancelAction) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Sender}
+}
+
+// GetSignBytes get the bytes for the message signer to sign on
+func (msg MsgCancelAction) GetSignBytes() []byte {
+	b, err := MsgCdc.MarshalJSON(msg)
+	if err != nil {
+		panic(err)
+	}
+	return sdk.MustSortJSON(b)
+}
+
+// ValidateBasic quick validity check
+func (msg MsgCancelAction) ValidateBasic() sdk.Error {
+	return validateActionMsg(msg.Sender, msg.Ident, msg.ActionID)
+}
+
+func validateActionMsg(sender, ident sdk.AccAddress, actionID int64) sdk.Error {
+	if err := validateIdentMsg(sender, ident); err != nil {
+		return err
+	}
+	if actionID < 1 {
+		return sdk.NewError(DefaultCodespace, CodeInvalidInput, "invalid action id")
+	}
+	return nil
+}
+
+// MsgSetGuardians set the guardians that can recover an identity
+// .......................................................
+type MsgSetGuardians struct {
+	Sender sdk.AccAddress `json:"sender"`
+	Ident  sdk.AccAddress `json:"ident"`
+	Config RecoveryConfig `json:"config"`
+}
+
+// NewMsgSetGuardians ...
+func NewMsgSetGuardians(sender, ident sdk.AccAddress, config RecoveryConfig) MsgSetGuardians {
+	return MsgSetGuardians{
+		Sender: sender,
+		Ident:  ident,
+		Config: config,
+	}
+}
+
+// Type ...
+func (msg MsgSetGuardians) Type() string { return MsgType }
+
+// GetSigners ...
+func (msg MsgSetGuardians) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Sender}
+}
+
+// GetSignBytes get the bytes for the message signer to sign on
+func (msg MsgSetGuardians) GetSignBytes() []byte {
+	b, err := MsgCdc.MarshalJSON(msg)
+	if err != nil {
+		panic(err)
+	}
+	return sdk.MustSortJSON(b)
+}
+
+// ValidateBasic quick validity check
+func (msg MsgSetGuardians) ValidateBasic() sdk.Error {
+	if err := validateIdentMsg(msg.Sender, msg.Ident); err != nil {
+		return err
+	}
+	return msg.Config.ValidateBasic()
+}
+
+// MsgRecover start or support the recovery of an identity to a new owner
+// .......................................................
+type MsgRecover struct {
+	Sender   sdk.AccAddress `json:"sender"`
+	Ident    sdk.AccAddress `json:"ident"`
+	NewOwner sdk.AccAddress `json:"new_owner"`
+}
+
+// NewMsgRecover ...
+func NewMsgRecover(sender, ident, newOwner sdk.AccAddress) MsgRecover {
+	return MsgRecover{
+		Sender:   sender,
+		Ident:    ident,
+		NewOwner: newOwner,
+	}
+}
+
+// Type ...
+func (msg MsgRecover) Type() string { return MsgType }
+
+// GetSigners ...
+func (msg MsgRecover) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Sender}
+}
+
+// GetSignBytes get the bytes for the message signer to sign on
+func (msg MsgRecover) GetSignBytes() []byte {
+	b, err := MsgCdc.MarshalJSON(msg)
+	if err != nil {
+		panic(err)
+	}
+	return sdk.MustSortJSON(b)
+}
+
+// ValidateBasic quick validity check
+func (msg MsgRecover) ValidateBasic() sdk.Error {
+	if err := validateIdentMsg(msg.Sender, msg.Ident); err != nil {
+		return err
+	}
+	if len(msg.NewOwner) == 0 {
+		return sdk.NewError(DefaultCodespace, CodeInvalidInput, "nil new owner address")
+	}
+	return nil
+}
+
+// MsgVetoRecovery cancel the recovery of an identity
+// .......................................................
+type MsgVetoRecovery struct {
+	Sender sdk.AccAddress `json:"sender"`
+	Ident  sdk.AccAddress `json:"ident"`
+}
+
+// NewMsgVetoRecovery ...
+func NewMsgVetoRecovery(sender, ident sdk.AccAddress) MsgVetoRecovery {
+	return MsgVetoRecovery{
+		Sender: sender,
+		Ident:  ident,
+	}
+}
+
+// Type ...
+func (msg MsgVetoRecovery) Type() string { return MsgType }
+
+// GetSigners ...
+func (msg MsgVetoRecovery) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Sender}
+}
+
+// GetSignBytes get the bytes for the message signer to sign on
+func (msg MsgVetoRecovery) GetSignBytes() []byte {
+	b, err := MsgCdc.MarshalJSON(msg)
+	if err != nil {
+		panic(err)
+	}
+	return sdk.MustSortJSON(b)
+}
+
+// ValidateBasic quick validity check
+func (msg MsgVetoRecovery) ValidateBasic() sdk.Error {
+	return validateIdentMsg(msg.Sender, msg.Ident)
+}
+
+// MsgFinalizeRecovery add the new owner once the recovery delay elapsed
+// .......................................................
+type MsgFinalizeRecovery struct {
+	Sender sdk.AccAddress `json:"sender"`
+	Ident  sdk.AccAddress `json:"ident"`
+}
+
+// NewMsgFinalizeRecovery ...
+func NewMsgFinalizeRecovery(sender, ident sdk.AccAddress) MsgFinalizeRecovery {
+	return MsgFinalizeRecovery{
+		Sender: sender,
+		Ident:  ident,
+	}
+}
+
+// Type ...
+func (msg MsgFinalizeRecovery) Type() string { return MsgType }
+
+// GetSigners ...
+func (msg MsgFinalizeRecovery) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Sender}
+}
+
+// GetSignBytes get the bytes for the message signer to sign on
+func (msg MsgFinalizeRecovery) GetSignBytes() []byte {
+	b, err := MsgCdc.MarshalJSON(msg)
+	if err != nil {
+		panic(err)
+	}
+	return sdk.MustSortJSON(b)
+}
+
+// ValidateBasic quick validity check
+func (msg MsgFinalizeRecovery) ValidateBasic() sdk.Error {
+	return validateIdentMsg(msg.Sender, msg.Ident)
+}
+
+// MsgSetProfile set the profile of an identity, an empty profile clears it
+// .......................................................
+type MsgSetProfile struct {
+	Sender  sdk.AccAddress `json:"sender"`
+	Ident   sdk.AccAddress `json:"ident"`
+	Profile Profile        `json:"profile"`
+}
+
+// NewMsgSetProfile ...
+func NewMsgSetProfile(sender, ident sdk.AccAddress, profile Profile) MsgSetProfile {
+	return MsgSetProfile{
+		Sender:  sender,
+		Ident:   ident,
+		Profile: profile,
+	}
+}
+
+// Type ...
+func (msg MsgSetProfile) Type() string { return MsgType }
+
+// GetSigners ...
+func (msg MsgSetProfile) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Sender}
+}
+
+// GetSignBytes get the bytes for the message signer to sign on
+func (msg MsgSetProfile) GetSignBytes() []byte {
+	b, err := MsgCdc.MarshalJSON(msg)
+	if err != nil {
+		panic(err)
+	}
+	return sdk.MustSortJSON(b)
+}
+
+// ValidateBasic quick validity check
+func (msg MsgSetProfile) ValidateBasic() sdk.Error {
+	if err := validateIdentMsg(msg.Sender, msg.Ident); err != nil {
+		return err
+	}
+	return msg.Profile.ValidateBasic()
+}
+
+// MsgSetCertSchema register the JSON schema of the data of the certs an
+// issuer gives for a property, an empty schema removes it
+// .......................................................
+type MsgSetCertSchema struct {
+	Sender   sdk.AccAddress `json:"sender"`
+	Issuer   sdk.AccAddress `json:"issuer"`
+	Property string         `json:"property"`
+	Schema   Metadata       `json:"schema"`
+}
+
+// NewMsgSetCertSchema ...
+func NewMsgSetCertSchema(sender, issuer sdk.AccAddress, property string, schema []byte) MsgSetCertSchema {
+	return MsgSetCertSchema{
+		Sender:   sender,
+		Issuer:   issuer,
+		Property: property,
+		Schema:   schema,
+	}
+}
+
+// Type ...
+func (msg MsgSetCertSchema) Type() string { return MsgType }
+
+// GetSigners ...
+func (msg MsgSetCertSchema) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Sender}
+}
+
+// GetSignBytes get the bytes for the message signer to sign on
+func (msg MsgSetCertSchema) GetSignBytes() []byte {
+	b, err := MsgCdc.MarshalJSON(msg)
+	if err != nil {
+		panic(err)
+	}
+	return sdk.MustSortJSON(b)
+}
+
+// ValidateBasic quick validity check
+func (msg MsgSetCertSchema) ValidateBasic() sdk.Error {
+	if err := validateIdentMsg(msg.Sender, msg.Issuer); err != nil {
+		return err
+	}
+	return CertSchema{Property: msg.Property, Schema: msg.Schema}.ValidateBasic()
+}
+
+func validateIdentMsg(sender, ident sdk.AccAddress) sdk.Error {
+	if len(sender) == 0 {
+		return sdk.NewError(DefaultCodespace, CodeInvalidInput, "nil sender address")
+	}
+	if ident == nil {
+		return sdk.NewError(DefaultCodespace, CodeInvalidInput, "nil ident address")
+	}
+	return nil
+}
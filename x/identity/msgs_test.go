@@ -97,7 +97,42 @@ func TestMsgSetCertsGetSignBytes(t *testing.T) {
 		Issuer: addr2,
 		Values: []CertValue{CertValue{Property: "owner", Confidence: true}},
 	}.GetSignBytes()
-	assert.Equal(t, string(signBytes), "{\"type\":\"identity/SetCerts\",\"value\":{\"issuer\":\"cosmosaccaddr1v9jxgu3jlsw7dy\",\"sender\":\"cosmosaccaddr1v9jxgu333rmgrm\",\"values\":[{\"confidence\":true,\"data\":null,\"owner\":\"cosmosaccaddr16y6p2v\",\"property\":\"owner\"}]}}")
+	assert.Equal(t, string(signBytes), "{\"type\":\"identity/SetCerts\",\"value\":{\"issuer\":\"cosmosaccaddr1v9jxgu3jlsw7dy\",\"sender\":\"cosmosaccaddr1v9jxgu333rmgrm\",\"values\":[{\"confidence\":true,\"data\":null,\"expires_at\":\"0\",\"owner\":\"cosmosaccaddr16y6p2v\",\"property\":\"owner\"}]}}")
+}
+
+// MsgRevokeCerts
+// ------------------------------------------
+func TestMsgRevokeCerts(t *testing.T) {
+	tests := []struct {
+		name        string
+		certifier   sdk.AccAddress
+		revocations []CertRevocation
+		expectPass  bool
+	}{
+		{"basic good", addr1, []CertRevocation{{Property: "owner", Owner: addr3, Reason: "expired"}}, true},
+		{"empty certifier", nil, []CertRevocation{{Property: "owner", Owner: addr3}}, false},
+		{"no revocations", addr1, nil, false},
+		{"empty property", addr1, []CertRevocation{{Owner: addr3}}, false},
+		{"empty owner", addr1, []CertRevocation{{Property: "owner"}}, false},
+	}
+
+	for _, tc := range tests {
+		msg := NewMsgRevokeCerts(tc.certifier, tc.certifier, tc.revocations)
+		if tc.expectPass {
+			require.Nil(t, msg.ValidateBasic(), "test: %v", tc.name)
+		} else {
+			require.NotNil(t, msg.ValidateBasic(), "test: %v", tc.name)
+		}
+	}
+}
+
+func TestMsgRevokeCertsGetSignBytes(t *testing.T) {
+	signBytes := MsgRevokeCerts{
+		Sender:      addr1,
+		Issuer:      addr2,
+		Revocations: []CertRevocation{{Property: "owner", Owner: addr3, Reason: "expired"}},
+	}.GetSignBytes()
+	assert.Equal(t, string(signBytes), "{\"type\":\"identity/MsgRevokeCerts\",\"value\":{\"issuer\":\"cosmosaccaddr1v9jxgu3jlsw7dy\",\"revocations\":[{\"owner\":\"cosmosaccaddr1v9jxgu3nzx6tsk\",\"property\":\"owner\",\"reason\":\"expired\"}],\"sender\":\"cosmosaccaddr1v9jxgu333rmgrm\"}}")
 }
 
 // MsgReg
@@ -115,25 +150,22 @@ func TestMsgRegGetSigner(t *testing.T) {
 func TestMsgRegGetSignBytes(t *testing.T) {
 	signBytes := MsgReg{
 		Sender: addr1,
-		Ident:  addr2,
 	}.GetSignBytes()
-	assert.Equal(t, string(signBytes), "{\"type\":\"identity/MsgReg\",\"value\":{\"ident\":\"cosmosaccaddr1v9jxgu3jlsw7dy\",\"sender\":\"cosmosaccaddr1v9jxgu333rmgrm\"}}")
+	assert.Equal(t, string(signBytes), "{\"type\":\"identity/MsgReg\",\"value\":{\"sender\":\"cosmosaccaddr1v9jxgu333rmgrm\"}}")
 }
 
 func TestMsgRegValidation(t *testing.T) {
 	tests := []struct {
 		name       string
 		sender     sdk.AccAddress
-		address    sdk.AccAddress
 		expectPass bool
 	}{
-		{"basic good", addr1, addr2, true},
-		{"empty sender", nil, addr2, false},
-		{"empty address", addr1, nil, false},
+		{"basic good", addr1, true},
+		{"empty sender", nil, false},
 	}
 
 	for _, tc := range tests {
-		msg := MsgReg{tc.sender, tc.address}
+		msg := MsgReg{tc.sender}
 		if tc.expectPass {
 			require.Nil(t, msg.ValidateBasic(), "test: %v", tc.name)
 		} else {
@@ -231,3 +263,78 @@ func TestMsgDelOwnerValidation(t *testing.T) {
 		}
 	}
 }
+
+// MsgAddService
+// ------------------------------------------
+func TestMsgAddServiceValidation(t *testing.T) {
+	tests := []struct {
+		name       string
+		sender     sdk.AccAddress
+		ident      sdk.AccAddress
+		service    Service
+		expectPass bool
+	}{
+		{"basic good", addr1, addr2, Service{ID: "hub", Type: "IdentityHub", Endpoint: "https://hub.example.com"}, true},
+		{"empty sender", nil, addr2, Service{ID: "hub", Type: "IdentityHub", Endpoint: "https://hub.example.com"}, false},
+		{"empty ident", addr1, nil, Service{ID: "hub", Type: "IdentityHub", Endpoint: "https://hub.example.com"}, false},
+		{"empty id", addr1, addr2, Service{Type: "IdentityHub", Endpoint: "https://hub.example.com"}, false},
+		{"invalid id", addr1, addr2, Service{ID: "hub#1", Type: "IdentityHub", Endpoint: "https://hub.example.com"}, false},
+		{"empty type", addr1, addr2, Service{ID: "hub", Endpoint: "https://hub.example.com"}, false},
+		{"relative endpoint", addr1, addr2, Service{ID: "hub", Type: "IdentityHub", Endpoint: "hub.example.com"}, false},
+	}
+
+	for _, tc := range tests {
+		msg := NewMsgAddService(tc.sender, tc.ident, tc.service)
+		if tc.expectPass {
+			require.Nil(t, msg.ValidateBasic(), "test: %v", tc.name)
+		} else {
+			require.NotNil(t, msg.ValidateBasic(), "test: %v", tc.name)
+		}
+	}
+}
+
+func TestMsgAddServiceGetSignBytes(t *testing.T) {
+	signBytes := NewMsgAddService(addr1, addr2, Service{ID: "hub", Type: "IdentityHub", Endpoint: "https://hub.example.com"}).GetSignBytes()
+	assert.Equal(t, string(signBytes), "{\"type\":\"identity/MsgAddService\",\"value\":{\"ident\":\"cosmosaccaddr1v9jxgu3jlsw7dy\",\"sender\":\"cosmosaccaddr1v9jxgu333rmgrm\",\"service\":{\"endpoint\":\"https://hub.example.com\",\"id\":\"hub\",\"type\":\"IdentityHub\"}}}")
+}
+
+// MsgDelService
+// ------------------------------------------
+func TestMsgDelServiceValidation(t *testing.T) {
+	require.Nil(t, NewMsgDelService(addr1, addr2, "hub").ValidateBasic())
+	require.NotNil(t, NewMsgDelService(nil, addr2, "hub").ValidateBasic())
+	require.NotNil(t, NewMsgDelService(addr1, nil, "hub").ValidateBasic())
+	require.NotNil(t, NewMsgDelService(addr1, addr2, "").ValidateBasic())
+}
+
+// MsgSetThreshold
+// ------------------------------------------
+func TestMsgSetThresholdValidation(t *testing.T) {
+	require.Nil(t, NewMsgSetThreshold(addr1, addr2, 2).ValidateBasic())
+	require.NotNil(t, NewMsgSetThreshold(nil, addr2, 2).ValidateBasic())
+	require.NotNil(t, NewMsgSetThreshold(addr1, nil, 2).ValidateBasic())
+	require.NotNil(t, NewMsgSetThreshold(addr1, addr2, 0).ValidateBasic())
+}
+
+func TestMsgApproveActionGetSignBytes(t *testing.T) {
+	signBytes := NewMsgApproveAction(addr1, addr2, 1).GetSignBytes()
+	assert.Equal(t, string(signBytes), "{\"type\":\"identity/MsgApproveAction\",\"value\":{\"action_id\":\"1\",\"ident\":\"cosmosaccaddr1v9jxgu3jlsw7dy\",\"sender\":\"cosmosaccaddr1v9jxgu333rmgrm\"}}")
+}
+
+func TestMsgActionValidation(t *testing.T) {
+	require.Nil(t, NewMsgApproveAction(addr1, addr2, 1).ValidateBasic())
+	require.NotNil(t, NewMsgApproveAction(addr1, addr2, 0).ValidateBasic())
+	require.Nil(t, NewMsgCancelAction(addr1, addr2, 1).ValidateBasic())
+	require.NotNil(t, NewMsgCancelAction(nil, addr2, 1).ValidateBasic())
+}
+
+// MsgSetCertSchema
+// ------------------------------------------
+func TestMsgSetCertSchemaValidation(t *testing.T) {
+	require.Nil(t, NewMsgSetCertSchema(addr1, addr2, "organic", []byte(`{"type": "object"}`)).ValidateBasic())
+	require.Nil(t, NewMsgSetCertSchema(addr1, addr2, "organic", nil).ValidateBasic())
+	require.NotNil(t, NewMsgSetCertSchema(nil, addr2, "organic", nil).ValidateBasic())
+	require.NotNil(t, NewMsgSetCertSchema(addr1, addr2, "", []byte(`{"type": "object"}`)).ValidateBasic())
+	require.NotNil(t, NewMsgSetCertSchema(addr1, addr2, "organic", []byte(`{"$ref": "#/x"}`)).ValidateBasic())
+	require.NotNil(t, NewMsgSetCertSchema(addr1, addr2, "organic", []byte(`not json`)).ValidateBasic())
+}
@@ -0,0 +1,102 @@
+package identity
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"regexp"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+)
+
+// Size limits of the profile fields
+const (
+	MaxProfileNameLength    = 64
+	MaxProfileWebsiteLength = 256
+)
+
+var (
+	countryRegexp   = regexp.MustCompile(`^[A-Z]{2}$`)
+	gs1PrefixRegexp = regexp.MustCompile(`^[0-9]{4,12}$`)
+)
+
+// Profile is the public metadata of an identity, shown next to its address
+type Profile struct {
+	Address   sdk.AccAddress `json:"address"`
+	Name      string         `json:"name"`
+	LogoHash  string         `json:"logo_hash"` // hex sha256 of the logo image
+	Website   string         `json:"website"`
+	Country   string         `json:"country"`    // ISO 3166-1 alpha-2 code
+	GS1Prefix string         `json:"gs1_prefix"` // GS1 company prefix
+	UpdatedAt int64          `json:"updated_at"`
+}
+
+// IsEmpty ...
+func (p Profile) IsEmpty() bool {
+	return p.Name == "" && p.LogoHash == "" && p.Website == "" && p.Country == "" && p.GS1Prefix == ""
+}
+
+// ValidateBasic quick validity check, every field is optional
+func (p Profile) ValidateBasic() sdk.Error {
+	if len(p.Name) > MaxProfileNameLength {
+		return ErrInvalidProfile(DefaultCodespace, fmt.Sprintf("name longer than %d bytes", MaxProfileNameLength))
+	}
+	if p.LogoHash != "" {
+		bz, err := hex.DecodeString(p.LogoHash)
+		if err != nil || len(bz) != 32 {
+			return ErrInvalidProfile(DefaultCodespace, "logo_hash must be a hex sha256")
+		}
+	}
+	if p.Website != "" {
+		if len(p.Website) > MaxProfileWebsiteLength {
+			return ErrInvalidProfile(DefaultCodespace, fmt.Sprintf("website longer than %d bytes", MaxProfileWebsiteLength))
+		}
+		u, err := url.Parse(p.Website)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return ErrInvalidProfile(DefaultCodespace, "website must be an http(s) URL")
+		}
+	}
+	if p.Country != "" && !countryRegexp.MatchString(p.Country) {
+		return ErrInvalidProfile(DefaultCodespace, "country must be an ISO 3166-1 alpha-2 code")
+	}
+	if p.GS1Prefix != "" && !gs1PrefixRegexp.MatchString(p.GS1Prefix) {
+		return ErrInvalidProfile(DefaultCodespace, "gs1_prefix must have 4 to 12 digits")
+	}
+	return nil
+}
+
+// SetProfile set or clear the profile of an identity
+func (k Keeper) SetProfile(ctx sdk.Context, msg MsgSetProfile) (sdk.Tags, sdk.Error) {
+	return k.authorize(ctx, msg.Ident, msg.Sender, msg)
+}
+
+func (k Keeper) setProfile(ctx sdk.Context, msg MsgSetProfile) (sdk.Tags, sdk.Error) {
+	store := ctx.KVStore(k.storeKey)
+	if msg.Profile.IsEmpty() {
+		store.Delete(KeyProfile(msg.Ident))
+		return nil, nil
+	}
+	profile := msg.Profile
+	profile.Address = msg.Ident
+	profile.UpdatedAt = ctx.BlockHeader().Time.Unix()
+	store.Set(KeyProfile(msg.Ident), k.cdc.MustMarshalBinary(profile))
+	return sdk.NewTags(TagIdent, []byte(msg.Ident.String())), nil
+}
+
+// GetProfile ...
+func (k Keeper) GetProfile(ctx sdk.Context, id sdk.AccAddress) (profile Profile, found bool) {
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(KeyProfile(id))
+	if bz == nil {
+		return
+	}
+	k.cdc.MustUnmarshalBinary(bz, &profile)
+	return profile, true
+}
+
+// UnmarshalProfile ...
+func UnmarshalProfile(cdc *wire.Codec, value []byte) (profile Profile, err error) {
+	err = cdc.UnmarshalBinary(value, &profile)
+	return
+}
@@ -0,0 +1,203 @@
+package identity
+
+import (
+	"bytes"
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// RecoveryConfig names the guardians of an identity, a quorum of them can
+// add an owner once the delay elapsed without a veto of the owners. The
+// recovery sets the approval threshold, the lost owners would otherwise keep
+// the identity locked.
+type RecoveryConfig struct {
+	Guardians    []sdk.AccAddress `json:"guardians"`
+	Quorum       int64            `json:"quorum"`
+	Delay        int64            `json:"delay"`         // seconds between the quorum and the recovery
+	Threshold    int64            `json:"threshold"`     // threshold after the recovery, 0 resets it to 1
+	RemoveOwners bool             `json:"remove_owners"` // the new owner replaces the others
+}
+
+// ValidateBasic quick validity check
+func (c RecoveryConfig) ValidateBasic() sdk.Error {
+	if len(c.Guardians) == 0 && c.Quorum != 0 {
+		return sdk.NewError(DefaultCodespace, CodeInvalidRecovery, "quorum without guardians")
+	}
+	if len(c.Guardians) > 0 && (c.Quorum < 1 || c.Quorum > int64(len(c.Guardians))) {
+		return sdk.NewError(DefaultCodespace, CodeInvalidRecovery, fmt.Sprintf("quorum must be between 1 and %d", len(c.Guardians)))
+	}
+	if c.Delay < 0 {
+		return sdk.NewError(DefaultCodespace, CodeInvalidRecovery, "negative delay")
+	}
+	if c.Threshold < 0 {
+		return sdk.NewError(DefaultCodespace, CodeInvalidRecovery, "negative threshold")
+	}
+	if c.RemoveOwners && c.Threshold > 1 {
+		return sdk.NewError(DefaultCodespace, CodeInvalidRecovery, "the new owner alone can not reach the threshold")
+	}
+	for i, guardian := range c.Guardians {
+		if len(guardian) == 0 {
+			return sdk.NewError(DefaultCodespace, CodeInvalidInput, "nil guardian address")
+		}
+		for _, other := range c.Guardians[:i] {
+			if bytes.Equal(guardian, other) {
+				return sdk.NewError(DefaultCodespace, CodeInvalidRecovery, fmt.Sprintf("duplicate guardian %s", guardian))
+			}
+		}
+	}
+	return nil
+}
+
+// IsGuardian ...
+func (c RecoveryConfig) IsGuardian(addr sdk.AccAddress) bool {
+	for _, guardian := range c.Guardians {
+		if bytes.Equal(guardian, addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Recovery is the recovery of an identity in progress
+type Recovery struct {
+	Ident        sdk.AccAddress   `json:"ident"`
+	NewOwner     sdk.AccAddress   `json:"new_owner"`
+	Approvals    []sdk.AccAddress `json:"approvals"` // guardians supporting the recovery
+	InitiatedAt  int64            `json:"initiated_at"`
+	QuorumAt     int64            `json:"quorum_at"`     // 0 until the quorum is reached
+	ExecutableAt int64            `json:"executable_at"` // 0 until the quorum is reached
+}
+
+// SetGuardians replace the recovery config of an identity, it needs the
+// approval of the owners like any sensitive action
+func (k Keeper) SetGuardians(ctx sdk.Context, msg MsgSetGuardians) (sdk.Tags, sdk.Error) {
+	return k.authorize(ctx, msg.Ident, msg.Sender, msg)
+}
+
+func (k Keeper) setGuardians(ctx sdk.Context, msg MsgSetGuardians) (sdk.Tags, sdk.Error) {
+	store := ctx.KVStore(k.storeKey)
+	store.Set(KeyRecoveryConfig(msg.Ident), k.cdc.MustMarshalBinary(msg.Config))
+	// a recovery started by the previous guardians is void
+	store.Delete(KeyRecovery(msg.Ident))
+	return nil, nil
+}
+
+// GetRecoveryConfig ...
+func (k Keeper) GetRecoveryConfig(ctx sdk.Context, id sdk.AccAddress) (config RecoveryConfig) {
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(KeyRecoveryConfig(id))
+	if bz == nil {
+		return
+	}
+	k.cdc.MustUnmarshalBinary(bz, &config)
+	return
+}
+
+// GetRecovery returns the recovery in progress of an identity
+func (k Keeper) GetRecovery(ctx sdk.Context, id sdk.AccAddress) (recovery Recovery, found bool) {
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(KeyRecovery(id))
+	if bz == nil {
+		return
+	}
+	k.cdc.MustUnmarshalBinary(bz, &recovery)
+	return recovery, true
+}
+
+func (k Keeper) setRecovery(ctx sdk.Context, recovery Recovery) {
+	store := ctx.KVStore(k.storeKey)
+	store.Set(KeyRecovery(recovery.Ident), k.cdc.MustMarshalBinary(recovery))
+}
+
+// Recover starts or supports the recovery of an identity by a guardian,
+// the delay starts when the quorum of guardians is reached
+func (k Keeper) Recover(ctx sdk.Context, msg MsgRecover) (sdk.Tags, sdk.Error) {
+	config := k.GetRecoveryConfig(ctx, msg.Ident)
+	if !config.IsGuardian(msg.Sender) {
+		return nil, sdk.ErrUnauthorized(fmt.Sprintf("addr %s is not a guardian of %s", msg.Sender, msg.Ident))
+	}
+	if k.hasOwner(ctx, msg.Ident, msg.NewOwner) {
+		return nil, sdk.NewError(k.codespace, CodeInvalidRecovery, fmt.Sprintf("addr %s is already an owner", msg.NewOwner))
+	}
+	now := ctx.BlockHeader().Time.Unix()
+	recovery, found := k.GetRecovery(ctx, msg.Ident)
+	if !found {
+		recovery = Recovery{
+			Ident:       msg.Ident,
+			NewOwner:    msg.NewOwner,
+			Approvals:   []sdk.AccAddress{},
+			InitiatedAt: now,
+		}
+	}
+	if !bytes.Equal(recovery.NewOwner, msg.NewOwner) {
+		return nil, sdk.NewError(k.codespace, CodeInvalidRecovery, fmt.Sprintf("a recovery to %s is in progress", recovery.NewOwner))
+	}
+	for _, approval := range recovery.Approvals {
+		if bytes.Equal(approval, msg.Sender) {
+			return nil, sdk.NewError(k.codespace, CodeInvalidRecovery, fmt.Sprintf("recovery already approved by %s", msg.Sender))
+		}
+	}
+	recovery.Approvals = append(recovery.Approvals, msg.Sender)
+	if recovery.QuorumAt == 0 && int64(len(recovery.Approvals)) >= config.Quorum {
+		recovery.QuorumAt = now
+		recovery.ExecutableAt = now + config.Delay
+	}
+	k.setRecovery(ctx, recovery)
+	return sdk.NewTags(TagIdent, []byte(msg.Ident.String())), nil
+}
+
+// VetoRecovery cancels the recovery in progress, any owner can veto
+func (k Keeper) VetoRecovery(ctx sdk.Context, msg MsgVetoRecovery) (sdk.Tags, sdk.Error) {
+	if !k.hasOwner(ctx, msg.Ident, msg.Sender) {
+		return nil, sdk.ErrUnauthorized(fmt.Sprintf("addr %s unauthorized", msg.Sender))
+	}
+	if _, found := k.GetRecovery(ctx, msg.Ident); !found {
+		return nil, ErrRecoveryNotFound(k.codespace, msg.Ident)
+	}
+	ctx.KVStore(k.storeKey).Delete(KeyRecovery(msg.Ident))
+	return sdk.NewTags(TagIdent, []byte(msg.Ident.String())), nil
+}
+
+// FinalizeRecovery adds the new owner once the delay elapsed, any guardian
+// or the new owner can finalize. The other owners are removed when the config
+// says so, and the threshold is set to the one of the config, capped by the
+// owners left.
+func (k Keeper) FinalizeRecovery(ctx sdk.Context, msg MsgFinalizeRecovery) (sdk.Tags, sdk.Error) {
+	recovery, found := k.GetRecovery(ctx, msg.Ident)
+	if !found {
+		return nil, ErrRecoveryNotFound(k.codespace, msg.Ident)
+	}
+	if !bytes.Equal(msg.Sender, recovery.NewOwner) && !k.GetRecoveryConfig(ctx, msg.Ident).IsGuardian(msg.Sender) {
+		return nil, sdk.ErrUnauthorized(fmt.Sprintf("addr %s unauthorized", msg.Sender))
+	}
+	if recovery.QuorumAt == 0 {
+		return nil, sdk.NewError(k.codespace, CodeInvalidRecovery, "the quorum of guardians is not reached")
+	}
+	if ctx.BlockHeader().Time.Unix() < recovery.ExecutableAt {
+		return nil, sdk.NewError(k.codespace, CodeInvalidRecovery, fmt.Sprintf("the recovery is executable at %d", recovery.ExecutableAt))
+	}
+	config := k.GetRecoveryConfig(ctx, msg.Ident)
+	ctx.KVStore(k.storeKey).Delete(KeyRecovery(msg.Ident))
+	tags, err := k.addOwner(ctx, MsgAddOwner{Sender: msg.Sender, Ident: msg.Ident, Owner: recovery.NewOwner})
+	if err != nil {
+		return nil, err
+	}
+	if config.RemoveOwners {
+		for _, owner := range k.GetOwners(ctx, msg.Ident) {
+			if !bytes.Equal(owner, recovery.NewOwner) {
+				k.delOwner(ctx, msg.Ident, owner)
+			}
+		}
+		k.setOwnerCount(ctx, msg.Ident, 1)
+	}
+	threshold := config.Threshold
+	if threshold < 1 {
+		threshold = 1
+	}
+	if owners := k.getOwnerCount(ctx, msg.Ident); threshold > owners {
+		threshold = owners
+	}
+	k.setThreshold(ctx, msg.Ident, threshold)
+	return sdk.NewTags(TagIdent, []byte(msg.Ident.String())).AppendTags(tags), nil
+}
@@ -0,0 +1,109 @@
+package identity
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+)
+
+// MaxCertSchemaSize is the largest schema a certifier can register
+const MaxCertSchemaSize = 16 * 1024
+
+// CertSchema is the JSON schema of the data of the certs a certifier
+// issues for a property
+type CertSchema struct {
+	Certifier sdk.AccAddress `json:"certifier"`
+	Property  string         `json:"property"`
+	Schema    Metadata       `json:"schema"`
+	UpdatedAt int64          `json:"updated_at"`
+}
+
+// ValidateBasic quick validity check, an empty schema removes it
+func (s CertSchema) ValidateBasic() sdk.Error {
+	if len(s.Property) == 0 {
+		return sdk.NewError(DefaultCodespace, CodeInvalidInput, "nil property")
+	}
+	if len(s.Schema) == 0 {
+		return nil
+	}
+	if len(s.Schema) > MaxCertSchemaSize {
+		return ErrInvalidSchema(DefaultCodespace, s.Property, fmt.Sprintf("larger than %d bytes", MaxCertSchemaSize))
+	}
+	if _, err := ParseJSONSchema(s.Schema); err != nil {
+		return ErrInvalidSchema(DefaultCodespace, s.Property, err.Error())
+	}
+	return nil
+}
+
+// SetCertSchema register the schema of a property, once the owners of the
+// certifier approved it
+func (k Keeper) SetCertSchema(ctx sdk.Context, msg MsgSetCertSchema) (sdk.Tags, sdk.Error) {
+	return k.authorize(ctx, msg.Issuer, msg.Sender, msg)
+}
+
+func (k Keeper) setCertSchema(ctx sdk.Context, msg MsgSetCertSchema) (sdk.Tags, sdk.Error) {
+	store := ctx.KVStore(k.storeKey)
+	key := KeyCertSchema(msg.Issuer, msg.Property)
+	if len(msg.Schema) == 0 {
+		store.Delete(key)
+	} else {
+		schema := CertSchema{
+			Certifier: msg.Issuer,
+			Property:  msg.Property,
+			Schema:    msg.Schema,
+			UpdatedAt: ctx.BlockHeader().Time.Unix(),
+		}
+		store.Set(key, k.cdc.MustMarshalBinary(schema))
+	}
+	return sdk.NewTags(TagIdent, []byte(msg.Issuer.String())), nil
+}
+
+// GetCertSchema ...
+func (k Keeper) GetCertSchema(ctx sdk.Context, certifier sdk.AccAddress, property string) (schema CertSchema, found bool) {
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(KeyCertSchema(certifier, property))
+	if bz == nil {
+		return
+	}
+	k.cdc.MustUnmarshalBinary(bz, &schema)
+	return schema, true
+}
+
+// GetCertSchemas returns all schemas registered by a certifier
+func (k Keeper) GetCertSchemas(ctx sdk.Context, certifier sdk.AccAddress) []CertSchema {
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, KeyCertSchemas(certifier))
+	schemas := []CertSchema{}
+	for ; iterator.Valid(); iterator.Next() {
+		schema := CertSchema{}
+		k.cdc.MustUnmarshalBinary(iterator.Value(), &schema)
+		schemas = append(schemas, schema)
+	}
+	iterator.Close()
+	return schemas
+}
+
+// validateCertData checks the data of a cert against the schema its
+// certifier registered for the property, if any
+func (k Keeper) validateCertData(ctx sdk.Context, certifier sdk.AccAddress, value CertValue) sdk.Error {
+	schema, found := k.GetCertSchema(ctx, certifier, value.Property)
+	if !found {
+		return nil
+	}
+	// the schema was checked when it was registered
+	s, err := ParseJSONSchema(schema.Schema)
+	if err != nil {
+		return ErrInvalidSchema(k.codespace, value.Property, err.Error())
+	}
+	if err := s.ValidateJSON(value.Data); err != nil {
+		return ErrInvalidCertData(k.codespace, value.Property, err.Error())
+	}
+	return nil
+}
+
+// UnmarshalCertSchema ...
+func UnmarshalCertSchema(cdc *wire.Codec, value []byte) (schema CertSchema, err error) {
+	err = cdc.UnmarshalBinary(value, &schema)
+	return
+}
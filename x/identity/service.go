@@ -0,0 +1,49 @@
+package identity
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// AddService add or replace a service endpoint of an identity
+func (k Keeper) AddService(ctx sdk.Context, msg MsgAddService) (sdk.Tags, sdk.Error) {
+	return k.authorize(ctx, msg.Ident, msg.Sender, msg)
+}
+
+func (k Keeper) addService(ctx sdk.Context, msg MsgAddService) (sdk.Tags, sdk.Error) {
+	k.setService(ctx, msg.Ident, msg.Service)
+	return nil, nil
+}
+
+// DeleteService delete a service endpoint of an identity
+func (k Keeper) DeleteService(ctx sdk.Context, msg MsgDelService) (sdk.Tags, sdk.Error) {
+	return k.authorize(ctx, msg.Ident, msg.Sender, msg)
+}
+
+func (k Keeper) deleteService(ctx sdk.Context, msg MsgDelService) (sdk.Tags, sdk.Error) {
+	store := ctx.KVStore(k.storeKey)
+	key := KeyService(msg.Ident, msg.ServiceID)
+	if !store.Has(key) {
+		return nil, ErrServiceNotFound(k.codespace, msg.Ident, msg.ServiceID)
+	}
+	store.Delete(key)
+	return nil, nil
+}
+
+func (k Keeper) setService(ctx sdk.Context, id sdk.AccAddress, service Service) {
+	store := ctx.KVStore(k.storeKey)
+	store.Set(KeyService(id, service.ID), k.cdc.MustMarshalBinary(service))
+}
+
+// GetServices returns the service endpoints of an identity sorted by id
+func (k Keeper) GetServices(ctx sdk.Context, id sdk.AccAddress) []Service {
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, KeyServices(id))
+	services := []Service{}
+	for ; iterator.Valid(); iterator.Next() {
+		var service Service
+		k.cdc.MustUnmarshalBinary(iterator.Value(), &service)
+		services = append(services, service)
+	}
+	iterator.Close()
+	return services
+}
@@ -0,0 +1,17 @@
+package identity
+
+const (
+	// TagIdent ...
+	TagIdent = "ident"
+	// TagAction is ActionPending or ActionExecuted for the actions that
+	// need the approval of the owners
+	TagAction = "action"
+	// TagActionID ...
+	TagActionID = "action_id"
+)
+
+// Outcomes of an action of the owners
+const (
+	ActionPending  = "pending"
+	ActionExecuted = "executed"
+)
@@ -7,7 +7,6 @@ import (
 	"strconv"
 	"testing"
 
-	"github.com/icheckteam/ichain/types"
 	"github.com/stretchr/testify/require"
 
 	abci "github.com/tendermint/tendermint/abci/types"
@@ -47,9 +46,9 @@ func makeTestCodec() *wire.Codec {
 	bank.RegisterWire(cdc)
 	RegisterWire(cdc)
 
-	// Register AppAccount
+	// Register BaseAccount, the app types import this package through x/asset
 	cdc.RegisterInterface((*auth.Account)(nil), nil)
-	cdc.RegisterConcrete(&types.AppAccount{}, "test/asset/Account", nil)
+	cdc.RegisterConcrete(&auth.BaseAccount{}, "test/asset/Account", nil)
 	wire.RegisterCrypto(cdc)
 
 	return cdc
@@ -71,7 +70,7 @@ func createTestInput(t *testing.T, isCheckTx bool, initCoins int64) (sdk.Context
 	accountMapper := auth.NewAccountMapper(
 		cdc,                   // amino codec
 		keyMain,               // target store
-		types.ProtoAppAccount, // prototype
+		auth.ProtoBaseAccount, // prototype
 	)
 	assetKeeper := NewKeeper(keyIdentity, cdc)
 	return ctx, accountMapper, assetKeeper
@@ -0,0 +1,151 @@
+package identity
+
+import (
+	"bytes"
+	"math"
+	"sort"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Bounds of ComputeTrust, the paths collected and the edges followed, so
+// a dense graph can't make a search exponential
+const (
+	MaxTrustPaths  = 100
+	MaxTrustVisits = 10000
+)
+
+// TrustGraph returns the accounts directly trusted by an account
+type TrustGraph func(trustor sdk.AccAddress) ([]sdk.AccAddress, error)
+
+// TrustPath is a chain of trust from the trustor to the trusting account,
+// both included
+type TrustPath []sdk.AccAddress
+
+// TrustResult tells whether and how strongly an account trusts another
+type TrustResult struct {
+	Trustor  sdk.AccAddress `json:"trustor"`
+	Trusting sdk.AccAddress `json:"trusting"`
+	Trusted  bool           `json:"trusted"`
+	Score    float64        `json:"score"`
+	Paths    []TrustPath    `json:"paths"`
+
+	// Truncated is set when a bound stopped the search, the score is then
+	// a lower bound
+	Truncated bool `json:"truncated"`
+}
+
+// CachedTrustGraph memoizes the edges of a graph, a cached graph can be
+// shared by several ComputeTrust calls over the same state
+func CachedTrustGraph(graph TrustGraph) TrustGraph {
+	cache := map[string][]sdk.AccAddress{}
+	return func(trustor sdk.AccAddress) ([]sdk.AccAddress, error) {
+		if trusting, ok := cache[string(trustor)]; ok {
+			return trusting, nil
+		}
+		trusting, err := graph(trustor)
+		if err != nil {
+			return nil, err
+		}
+		cache[string(trustor)] = trusting
+		return trusting, nil
+	}
+}
+
+// ComputeTrust finds the trust paths of up to maxHops edges from trustor to
+// trusting, following at most MaxTrustVisits edges. A path of n hops has the strength decay^(n-1) and the paths
+// are combined as independent evidence, score = 1 - Π(1 - strength).
+// Every account trusts itself with a score of 1.
+func ComputeTrust(graph TrustGraph, trustor, trusting sdk.AccAddress, maxHops int, decay float64) (TrustResult, error) {
+	result := TrustResult{
+		Trustor:  trustor,
+		Trusting: trusting,
+		Paths:    []TrustPath{},
+	}
+	if bytes.Equal(trustor, trusting) {
+		result.Trusted = true
+		result.Score = 1
+		result.Paths = append(result.Paths, TrustPath{trustor})
+		return result, nil
+	}
+
+	path := TrustPath{trustor}
+	visits := 0
+	var walk func() error
+	walk = func() error {
+		if result.Truncated || len(path) > maxHops {
+			return nil
+		}
+		edges, err := graph(path[len(path)-1])
+		if err != nil {
+			return err
+		}
+		for _, next := range edges {
+			if visits++; visits > MaxTrustVisits {
+				result.Truncated = true
+				return nil
+			}
+			if bytes.Equal(next, trusting) {
+				found := make(TrustPath, len(path), len(path)+1)
+				copy(found, path)
+				result.Paths = append(result.Paths, append(found, next))
+				if len(result.Paths) >= MaxTrustPaths {
+					result.Truncated = true
+					return nil
+				}
+				continue
+			}
+			if path.contains(next) {
+				continue
+			}
+			path = append(path, next)
+			if err := walk(); err != nil {
+				return err
+			}
+			path = path[:len(path)-1]
+		}
+		return nil
+	}
+	if err := walk(); err != nil {
+		return result, err
+	}
+
+	sort.SliceStable(result.Paths, func(i, j int) bool {
+		return len(result.Paths[i]) < len(result.Paths[j])
+	})
+	distrust := 1.0
+	for _, p := range result.Paths {
+		distrust *= 1 - math.Pow(decay, float64(len(p)-2))
+	}
+	result.Score = 1 - distrust
+	result.Trusted = len(result.Paths) > 0
+	return result, nil
+}
+
+func (p TrustPath) contains(addr sdk.AccAddress) bool {
+	for _, a := range p {
+		if bytes.Equal(a, addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetTrusting returns the accounts directly trusted by trustor
+func (k Keeper) GetTrusting(ctx sdk.Context, trustor sdk.AccAddress) []sdk.AccAddress {
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, KeyTrusts(trustor))
+	trusting := []sdk.AccAddress{}
+	for ; iterator.Valid(); iterator.Next() {
+		trusting = append(trusting, TrustingFromKey(trustor, iterator.Key()))
+	}
+	iterator.Close()
+	return trusting
+}
+
+// TrustGraph returns the trust graph of the current state
+func (k Keeper) TrustGraph(ctx sdk.Context) TrustGraph {
+	return func(trustor sdk.AccAddress) ([]sdk.AccAddress, error) {
+		return k.GetTrusting(ctx, trustor), nil
+	}
+}
@@ -0,0 +1,79 @@
+package identity
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mapTrustGraph(edges map[string][]sdk.AccAddress) TrustGraph {
+	return func(trustor sdk.AccAddress) ([]sdk.AccAddress, error) {
+		return edges[string(trustor)], nil
+	}
+}
+
+func TestComputeTrust(t *testing.T) {
+	a, b, c, d := addrs[0], addrs[1], addrs[2], addrs[3]
+	graph := mapTrustGraph(map[string][]sdk.AccAddress{
+		string(a): {b, c},
+		string(b): {c, d},
+		string(c): {a, d},
+	})
+
+	result, err := ComputeTrust(graph, a, a, 3, 0.5)
+	require.Nil(t, err)
+	assert.True(t, result.Trusted)
+	assert.Equal(t, 1.0, result.Score)
+
+	result, _ = ComputeTrust(graph, a, b, 3, 0.5)
+	assert.Equal(t, 1.0, result.Score)
+	require.Equal(t, 1, len(result.Paths))
+
+	// a -> b -> d and a -> c -> d, then a -> b -> c -> d
+	result, _ = ComputeTrust(graph, a, d, 3, 0.5)
+	require.Equal(t, 3, len(result.Paths))
+	assert.Equal(t, TrustPath{a, b, d}, result.Paths[0])
+	assert.Equal(t, TrustPath{a, b, c, d}, result.Paths[2])
+	assert.InDelta(t, 1-0.5*0.5*0.75, result.Score, 1e-9)
+
+	result, _ = ComputeTrust(graph, a, d, 1, 0.5)
+	assert.False(t, result.Trusted)
+	assert.Equal(t, 0.0, result.Score)
+
+	result, _ = ComputeTrust(graph, d, a, 3, 0.5)
+	assert.False(t, result.Trusted)
+}
+
+func TestComputeTrustBudget(t *testing.T) {
+	// everyone trusts everyone, the target is out of reach
+	var nodes []sdk.AccAddress
+	for i := 0; i < 12; i++ {
+		nodes = append(nodes, sdk.AccAddress([]byte{byte(i)}))
+	}
+	edges := 0
+	graph := func(trustor sdk.AccAddress) ([]sdk.AccAddress, error) {
+		edges += len(nodes)
+		return nodes, nil
+	}
+	result, err := ComputeTrust(graph, nodes[0], addrs[0], 6, 0.5)
+	require.Nil(t, err)
+	assert.False(t, result.Trusted)
+	assert.True(t, result.Truncated)
+	assert.True(t, edges <= MaxTrustVisits+len(nodes))
+}
+
+func TestGetTrusting(t *testing.T) {
+	ctx, _, keeper := createTestInput(t, false, 0)
+	keeper.SetTrust(ctx, addrs[1], addrs[2])
+	keeper.SetTrust(ctx, addrs[1], addrs[3])
+	keeper.SetTrust(ctx, addrs[2], addrs[3])
+
+	trusting := keeper.GetTrusting(ctx, addrs[1])
+	require.Equal(t, 2, len(trusting))
+
+	result, err := ComputeTrust(keeper.TrustGraph(ctx), addrs[1], addrs[3], 2, 0.5)
+	require.Nil(t, err)
+	assert.Equal(t, 2, len(result.Paths))
+}
@@ -2,6 +2,8 @@ package identity
 
 import (
 	"errors"
+	"net/url"
+	"strings"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/wire"
@@ -9,11 +11,43 @@ import (
 
 // Cert ...
 type Cert struct {
-	Property  string         `json:"property"`
-	Certifier sdk.AccAddress `json:"certifier"`
-	Owner     sdk.AccAddress `json:"owner"`
-	Data      Metadata       `json:"data"`
-	CreatedAt int64          `json:"created_at"`
+	Property     string         `json:"property"`
+	Certifier    sdk.AccAddress `json:"certifier"`
+	Owner        sdk.AccAddress `json:"owner"`
+	Data         Metadata       `json:"data"`
+	CreatedAt    int64          `json:"created_at"`
+	ExpiresAt    int64          `json:"expires_at"` // 0 if the cert never expires
+	Revoked      bool           `json:"revoked"`
+	RevokedAt    int64          `json:"revoked_at"`
+	RevokeReason string         `json:"revoke_reason"`
+}
+
+// Status of a cert at a given time
+const (
+	CertStatusValid    = "valid"
+	CertStatusPending  = "pending" // not issued yet
+	CertStatusExpired  = "expired"
+	CertStatusRevoked  = "revoked"
+	CertStatusNotFound = "not_found"
+)
+
+// StatusAt returns the status of the cert at the unix time t
+func (c Cert) StatusAt(t int64) string {
+	switch {
+	case t < c.CreatedAt:
+		return CertStatusPending
+	case c.Revoked && t >= c.RevokedAt:
+		return CertStatusRevoked
+	case c.ExpiresAt > 0 && t >= c.ExpiresAt:
+		return CertStatusExpired
+	default:
+		return CertStatusValid
+	}
+}
+
+// IsValidAt returns true if the cert was issued, not expired and not revoked at t
+func (c Cert) IsValidAt(t int64) bool {
+	return c.StatusAt(t) == CertStatusValid
 }
 
 // CertValue ...
@@ -22,6 +56,7 @@ type CertValue struct {
 	Property   string         `json:"property"`
 	Data       Metadata       `json:"data"`
 	Confidence bool           `json:"confidence"`
+	ExpiresAt  int64          `json:"expires_at"`
 }
 
 // ValidateBasic quick validity check
@@ -33,6 +68,64 @@ func (msg CertValue) ValidateBasic() sdk.Error {
 	if len(msg.Owner) == 0 {
 		return sdk.NewError(DefaultCodespace, CodeInvalidInput, "nil owner address")
 	}
+
+	if msg.ExpiresAt < 0 {
+		return sdk.NewError(DefaultCodespace, CodeInvalidInput, "negative expires_at")
+	}
+	return nil
+}
+
+// Revocation records the revocation of a cert, it stays in the revocation
+// list even if the cert is issued again
+type Revocation struct {
+	Owner         sdk.AccAddress `json:"owner"`
+	Property      string         `json:"property"`
+	Certifier     sdk.AccAddress `json:"certifier"`
+	Reason        string         `json:"reason"`
+	RevokedAt     int64          `json:"revoked_at"`
+	CertCreatedAt int64          `json:"cert_created_at"`
+}
+
+// CertRevocation is a cert to revoke with the reason of the revocation
+type CertRevocation struct {
+	Owner    sdk.AccAddress `json:"owner"`
+	Property string         `json:"property"`
+	Reason   string         `json:"reason"`
+}
+
+// ValidateBasic quick validity check
+func (r CertRevocation) ValidateBasic() sdk.Error {
+	if len(r.Property) == 0 {
+		return sdk.NewError(DefaultCodespace, CodeInvalidInput, "nil property address")
+	}
+	if len(r.Owner) == 0 {
+		return sdk.NewError(DefaultCodespace, CodeInvalidInput, "nil owner address")
+	}
+	return nil
+}
+
+// Service is a service endpoint of an identity, published in its DID document
+type Service struct {
+	ID       string `json:"id"` // fragment of the service in the DID document
+	Type     string `json:"type"`
+	Endpoint string `json:"endpoint"`
+}
+
+// ValidateBasic quick validity check
+func (s Service) ValidateBasic() sdk.Error {
+	if len(s.ID) == 0 {
+		return sdk.NewError(DefaultCodespace, CodeInvalidInput, "empty service id")
+	}
+	if strings.ContainsAny(s.ID, "#?/; ") {
+		return sdk.NewError(DefaultCodespace, CodeInvalidInput, "invalid service id")
+	}
+	if len(s.Type) == 0 {
+		return sdk.NewError(DefaultCodespace, CodeInvalidInput, "empty service type")
+	}
+	u, err := url.Parse(s.Endpoint)
+	if err != nil || u.Scheme == "" {
+		return sdk.NewError(DefaultCodespace, CodeInvalidInput, "service endpoint must be an absolute URI")
+	}
 	return nil
 }
 
@@ -64,3 +157,9 @@ func UnmarshalCert(cdc *wire.Codec, value []byte) (cert Cert, err error) {
 	err = cdc.UnmarshalBinary(value, &cert)
 	return
 }
+
+// UnmarshalRevocation ...
+func UnmarshalRevocation(cdc *wire.Codec, value []byte) (revocation Revocation, err error) {
+	err = cdc.UnmarshalBinary(value, &revocation)
+	return
+}
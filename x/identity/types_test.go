@@ -2,8 +2,10 @@ package identity
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -21,3 +23,66 @@ func TestMetadata(t *testing.T) {
 	}
 	assert.Equal(t, string(newMeta), string(metadata))
 }
+
+func TestCertStatusAt(t *testing.T) {
+	cert := Cert{CreatedAt: 100, ExpiresAt: 200}
+	assert.Equal(t, CertStatusPending, cert.StatusAt(99))
+	assert.Equal(t, CertStatusValid, cert.StatusAt(100))
+	assert.Equal(t, CertStatusExpired, cert.StatusAt(200))
+
+	cert = Cert{CreatedAt: 100, Revoked: true, RevokedAt: 150}
+	assert.True(t, cert.IsValidAt(149))
+	assert.Equal(t, CertStatusRevoked, cert.StatusAt(150))
+	assert.Equal(t, CertStatusRevoked, cert.StatusAt(1000))
+}
+
+func TestParseDID(t *testing.T) {
+	addr := sdk.AccAddress([]byte("addr1"))
+	did := DID(addr)
+	assert.Equal(t, "did:ichain:"+addr.String(), did)
+
+	parsed, err := ParseDID(did + "#key-1")
+	assert.Nil(t, err)
+	assert.Equal(t, addr, parsed)
+
+	_, err = ParseDID("did:example:" + addr.String())
+	assert.NotNil(t, err)
+	_, err = ParseDID("did:ichain:notbech32")
+	assert.NotNil(t, err)
+}
+
+func TestRecoveryConfigValidateBasic(t *testing.T) {
+	a, b := sdk.AccAddress([]byte("addr1")), sdk.AccAddress([]byte("addr2"))
+	assert.Nil(t, RecoveryConfig{}.ValidateBasic())
+	assert.Nil(t, RecoveryConfig{Guardians: []sdk.AccAddress{a, b}, Quorum: 2, Delay: 3600}.ValidateBasic())
+	assert.NotNil(t, RecoveryConfig{Quorum: 1}.ValidateBasic())
+	assert.NotNil(t, RecoveryConfig{Guardians: []sdk.AccAddress{a, b}, Quorum: 3}.ValidateBasic())
+	assert.NotNil(t, RecoveryConfig{Guardians: []sdk.AccAddress{a, a}, Quorum: 1}.ValidateBasic())
+	assert.NotNil(t, RecoveryConfig{Guardians: []sdk.AccAddress{a}, Quorum: 1, Delay: -1}.ValidateBasic())
+}
+
+func TestProfileValidateBasic(t *testing.T) {
+	valid := Profile{
+		Name:      "Acme Coffee",
+		LogoHash:  "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+		Website:   "https://acme.example.com",
+		Country:   "VN",
+		GS1Prefix: "8935049",
+	}
+	assert.Nil(t, valid.ValidateBasic())
+	assert.Nil(t, Profile{}.ValidateBasic())
+
+	invalid := []Profile{
+		{Name: strings.Repeat("a", MaxProfileNameLength+1)},
+		{LogoHash: "9f86d081"},
+		{Website: "acme.example.com"},
+		{Website: "ftp://acme.example.com"},
+		{Country: "vn"},
+		{Country: "VNM"},
+		{GS1Prefix: "893"},
+		{GS1Prefix: "89350A9"},
+	}
+	for _, p := range invalid {
+		assert.NotNil(t, p.ValidateBasic(), "%+v", p)
+	}
+}
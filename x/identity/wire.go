@@ -11,6 +11,18 @@ func RegisterWire(cdc *wire.Codec) {
 	cdc.RegisterConcrete(MsgAddOwner{}, "identity/MsgAddOwner", nil)
 	cdc.RegisterConcrete(MsgReg{}, "identity/MsgReg", nil)
 	cdc.RegisterConcrete(MsgDelOwner{}, "identity/MsgDelOwner", nil)
+	cdc.RegisterConcrete(MsgRevokeCerts{}, "identity/MsgRevokeCerts", nil)
+	cdc.RegisterConcrete(MsgAddService{}, "identity/MsgAddService", nil)
+	cdc.RegisterConcrete(MsgDelService{}, "identity/MsgDelService", nil)
+	cdc.RegisterConcrete(MsgSetThreshold{}, "identity/MsgSetThreshold", nil)
+	cdc.RegisterConcrete(MsgApproveAction{}, "identity/MsgApproveAction", nil)
+	cdc.RegisterConcrete(MsgCancelAction{}, "identity/MsgCancelAction", nil)
+	cdc.RegisterConcrete(MsgSetGuardians{}, "identity/MsgSetGuardians", nil)
+	cdc.RegisterConcrete(MsgRecover{}, "identity/MsgRecover", nil)
+	cdc.RegisterConcrete(MsgVetoRecovery{}, "identity/MsgVetoRecovery", nil)
+	cdc.RegisterConcrete(MsgFinalizeRecovery{}, "identity/MsgFinalizeRecovery", nil)
+	cdc.RegisterConcrete(MsgSetProfile{}, "identity/MsgSetProfile", nil)
+	cdc.RegisterConcrete(MsgSetCertSchema{}, "identity/MsgSetCertSchema", nil)
 }
 
 // MsgCdc generic sealed codec to be used throughout sdk
@@ -1,6 +1,303 @@
 package mongo
 
-// Store store all data in mongodb to scale
-// ....
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/icheckteam/ichain/indexer"
+)
+
+// Collections written by the store
+const (
+	CollectionMeta       = "meta"
+	CollectionAssets     = "assets"
+	CollectionIdentities = "identities"
+	CollectionHistories  = "histories"
+)
+
+const (
+	metaHeightID  = "height"
+	metaPendingID = "pending"
+)
+
+// Store store all data in mongodb to scale the read traffic,
+// the documents keep the indexed fields next to the amino JSON of the read model
 type Store struct {
+	session *mgo.Session
+	db      string
+	cdc     *wire.Codec
+}
+
+var _ indexer.Store = &Store{}
+
+type metaDoc struct {
+	ID      string `bson:"_id"`
+	Height  int64  `bson:"height"`
+	Changes string `bson:"changes,omitempty"`
+}
+
+type assetDoc struct {
+	ID        string   `bson:"_id"`
+	Owner     string   `bson:"owner"`
+	Reporters []string `bson:"reporters"`
+	Materials []string `bson:"materials"`
+	Parent    string   `bson:"parent"`
+	Created   int64    `bson:"created"`
+	Data      string   `bson:"data"`
+}
+
+type identityDoc struct {
+	ID   string `bson:"_id"`
+	Data string `bson:"data"`
+}
+
+type historyDoc struct {
+	ID       string `bson:"_id"`
+	AssetID  string `bson:"asset_id"`
+	Height   int64  `bson:"height"`
+	TxIndex  int    `bson:"tx_index"`
+	MsgIndex int    `bson:"msg_index"`
+	Data     string `bson:"data"`
+}
+
+// NewStore connects to the database at url and finishes the commit
+// interrupted by a previous run, if any
+func NewStore(url string, db string, cdc *wire.Codec) (*Store, error) {
+	session, err := mgo.Dial(url)
+	if err != nil {
+		return nil, err
+	}
+	s := &Store{session: session, db: db, cdc: cdc}
+	if err := s.ensureIndexes(); err != nil {
+		session.Close()
+		return nil, err
+	}
+	if err := s.recover(); err != nil {
+		session.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Store) c(name string) *mgo.Collection {
+	return s.session.DB(s.db).C(name)
+}
+
+func (s *Store) ensureIndexes() error {
+	for _, key := range []string{"owner", "reporters", "materials", "parent"} {
+		if err := s.c(CollectionAssets).EnsureIndexKey(key); err != nil {
+			return err
+		}
+	}
+	return s.c(CollectionHistories).EnsureIndexKey("asset_id", "height", "tx_index", "msg_index")
+}
+
+// recover applies the changes of a commit that did not complete
+func (s *Store) recover() error {
+	var pending metaDoc
+	err := s.c(CollectionMeta).FindId(metaPendingID).One(&pending)
+	if err == mgo.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var changes indexer.Changes
+	if err := s.cdc.UnmarshalJSON([]byte(pending.Changes), &changes); err != nil {
+		return err
+	}
+	return s.apply(pending.Height, changes)
+}
+
+// Height implements indexer.Store
+func (s *Store) Height() (int64, error) {
+	var meta metaDoc
+	err := s.c(CollectionMeta).FindId(metaHeightID).One(&meta)
+	if err == mgo.ErrNotFound {
+		return 0, nil
+	}
+	return meta.Height, err
+}
+
+// Commit implements indexer.Store. MongoDB has no multi document
+// transactions, the changes are journaled first so that an interrupted
+// commit is completed by the next NewStore.
+func (s *Store) Commit(height int64, changes indexer.Changes) error {
+	bz, err := s.cdc.MarshalJSON(changes)
+	if err != nil {
+		return err
+	}
+	_, err = s.c(CollectionMeta).UpsertId(metaPendingID, metaDoc{
+		ID:      metaPendingID,
+		Height:  height,
+		Changes: string(bz),
+	})
+	if err != nil {
+		return err
+	}
+	return s.apply(height, changes)
+}
+
+// apply writes the changes, every write is an idempotent upsert
+func (s *Store) apply(height int64, changes indexer.Changes) error {
+	for _, a := range changes.Assets {
+		doc, err := s.toAssetDoc(a)
+		if err != nil {
+			return err
+		}
+		if _, err := s.c(CollectionAssets).UpsertId(doc.ID, doc); err != nil {
+			return err
+		}
+	}
+	for _, ident := range changes.Identities {
+		bz, err := s.cdc.MarshalJSON(ident)
+		if err != nil {
+			return err
+		}
+		id := ident.Address.String()
+		if _, err := s.c(CollectionIdentities).UpsertId(id, identityDoc{ID: id, Data: string(bz)}); err != nil {
+			return err
+		}
+	}
+	for _, h := range changes.Histories {
+		bz, err := s.cdc.MarshalJSON(h)
+		if err != nil {
+			return err
+		}
+		doc := historyDoc{
+			ID:       historyID(h),
+			AssetID:  h.AssetID,
+			Height:   h.Height,
+			TxIndex:  h.TxIndex,
+			MsgIndex: h.MsgIndex,
+			Data:     string(bz),
+		}
+		if _, err := s.c(CollectionHistories).UpsertId(doc.ID, doc); err != nil {
+			return err
+		}
+	}
+	if _, err := s.c(CollectionMeta).UpsertId(metaHeightID, metaDoc{ID: metaHeightID, Height: height}); err != nil {
+		return err
+	}
+	return s.c(CollectionMeta).RemoveId(metaPendingID)
+}
+
+func (s *Store) toAssetDoc(a indexer.Asset) (assetDoc, error) {
+	bz, err := s.cdc.MarshalJSON(a)
+	if err != nil {
+		return assetDoc{}, err
+	}
+	doc := assetDoc{
+		ID:        a.ID,
+		Reporters: []string{},
+		Materials: []string{},
+		Parent:    a.Parent,
+		Created:   a.Created,
+		Data:      string(bz),
+	}
+	if len(a.Owner) > 0 {
+		doc.Owner = a.Owner.String()
+	}
+	for _, r := range a.Reporters {
+		doc.Reporters = append(doc.Reporters, r.Addr.String())
+	}
+	for _, m := range a.Materials {
+		doc.Materials = append(doc.Materials, m.RecordID)
+	}
+	return doc, nil
+}
+
+// GetAsset implements indexer.Store
+func (s *Store) GetAsset(id string) (a indexer.Asset, found bool, err error) {
+	var doc assetDoc
+	err = s.c(CollectionAssets).FindId(id).One(&doc)
+	if err == mgo.ErrNotFound {
+		return a, false, nil
+	}
+	if err != nil {
+		return
+	}
+	err = s.cdc.UnmarshalJSON([]byte(doc.Data), &a)
+	return a, err == nil, err
+}
+
+// GetIdentity implements indexer.Store
+func (s *Store) GetIdentity(addr sdk.AccAddress) (ident indexer.Identity, found bool, err error) {
+	var doc identityDoc
+	err = s.c(CollectionIdentities).FindId(addr.String()).One(&doc)
+	if err == mgo.ErrNotFound {
+		return ident, false, nil
+	}
+	if err != nil {
+		return
+	}
+	err = s.cdc.UnmarshalJSON([]byte(doc.Data), &ident)
+	return ident, err == nil, err
+}
+
+// History implements indexer.Store
+func (s *Store) History(assetID string) ([]indexer.History, error) {
+	docs := []historyDoc{}
+	err := s.c(CollectionHistories).
+		Find(bson.M{"asset_id": assetID}).
+		Sort("height", "tx_index", "msg_index").
+		All(&docs)
+	if err != nil {
+		return nil, err
+	}
+	histories := make([]indexer.History, len(docs))
+	for i, doc := range docs {
+		if err := s.cdc.UnmarshalJSON([]byte(doc.Data), &histories[i]); err != nil {
+			return nil, err
+		}
+	}
+	return histories, nil
+}
+
+// AssetsByOwner implements indexer.Store
+func (s *Store) AssetsByOwner(owner sdk.AccAddress) ([]indexer.Asset, error) {
+	return s.findAssets(bson.M{"owner": owner.String()})
+}
+
+// AssetsByReporter implements indexer.Store
+func (s *Store) AssetsByReporter(reporter sdk.AccAddress) ([]indexer.Asset, error) {
+	return s.findAssets(bson.M{"reporters": reporter.String()})
+}
+
+// AssetsByMaterial implements indexer.Store
+func (s *Store) AssetsByMaterial(materialID string) ([]indexer.Asset, error) {
+	return s.findAssets(bson.M{"materials": materialID})
+}
+
+// Children implements indexer.Store
+func (s *Store) Children(parentID string) ([]indexer.Asset, error) {
+	return s.findAssets(bson.M{"parent": parentID})
+}
+
+func (s *Store) findAssets(query bson.M) ([]indexer.Asset, error) {
+	docs := []assetDoc{}
+	if err := s.c(CollectionAssets).Find(query).Sort("created").All(&docs); err != nil {
+		return nil, err
+	}
+	assets := make([]indexer.Asset, len(docs))
+	for i, doc := range docs {
+		if err := s.cdc.UnmarshalJSON([]byte(doc.Data), &assets[i]); err != nil {
+			return nil, err
+		}
+	}
+	return assets, nil
+}
+
+// Close implements indexer.Store
+func (s *Store) Close() error {
+	s.session.Close()
+	return nil
+}
+
+func historyID(h indexer.History) string {
+	return fmt.Sprintf("%s:%d:%s", h.TxHash, h.MsgIndex, h.AssetID)
 }